@@ -15,11 +15,14 @@
 package configuration
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math"
 	"os"
 	"path"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
@@ -48,6 +51,10 @@ const (
 	// Testnet is Ravencoin Testnet3.
 	Testnet string = "TESTNET"
 
+	// Custom is a Ravencoin-derived network described
+	// by the file at CustomNetworkPathEnv.
+	Custom string = "CUSTOM"
+
 	// mainnetConfigPath is the path of the Ravencoin
 	// configuration file for mainnet.
 	mainnetConfigPath = "/app/ravencoin-mainnet.conf"
@@ -94,10 +101,157 @@ const (
 	// read to determine network.
 	NetworkEnv = "NETWORK"
 
+	// CustomNetworkPathEnv is the environment variable
+	// read to determine the path of the JSON file that
+	// describes the network when NETWORK is CUSTOM.
+	CustomNetworkPathEnv = "CUSTOM_NETWORK_PATH"
+
 	// PortEnv is the environment variable
 	// read to determine the port for the Rosetta
 	// implementation.
 	PortEnv = "PORT"
+
+	// RateLimitEnv is the environment variable
+	// read to determine the number of requests per
+	// second allowed from each client IP. If it is
+	// not populated, requests are not rate limited.
+	RateLimitEnv = "RATE_LIMIT"
+
+	// RateLimitBurstEnv is the environment variable
+	// read to determine the number of requests a
+	// client IP may make in a burst.
+	RateLimitBurstEnv = "RATE_LIMIT_BURST"
+
+	// RateLimitAllowListEnv is the environment variable
+	// read to determine the comma-separated IPs and CIDRs
+	// that are exempt from rate limiting.
+	RateLimitAllowListEnv = "RATE_LIMIT_ALLOWLIST"
+
+	// SyncStartHeightEnv is the environment variable
+	// read to determine the height the indexer begins
+	// syncing from. If it is not populated, the indexer
+	// syncs from genesis. If it is, ravend is run with
+	// txindex (and without pruning) so that coins created
+	// before this height can be fetched, so a data directory
+	// synced without txindex must be reindexed.
+	SyncStartHeightEnv = "SYNC_START_HEIGHT"
+
+	// ExcludeImmatureCoinbaseEnv is the environment
+	// variable read to determine if immature coinbase
+	// outputs are excluded from balances and coins.
+	ExcludeImmatureCoinbaseEnv = "EXCLUDE_IMMATURE_COINBASE"
+
+	// ConfirmationDepthEnv is the environment variable
+	// read to determine how many blocks below the best
+	// block the construction metadata replay block is
+	// selected. If it is not populated, no replay block
+	// is selected.
+	ConfirmationDepthEnv = "CONFIRMATION_DEPTH"
+
+	// ReplayProtectionDepthEnv is the environment variable
+	// read to override the number of blocks below the best
+	// block the replay block is selected at. If it is not
+	// populated, CONFIRMATION_DEPTH is used.
+	ReplayProtectionDepthEnv = "REPLAY_PROTECTION_DEPTH"
+
+	// PruneDepthEnv is the environment variable read to
+	// determine how many blocks below the best block the
+	// indexer keeps block data (and with it, the spent
+	// coins) for. It may not be less than CONFIRMATION_DEPTH.
+	// If it is not populated, indexed blocks are not pruned.
+	// It must be set before the indexer first syncs, as the
+	// outputs of unspent coins are only kept once it is.
+	PruneDepthEnv = "PRUNE_DEPTH"
+
+	// ScriptPubKeyCacheSizeEnv is the environment variable
+	// read to determine the number of coin ScriptPubKeys
+	// cached by /construction/metadata. If it is not
+	// populated, ScriptPubKeys are not cached.
+	ScriptPubKeyCacheSizeEnv = "SCRIPT_PUB_KEY_CACHE_SIZE"
+
+	// ScriptPubKeyCacheTTLEnv is the environment variable
+	// read to determine how long a cached ScriptPubKey
+	// is used (i.e. 10m).
+	ScriptPubKeyCacheTTLEnv = "SCRIPT_PUB_KEY_CACHE_TTL"
+
+	// AssetInfoCacheSizeEnv is the environment variable
+	// read to determine the number of assets whose metadata
+	// is cached by the indexer. If it is not populated,
+	// asset metadata is not cached.
+	AssetInfoCacheSizeEnv = "ASSET_INFO_CACHE_SIZE"
+
+	// AssetInfoCacheTTLEnv is the environment variable
+	// read to determine how long cached asset metadata
+	// is used (i.e. 10m).
+	AssetInfoCacheTTLEnv = "ASSET_INFO_CACHE_TTL"
+
+	// MaxServableHeightEnv is the environment variable
+	// read to determine the highest block that is indexed
+	// and served. If it is not populated, all blocks are
+	// indexed and served.
+	MaxServableHeightEnv = "MAX_SERVABLE_HEIGHT"
+
+	// AllowNonCanonicalAssetNamesEnv is the environment
+	// variable read to determine if construction accepts
+	// asset names that are not in their canonical casing.
+	AllowNonCanonicalAssetNamesEnv = "ALLOW_NONCANONICAL_ASSET_NAMES"
+
+	// AllowUnverifiedInputAmountsEnv is the environment
+	// variable read to determine if /construction/metadata
+	// accepts coins whose amount does not match the
+	// indexed value.
+	AllowUnverifiedInputAmountsEnv = "ALLOW_UNVERIFIED_INPUT_AMOUNTS"
+
+	// MaxFeeAmountEnv is the environment variable read
+	// to determine the largest fee (in Satoshis) that
+	// /construction/metadata will suggest.
+	MaxFeeAmountEnv = "MAX_FEE_AMOUNT"
+
+	// HDVersionBytesEnv is the environment variable read
+	// to register additional (i.e. SLIP-0132) HD version
+	// bytes. It is a comma-separated list of hex-encoded
+	// public:private pairs (i.e. 04b24746:04b2430c).
+	HDVersionBytesEnv = "HD_VERSION_BYTES"
+
+	// PubKeyHashAddrIDEnv is the environment variable
+	// read to override the version byte of P2PKH
+	// addresses on the selected network (i.e. 0x3c).
+	PubKeyHashAddrIDEnv = "PUBKEY_HASH_ADDR_ID"
+
+	// ScriptHashAddrIDEnv is the environment variable
+	// read to override the version byte of P2SH
+	// addresses on the selected network (i.e. 0x7a).
+	ScriptHashAddrIDEnv = "SCRIPT_HASH_ADDR_ID"
+
+	// AdminTokenEnv is the environment variable read to
+	// determine the bearer token required by the admin
+	// endpoints (i.e. /admin/balances). If it is not
+	// populated, the admin endpoints are not served.
+	AdminTokenEnv = "ADMIN_TOKEN"
+
+	// TipStaleBlocksEnv is the environment variable read
+	// to determine how many target block times may pass
+	// since the tip was mined before it is reported as
+	// stale. A value of 0 disables the alarm.
+	TipStaleBlocksEnv = "TIP_STALE_BLOCKS"
+
+	// defaultTipStaleBlocks is the number of target block
+	// times used if TipStaleBlocksEnv is not populated.
+	defaultTipStaleBlocks = 3
+
+	// defaultScriptPubKeyCacheTTL is the TTL of cached
+	// ScriptPubKeys if ScriptPubKeyCacheTTLEnv is not
+	// populated.
+	defaultScriptPubKeyCacheTTL = 10 * time.Minute
+
+	// defaultAssetInfoCacheTTL is the TTL of cached
+	// asset metadata if AssetInfoCacheTTLEnv is not
+	// populated.
+	defaultAssetInfoCacheTTL = 10 * time.Minute
+
+	// defaultMaxFeeAmount is the largest fee (in Satoshis)
+	// suggested if MaxFeeAmountEnv is not populated.
+	defaultMaxFeeAmount = 10 * ravencoin.SatoshisInRavencoin
 )
 
 // PruningConfiguration is the configuration to
@@ -108,6 +262,82 @@ type PruningConfiguration struct {
 	MinHeight int64
 }
 
+// RateLimitConfiguration is the configuration to
+// use for rate limiting requests by client IP.
+type RateLimitConfiguration struct {
+	Rate      float64
+	Burst     int
+	AllowList []string
+}
+
+// ScriptPubKeyCacheConfiguration is the configuration
+// to use for caching coin ScriptPubKeys.
+type ScriptPubKeyCacheConfiguration struct {
+	Size int
+	TTL  time.Duration
+}
+
+// AssetInfoCacheConfiguration is the configuration
+// to use for caching asset metadata.
+type AssetInfoCacheConfiguration struct {
+	Size int
+	TTL  time.Duration
+}
+
+// OperationTypes are the names the construction
+// API uses for each type of operation.
+type OperationTypes struct {
+	Input        string
+	Output       string
+	IssueAsset   string
+	ReissueAsset string
+	BurnAsset    string
+}
+
+// DefaultOperationTypes returns the operation type
+// names used by the data API.
+func DefaultOperationTypes() *OperationTypes {
+	return &OperationTypes{
+		Input:        ravencoin.InputOpType,
+		Output:       ravencoin.OutputOpType,
+		IssueAsset:   ravencoin.IssueAssetOpType,
+		ReissueAsset: ravencoin.ReissueAssetOpType,
+		BurnAsset:    ravencoin.BurnAssetOpType,
+	}
+}
+
+// Supported returns every operation type the server
+// accepts and returns: those of the data API followed
+// by any configured names it does not already use.
+func (o *OperationTypes) Supported() []string {
+	supported := append([]string{}, ravencoin.OperationTypes...)
+	if o == nil {
+		return supported
+	}
+
+	seen := map[string]struct{}{}
+	for _, opType := range supported {
+		seen[opType] = struct{}{}
+	}
+
+	for _, opType := range []string{
+		o.Input,
+		o.Output,
+		o.IssueAsset,
+		o.ReissueAsset,
+		o.BurnAsset,
+	} {
+		if _, ok := seen[opType]; ok || len(opType) == 0 {
+			continue
+		}
+
+		seen[opType] = struct{}{}
+		supported = append(supported, opType)
+	}
+
+	return supported
+}
+
 // Configuration determines how
 type Configuration struct {
 	Mode                   Mode
@@ -122,6 +352,90 @@ type Configuration struct {
 	IndexerPath            string
 	RavendPath           string
 	Compressors            []*encoder.CompressorEntry
+	RateLimit              *RateLimitConfiguration
+
+	// SyncStartHeight is the height the indexer begins
+	// syncing from. Balances of accounts that held funds
+	// before this height are incomplete.
+	SyncStartHeight int64
+
+	// ExcludeImmatureCoinbase determines if coinbase
+	// outputs that cannot yet be spent are omitted from
+	// AccountBalance and AccountCoins.
+	ExcludeImmatureCoinbase bool
+
+	// ConfirmationDepth is the number of blocks below the
+	// best block that /construction/metadata selects its
+	// replay block at. A reorg deeper than this while
+	// fetching metadata results in a retriable error.
+	ConfirmationDepth int64
+
+	// ReplayProtectionDepth overrides ConfirmationDepth
+	// when selecting the replay block, for chains that
+	// warrant a deeper (or shallower) reference block.
+	ReplayProtectionDepth int64
+
+	// PruneDepth is the number of blocks below the best
+	// block that the indexer keeps block data for. Blocks
+	// below it are pruned, which drops the record of coins
+	// spent in them but keeps all unspent coins (and their
+	// scripts). If it is 0, indexed blocks are not pruned.
+	PruneDepth int64
+
+	// ScriptPubKeyCache is nil if ScriptPubKeys
+	// should not be cached.
+	ScriptPubKeyCache *ScriptPubKeyCacheConfiguration
+
+	// AssetInfoCache is nil if asset metadata
+	// should not be cached.
+	AssetInfoCache *AssetInfoCacheConfiguration
+
+	// MaxServableHeight pins the chain view to a snapshot.
+	// If it is populated, blocks above this height are
+	// neither indexed nor served.
+	MaxServableHeight int64
+
+	// AllowNonCanonicalAssetNames disables the rejection of
+	// construction operations on asset names that are not
+	// in their canonical casing.
+	AllowNonCanonicalAssetNames bool
+
+	// AllowUnverifiedInputAmounts disables checking the
+	// amount of each coin provided to /construction/metadata
+	// against the value of the output that created it. When
+	// it is set, an understated input can hide the fee of a
+	// transaction.
+	AllowUnverifiedInputAmounts bool
+
+	// MaxFeeAmount is the largest fee (in Satoshis) that
+	// /construction/metadata will suggest. If the computed
+	// fee exceeds it, an error is returned instead. If it
+	// is 0, the fee is not capped.
+	MaxFeeAmount int64
+
+	// TipStaleThreshold is how long since the tip was mined
+	// before /network/status reports it as stale. If it is
+	// 0, the tip is never reported as stale.
+	TipStaleThreshold time.Duration
+
+	// HDVersionBytes are the public and private HD version
+	// bytes (in that order) of each extended key format to
+	// register in addition to those of Params.
+	HDVersionBytes [][2][4]byte
+
+	// OperationTypes are the operation type names accepted
+	// and returned by the construction API. If it is nil,
+	// DefaultOperationTypes are used.
+	OperationTypes *OperationTypes
+
+	// AdminToken is the bearer token required by the
+	// admin endpoints. If it is empty, they are not served.
+	AdminToken string
+
+	// CustomNetwork is populated if NETWORK is CUSTOM.
+	// Its Params must be registered with
+	// RegisterCustomNetwork before they are used.
+	CustomNetwork *CustomNetworkConfiguration
 }
 
 // LoadConfiguration attempts to create a new Configuration
@@ -189,12 +503,63 @@ func LoadConfiguration(baseDirectory string) (*Configuration, error) {
 				DictionaryPath: testnetTransactionDictionary,
 			},
 		}
+	case Custom:
+		customNetworkPath := os.Getenv(CustomNetworkPathEnv)
+		if len(customNetworkPath) == 0 {
+			return nil, errors.New("CUSTOM_NETWORK_PATH must be populated")
+		}
+
+		customNetwork, err := loadCustomNetworkConfiguration(customNetworkPath)
+		if err != nil {
+			return nil, err
+		}
+
+		params, err := customNetwork.Params()
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid custom network %s", err, customNetworkPath)
+		}
+
+		config.CustomNetwork = customNetwork
+		config.Network = &types.NetworkIdentifier{
+			Blockchain: ravencoin.Blockchain,
+			Network:    customNetwork.Name,
+		}
+		config.GenesisBlockIdentifier = &types.BlockIdentifier{
+			Hash: params.GenesisHash.String(),
+		}
+		config.Params = params
+		config.Currency = &types.Currency{
+			Symbol:   customNetwork.CurrencySymbol,
+			Decimals: ravencoin.Decimals,
+		}
+		config.ConfigPath = customNetwork.ConfigPath
+		config.RPCPort = customNetwork.RPCPort
 	case "":
 		return nil, errors.New("NETWORK must be populated")
 	default:
 		return nil, fmt.Errorf("%s is not a valid network", networkValue)
 	}
 
+	params, err := loadAddressIDOverrides(config.Params)
+	if err != nil {
+		return nil, err
+	}
+	config.Params = params
+
+	tipStaleBlocks := int64(defaultTipStaleBlocks)
+	tipStaleBlocksValue := os.Getenv(TipStaleBlocksEnv)
+	if len(tipStaleBlocksValue) > 0 {
+		tipStaleBlocks, err = strconv.ParseInt(tipStaleBlocksValue, 10, 64)
+		if err != nil || tipStaleBlocks < 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse tip stale blocks %s",
+				err,
+				tipStaleBlocksValue,
+			)
+		}
+	}
+	config.TipStaleThreshold = time.Duration(tipStaleBlocks) * config.Params.TargetTimePerBlock
+
 	portValue := os.Getenv(PortEnv)
 	if len(portValue) == 0 {
 		return nil, errors.New("PORT must be populated")
@@ -206,9 +571,325 @@ func LoadConfiguration(baseDirectory string) (*Configuration, error) {
 	}
 	config.Port = port
 
+	rateLimit, err := loadRateLimitConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	config.RateLimit = rateLimit
+
+	syncStartHeightValue := os.Getenv(SyncStartHeightEnv)
+	if len(syncStartHeightValue) > 0 {
+		syncStartHeight, err := strconv.ParseInt(syncStartHeightValue, 10, 64)
+		if err != nil || syncStartHeight < 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse sync start height %s",
+				err,
+				syncStartHeightValue,
+			)
+		}
+		config.SyncStartHeight = syncStartHeight
+	}
+
+	excludeImmatureCoinbaseValue := os.Getenv(ExcludeImmatureCoinbaseEnv)
+	if len(excludeImmatureCoinbaseValue) > 0 {
+		excludeImmatureCoinbase, err := strconv.ParseBool(excludeImmatureCoinbaseValue)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse exclude immature coinbase %s",
+				err,
+				excludeImmatureCoinbaseValue,
+			)
+		}
+		config.ExcludeImmatureCoinbase = excludeImmatureCoinbase
+	}
+
+	confirmationDepthValue := os.Getenv(ConfirmationDepthEnv)
+	if len(confirmationDepthValue) > 0 {
+		confirmationDepth, err := strconv.ParseInt(confirmationDepthValue, 10, 64)
+		if err != nil || confirmationDepth < 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse confirmation depth %s",
+				err,
+				confirmationDepthValue,
+			)
+		}
+		config.ConfirmationDepth = confirmationDepth
+	}
+
+	replayProtectionDepthValue := os.Getenv(ReplayProtectionDepthEnv)
+	if len(replayProtectionDepthValue) > 0 {
+		replayProtectionDepth, err := strconv.ParseInt(replayProtectionDepthValue, 10, 64)
+		if err != nil || replayProtectionDepth < 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse replay protection depth %s",
+				err,
+				replayProtectionDepthValue,
+			)
+		}
+		config.ReplayProtectionDepth = replayProtectionDepth
+	}
+
+	pruneDepthValue := os.Getenv(PruneDepthEnv)
+	if len(pruneDepthValue) > 0 {
+		indexPruneDepth, err := strconv.ParseInt(pruneDepthValue, 10, 64)
+		if err != nil || indexPruneDepth <= 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse prune depth %s",
+				err,
+				pruneDepthValue,
+			)
+		}
+
+		// Blocks that may still be reorged must
+		// be kept to roll back their coins.
+		if indexPruneDepth < config.ConfirmationDepth {
+			return nil, fmt.Errorf(
+				"prune depth %d is less than confirmation depth %d",
+				indexPruneDepth,
+				config.ConfirmationDepth,
+			)
+		}
+		config.PruneDepth = indexPruneDepth
+	}
+
+	maxServableHeightValue := os.Getenv(MaxServableHeightEnv)
+	if len(maxServableHeightValue) > 0 {
+		maxServableHeight, err := strconv.ParseInt(maxServableHeightValue, 10, 64)
+		if err != nil || maxServableHeight <= 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse max servable height %s",
+				err,
+				maxServableHeightValue,
+			)
+		}
+		config.MaxServableHeight = maxServableHeight
+	}
+
+	allowNonCanonicalAssetNamesValue := os.Getenv(AllowNonCanonicalAssetNamesEnv)
+	if len(allowNonCanonicalAssetNamesValue) > 0 {
+		allowNonCanonicalAssetNames, err := strconv.ParseBool(allowNonCanonicalAssetNamesValue)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse allow non-canonical asset names %s",
+				err,
+				allowNonCanonicalAssetNamesValue,
+			)
+		}
+		config.AllowNonCanonicalAssetNames = allowNonCanonicalAssetNames
+	}
+
+	allowUnverifiedInputAmountsValue := os.Getenv(AllowUnverifiedInputAmountsEnv)
+	if len(allowUnverifiedInputAmountsValue) > 0 {
+		allowUnverifiedInputAmounts, err := strconv.ParseBool(allowUnverifiedInputAmountsValue)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse allow unverified input amounts %s",
+				err,
+				allowUnverifiedInputAmountsValue,
+			)
+		}
+		config.AllowUnverifiedInputAmounts = allowUnverifiedInputAmounts
+	}
+
+	config.MaxFeeAmount = defaultMaxFeeAmount
+	maxFeeAmountValue := os.Getenv(MaxFeeAmountEnv)
+	if len(maxFeeAmountValue) > 0 {
+		maxFeeAmount, err := strconv.ParseInt(maxFeeAmountValue, 10, 64)
+		if err != nil || maxFeeAmount <= 0 {
+			return nil, fmt.Errorf(
+				"%w: unable to parse max fee amount %s",
+				err,
+				maxFeeAmountValue,
+			)
+		}
+		config.MaxFeeAmount = maxFeeAmount
+	}
+
+	scriptPubKeyCache, err := loadScriptPubKeyCacheConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	config.ScriptPubKeyCache = scriptPubKeyCache
+
+	assetInfoCache, err := loadAssetInfoCacheConfiguration()
+	if err != nil {
+		return nil, err
+	}
+	config.AssetInfoCache = assetInfoCache
+
+	hdVersionBytes, err := loadHDVersionBytes()
+	if err != nil {
+		return nil, err
+	}
+	config.HDVersionBytes = hdVersionBytes
+	config.OperationTypes = DefaultOperationTypes()
+	config.AdminToken = os.Getenv(AdminTokenEnv)
+
 	return config, nil
 }
 
+// loadHDVersionBytes returns the HD version byte pairs
+// populated in the environment or nil if there are none.
+func loadHDVersionBytes() ([][2][4]byte, error) {
+	value := os.Getenv(HDVersionBytesEnv)
+	if len(value) == 0 {
+		return nil, nil
+	}
+
+	pairs := strings.Split(value, ",")
+	hdVersionBytes := make([][2][4]byte, len(pairs))
+	for i, pair := range pairs {
+		ids := strings.Split(strings.TrimSpace(pair), ":")
+		if len(ids) != 2 { // nolint:gomnd
+			return nil, fmt.Errorf("%w: unable to parse hd version bytes %s", chaincfg.ErrInvalidHDKeyID, pair)
+		}
+
+		for j, id := range ids {
+			decoded, err := hex.DecodeString(id)
+			if err != nil || len(decoded) != len(hdVersionBytes[i][j]) {
+				return nil, fmt.Errorf("%w: unable to parse hd version bytes %s", chaincfg.ErrInvalidHDKeyID, pair)
+			}
+			copy(hdVersionBytes[i][j][:], decoded)
+		}
+	}
+
+	return hdVersionBytes, nil
+}
+
+// loadAddressIDOverrides returns params with any address
+// version bytes overridden in the environment. If there
+// are no overrides, params is returned.
+func loadAddressIDOverrides(params *chaincfg.Params) (*chaincfg.Params, error) {
+	pubKeyHashAddrIDValue := os.Getenv(PubKeyHashAddrIDEnv)
+	scriptHashAddrIDValue := os.Getenv(ScriptHashAddrIDEnv)
+	if len(pubKeyHashAddrIDValue) == 0 && len(scriptHashAddrIDValue) == 0 {
+		return params, nil
+	}
+
+	pubKeyHashAddrID := params.PubKeyHashAddrID
+	if len(pubKeyHashAddrIDValue) > 0 {
+		id, err := strconv.ParseUint(pubKeyHashAddrIDValue, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse pubkey hash addr id %s", err, pubKeyHashAddrIDValue)
+		}
+		pubKeyHashAddrID = byte(id)
+	}
+
+	scriptHashAddrID := params.ScriptHashAddrID
+	if len(scriptHashAddrIDValue) > 0 {
+		id, err := strconv.ParseUint(scriptHashAddrIDValue, 0, 8)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse script hash addr id %s", err, scriptHashAddrIDValue)
+		}
+		scriptHashAddrID = byte(id)
+	}
+
+	overridden, err := chaincfg.OverrideAddressIDs(params, pubKeyHashAddrID, scriptHashAddrID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to override address version bytes", err)
+	}
+
+	return overridden, nil
+}
+
+// loadScriptPubKeyCacheConfiguration returns the
+// *ScriptPubKeyCacheConfiguration populated from the
+// environment or nil if caching is not enabled.
+func loadScriptPubKeyCacheConfiguration() (*ScriptPubKeyCacheConfiguration, error) {
+	sizeValue := os.Getenv(ScriptPubKeyCacheSizeEnv)
+	if len(sizeValue) == 0 {
+		return nil, nil
+	}
+
+	size, err := strconv.Atoi(sizeValue)
+	if err != nil || size <= 0 {
+		return nil, fmt.Errorf("%w: unable to parse script pub key cache size %s", err, sizeValue)
+	}
+
+	ttl := defaultScriptPubKeyCacheTTL
+	ttlValue := os.Getenv(ScriptPubKeyCacheTTLEnv)
+	if len(ttlValue) > 0 {
+		ttl, err = time.ParseDuration(ttlValue)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("%w: unable to parse script pub key cache ttl %s", err, ttlValue)
+		}
+	}
+
+	return &ScriptPubKeyCacheConfiguration{
+		Size: size,
+		TTL:  ttl,
+	}, nil
+}
+
+// loadAssetInfoCacheConfiguration returns the
+// *AssetInfoCacheConfiguration populated from the
+// environment or nil if caching is not enabled.
+func loadAssetInfoCacheConfiguration() (*AssetInfoCacheConfiguration, error) {
+	sizeValue := os.Getenv(AssetInfoCacheSizeEnv)
+	if len(sizeValue) == 0 {
+		return nil, nil
+	}
+
+	size, err := strconv.Atoi(sizeValue)
+	if err != nil || size <= 0 {
+		return nil, fmt.Errorf("%w: unable to parse asset info cache size %s", err, sizeValue)
+	}
+
+	ttl := defaultAssetInfoCacheTTL
+	ttlValue := os.Getenv(AssetInfoCacheTTLEnv)
+	if len(ttlValue) > 0 {
+		ttl, err = time.ParseDuration(ttlValue)
+		if err != nil || ttl <= 0 {
+			return nil, fmt.Errorf("%w: unable to parse asset info cache ttl %s", err, ttlValue)
+		}
+	}
+
+	return &AssetInfoCacheConfiguration{
+		Size: size,
+		TTL:  ttl,
+	}, nil
+}
+
+// loadRateLimitConfiguration returns the *RateLimitConfiguration
+// populated from the environment or nil if rate limiting
+// is not enabled.
+func loadRateLimitConfiguration() (*RateLimitConfiguration, error) {
+	rateValue := os.Getenv(RateLimitEnv)
+	if len(rateValue) == 0 {
+		return nil, nil
+	}
+
+	rate, err := strconv.ParseFloat(rateValue, 64)
+	if err != nil || rate <= 0 {
+		return nil, fmt.Errorf("%w: unable to parse rate limit %s", err, rateValue)
+	}
+
+	// By default, we allow a burst of one second
+	// worth of requests.
+	burst := int(math.Ceil(rate))
+	burstValue := os.Getenv(RateLimitBurstEnv)
+	if len(burstValue) > 0 {
+		burst, err = strconv.Atoi(burstValue)
+		if err != nil || burst <= 0 {
+			return nil, fmt.Errorf("%w: unable to parse rate limit burst %s", err, burstValue)
+		}
+	}
+
+	allowList := []string{}
+	for _, entry := range strings.Split(os.Getenv(RateLimitAllowListEnv), ",") {
+		entry = strings.TrimSpace(entry)
+		if len(entry) > 0 {
+			allowList = append(allowList, entry)
+		}
+	}
+
+	return &RateLimitConfiguration{
+		Rate:      rate,
+		Burst:     burst,
+		AllowList: allowList,
+	}, nil
+}
+
 // ensurePathsExist directories along
 // a path if they do not exist.
 func ensurePathExists(path string) error {
@@ -15,13 +15,20 @@
 package configuration
 
 import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
+	"io/ioutil"
 	"os"
 	"path"
 	"testing"
+	"time"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
 
+	"github.com/btcsuite/btcutil"
 	"github.com/coinbase/rosetta-sdk-go/storage/encoder"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/coinbase/rosetta-sdk-go/utils"
@@ -65,6 +72,9 @@ func TestLoadConfiguration(t *testing.T) {
 				Port:                   1000,
 				RPCPort:                mainnetRPCPort,
 				ConfigPath:             mainnetConfigPath,
+				TipStaleThreshold:      3 * time.Minute,
+				MaxFeeAmount:           defaultMaxFeeAmount,
+				OperationTypes:         DefaultOperationTypes(),
 				Pruning: &PruningConfiguration{
 					Frequency: pruneFrequency,
 					Depth:     pruneDepth,
@@ -94,6 +104,9 @@ func TestLoadConfiguration(t *testing.T) {
 				Port:                   1000,
 				RPCPort:                testnetRPCPort,
 				ConfigPath:             testnetConfigPath,
+				TipStaleThreshold:      3 * time.Minute,
+				MaxFeeAmount:           defaultMaxFeeAmount,
+				OperationTypes:         DefaultOperationTypes(),
 				Pruning: &PruningConfiguration{
 					Frequency: pruneFrequency,
 					Depth:     pruneDepth,
@@ -150,3 +163,637 @@ func TestLoadConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfiguration_RateLimit(t *testing.T) {
+	tests := map[string]struct {
+		RateLimit          string
+		RateLimitBurst     string
+		RateLimitAllowList string
+
+		rateLimit *RateLimitConfiguration
+		err       error
+	}{
+		"not set": {},
+		"only rate set": {
+			RateLimit: "2.5",
+			rateLimit: &RateLimitConfiguration{
+				Rate:      2.5,
+				Burst:     3,
+				AllowList: []string{},
+			},
+		},
+		"all set": {
+			RateLimit:          "10",
+			RateLimitBurst:     "50",
+			RateLimitAllowList: "127.0.0.1, 10.0.0.0/8",
+			rateLimit: &RateLimitConfiguration{
+				Rate:      10,
+				Burst:     50,
+				AllowList: []string{"127.0.0.1", "10.0.0.0/8"},
+			},
+		},
+		"invalid rate": {
+			RateLimit: "-1",
+			err:       errors.New("unable to parse rate limit -1"),
+		},
+		"invalid burst": {
+			RateLimit:      "10",
+			RateLimitBurst: "bad burst",
+			err:            errors.New("unable to parse rate limit burst bad burst"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(RateLimitEnv, test.RateLimit)
+			os.Setenv(RateLimitBurstEnv, test.RateLimitBurst)
+			os.Setenv(RateLimitAllowListEnv, test.RateLimitAllowList)
+			defer func() {
+				os.Unsetenv(RateLimitEnv)
+				os.Unsetenv(RateLimitBurstEnv)
+				os.Unsetenv(RateLimitAllowListEnv)
+			}()
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.rateLimit, cfg.RateLimit)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_SyncStartHeight(t *testing.T) {
+	tests := map[string]struct {
+		SyncStartHeight string
+
+		syncStartHeight int64
+		err             error
+	}{
+		"not set": {},
+		"set": {
+			SyncStartHeight: "1500000",
+			syncStartHeight: 1500000,
+		},
+		"invalid": {
+			SyncStartHeight: "-1",
+			err:             errors.New("unable to parse sync start height -1"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(SyncStartHeightEnv, test.SyncStartHeight)
+			defer os.Unsetenv(SyncStartHeightEnv)
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.syncStartHeight, cfg.SyncStartHeight)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_PruneDepth(t *testing.T) {
+	tests := map[string]struct {
+		PruneDepth        string
+		ConfirmationDepth string
+
+		pruneDepth int64
+		err        error
+	}{
+		"not set": {},
+		"set": {
+			PruneDepth: "1000",
+			pruneDepth: 1000,
+		},
+		"equal to confirmation depth": {
+			PruneDepth:        "6",
+			ConfirmationDepth: "6",
+			pruneDepth:        6,
+		},
+		"less than confirmation depth": {
+			PruneDepth:        "5",
+			ConfirmationDepth: "6",
+			err:               errors.New("prune depth 5 is less than confirmation depth 6"),
+		},
+		"invalid": {
+			PruneDepth: "0",
+			err:        errors.New("unable to parse prune depth 0"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(PruneDepthEnv, test.PruneDepth)
+			os.Setenv(ConfirmationDepthEnv, test.ConfirmationDepth)
+			defer func() {
+				os.Unsetenv(PruneDepthEnv)
+				os.Unsetenv(ConfirmationDepthEnv)
+			}()
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.pruneDepth, cfg.PruneDepth)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_ScriptPubKeyCache(t *testing.T) {
+	tests := map[string]struct {
+		ScriptPubKeyCacheSize string
+		ScriptPubKeyCacheTTL  string
+
+		scriptPubKeyCache *ScriptPubKeyCacheConfiguration
+		err               error
+	}{
+		"not set": {},
+		"default ttl": {
+			ScriptPubKeyCacheSize: "1000",
+			scriptPubKeyCache: &ScriptPubKeyCacheConfiguration{
+				Size: 1000,
+				TTL:  defaultScriptPubKeyCacheTTL,
+			},
+		},
+		"custom ttl": {
+			ScriptPubKeyCacheSize: "1000",
+			ScriptPubKeyCacheTTL:  "1h",
+			scriptPubKeyCache: &ScriptPubKeyCacheConfiguration{
+				Size: 1000,
+				TTL:  time.Hour,
+			},
+		},
+		"invalid size": {
+			ScriptPubKeyCacheSize: "0",
+			err:                   errors.New("unable to parse script pub key cache size 0"),
+		},
+		"invalid ttl": {
+			ScriptPubKeyCacheSize: "1000",
+			ScriptPubKeyCacheTTL:  "forever",
+			err:                   errors.New("unable to parse script pub key cache ttl forever"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(ScriptPubKeyCacheSizeEnv, test.ScriptPubKeyCacheSize)
+			os.Setenv(ScriptPubKeyCacheTTLEnv, test.ScriptPubKeyCacheTTL)
+			defer func() {
+				os.Unsetenv(ScriptPubKeyCacheSizeEnv)
+				os.Unsetenv(ScriptPubKeyCacheTTLEnv)
+			}()
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.scriptPubKeyCache, cfg.ScriptPubKeyCache)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_AssetInfoCache(t *testing.T) {
+	tests := map[string]struct {
+		AssetInfoCacheSize string
+		AssetInfoCacheTTL  string
+
+		assetInfoCache *AssetInfoCacheConfiguration
+		err            error
+	}{
+		"not set": {},
+		"default ttl": {
+			AssetInfoCacheSize: "1000",
+			assetInfoCache: &AssetInfoCacheConfiguration{
+				Size: 1000,
+				TTL:  defaultAssetInfoCacheTTL,
+			},
+		},
+		"custom ttl": {
+			AssetInfoCacheSize: "1000",
+			AssetInfoCacheTTL:  "1h",
+			assetInfoCache: &AssetInfoCacheConfiguration{
+				Size: 1000,
+				TTL:  time.Hour,
+			},
+		},
+		"invalid size": {
+			AssetInfoCacheSize: "0",
+			err:                errors.New("unable to parse asset info cache size 0"),
+		},
+		"invalid ttl": {
+			AssetInfoCacheSize: "1000",
+			AssetInfoCacheTTL:  "forever",
+			err:                errors.New("unable to parse asset info cache ttl forever"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(AssetInfoCacheSizeEnv, test.AssetInfoCacheSize)
+			os.Setenv(AssetInfoCacheTTLEnv, test.AssetInfoCacheTTL)
+			defer func() {
+				os.Unsetenv(AssetInfoCacheSizeEnv)
+				os.Unsetenv(AssetInfoCacheTTLEnv)
+			}()
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.assetInfoCache, cfg.AssetInfoCache)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_AllowUnverifiedInputAmounts(t *testing.T) {
+	tests := map[string]struct {
+		AllowUnverifiedInputAmounts string
+
+		allowUnverifiedInputAmounts bool
+		err                         error
+	}{
+		"not set": {},
+		"enabled": {
+			AllowUnverifiedInputAmounts: "true",
+			allowUnverifiedInputAmounts: true,
+		},
+		"disabled": {
+			AllowUnverifiedInputAmounts: "false",
+		},
+		"invalid": {
+			AllowUnverifiedInputAmounts: "sometimes",
+			err:                         errors.New("unable to parse allow unverified input amounts sometimes"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(AllowUnverifiedInputAmountsEnv, test.AllowUnverifiedInputAmounts)
+			defer os.Unsetenv(AllowUnverifiedInputAmountsEnv)
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.allowUnverifiedInputAmounts, cfg.AllowUnverifiedInputAmounts)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_MaxFeeAmount(t *testing.T) {
+	tests := map[string]struct {
+		MaxFeeAmount string
+
+		maxFeeAmount int64
+		err          error
+	}{
+		"not set": {
+			maxFeeAmount: defaultMaxFeeAmount,
+		},
+		"set": {
+			MaxFeeAmount: "500000",
+			maxFeeAmount: 500000,
+		},
+		"zero": {
+			MaxFeeAmount: "0",
+			err:          errors.New("unable to parse max fee amount 0"),
+		},
+		"invalid": {
+			MaxFeeAmount: "lots",
+			err:          errors.New("unable to parse max fee amount lots"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(MaxFeeAmountEnv, test.MaxFeeAmount)
+			defer os.Unsetenv(MaxFeeAmountEnv)
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.maxFeeAmount, cfg.MaxFeeAmount)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_HDVersionBytes(t *testing.T) {
+	tests := map[string]struct {
+		HDVersionBytes string
+
+		hdVersionBytes [][2][4]byte
+		err            error
+	}{
+		"not set": {},
+		"single pair": {
+			HDVersionBytes: "04b24746:04b2430c",
+			hdVersionBytes: [][2][4]byte{
+				{{0x04, 0xb2, 0x47, 0x46}, {0x04, 0xb2, 0x43, 0x0c}},
+			},
+		},
+		"multiple pairs": {
+			HDVersionBytes: "04b24746:04b2430c, 049d7cb2:049d7878",
+			hdVersionBytes: [][2][4]byte{
+				{{0x04, 0xb2, 0x47, 0x46}, {0x04, 0xb2, 0x43, 0x0c}},
+				{{0x04, 0x9d, 0x7c, 0xb2}, {0x04, 0x9d, 0x78, 0x78}},
+			},
+		},
+		"missing private": {
+			HDVersionBytes: "04b24746",
+			err:            chaincfg.ErrInvalidHDKeyID,
+		},
+		"wrong length": {
+			HDVersionBytes: "04b247:04b2430c",
+			err:            chaincfg.ErrInvalidHDKeyID,
+		},
+		"not hex": {
+			HDVersionBytes: "zpub:zprv",
+			err:            chaincfg.ErrInvalidHDKeyID,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(HDVersionBytesEnv, test.HDVersionBytes)
+			defer os.Unsetenv(HDVersionBytesEnv)
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.True(t, errors.Is(err, test.err))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.hdVersionBytes, cfg.HDVersionBytes)
+			}
+		})
+	}
+}
+
+func TestLoadConfiguration_AddressIDOverrides(t *testing.T) {
+	hash := make([]byte, 20)
+
+	tests := map[string]struct {
+		PubKeyHashAddrID string
+		ScriptHashAddrID string
+
+		p2pkhAddress string
+		p2shAddress  string
+		err          error
+	}{
+		"not set": {
+			p2pkhAddress: "mfWxJ45yp2SFn7UciZyNpvDKrzbhyfKrY8",
+			p2shAddress:  "2MsFDzHRUAMpjHxKyoEHU3aMCMsVtMqs1PV",
+		},
+		"pubkey hash override": {
+			PubKeyHashAddrID: "0x41",
+			p2pkhAddress:     "T9yD14Nj9j7xAB4dbGeiX9h8unkKHxuWwb",
+			p2shAddress:      "2MsFDzHRUAMpjHxKyoEHU3aMCMsVtMqs1PV",
+		},
+		"collides with mainnet": {
+			PubKeyHashAddrID: "0x00",
+			err:              chaincfg.ErrAddressIDCollision,
+		},
+		"collides with script hash": {
+			PubKeyHashAddrID: "0xc4",
+			err:              chaincfg.ErrAddressIDCollision,
+		},
+		"invalid pubkey hash id": {
+			PubKeyHashAddrID: "256",
+			err:              errors.New("unable to parse pubkey hash addr id 256"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Testnet)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(PubKeyHashAddrIDEnv, test.PubKeyHashAddrID)
+			os.Setenv(ScriptHashAddrIDEnv, test.ScriptHashAddrID)
+			defer func() {
+				os.Unsetenv(PubKeyHashAddrIDEnv)
+				os.Unsetenv(ScriptHashAddrIDEnv)
+			}()
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.Contains(t, err.Error(), test.err.Error())
+				return
+			}
+			assert.NoError(t, err)
+
+			p2pkh, err := btcutil.NewAddressPubKeyHash(hash, cfg.Params.BtcdParams())
+			assert.NoError(t, err)
+			assert.Equal(t, test.p2pkhAddress, p2pkh.EncodeAddress())
+
+			p2sh, err := btcutil.NewAddressScriptHashFromHash(hash, cfg.Params.BtcdParams())
+			assert.NoError(t, err)
+			assert.Equal(t, test.p2shAddress, p2sh.EncodeAddress())
+
+			// The network parameters are never modified.
+			assert.Equal(t, byte(0x6f), ravencoin.TestnetParams.PubKeyHashAddrID)
+		})
+	}
+}
+
+func TestLoadConfiguration_CustomNetwork(t *testing.T) {
+	// The transactions of the mainnet genesis block
+	// with a header that commits to them.
+	genesis := *chaincfg.MainNetParams.GenesisBlock
+	genesis.Header.MerkleRoot = genesis.Transactions[0].TxHash()
+	genesisHash := "00000086f1ac3ee1bf16bfbfd8d5c0ebb7a03c7d6f5ab8c1f85e6e0f1a3a5ae4"
+
+	customNetwork := func() *CustomNetworkConfiguration {
+		var buf bytes.Buffer
+		assert.NoError(t, genesis.Serialize(&buf))
+
+		return &CustomNetworkConfiguration{
+			Name:             "customnet",
+			Net:              chaincfg.RavencoinNet(0x43535452),
+			Port:             "18770",
+			RPCPort:          18771,
+			ConfigPath:       "/app/customnet.conf",
+			GenesisBlock:     hex.EncodeToString(buf.Bytes()),
+			GenesisHash:      genesisHash,
+			CurrencySymbol:   "CST",
+			PubKeyHashAddrID: 0x1c,
+			ScriptHashAddrID: 0x1e,
+			PrivateKeyID:     0x9c,
+			Bech32HRPSegwit:  "cst",
+			HDPublicKeyID:    "0488b21e",
+			HDPrivateKeyID:   "0488ade4",
+			HDCoinType:       175,
+		}
+	}
+
+	tests := map[string]struct {
+		network func() *CustomNetworkConfiguration
+
+		p2pkhAddress string
+		err          error
+		registerErr  error
+	}{
+		"valid": {
+			network:      customNetwork,
+			p2pkhAddress: "CGTta3M4t3yXu8uRgkKvaWd2d8DQvDPnpL",
+		},
+		"genesis merkle root mismatch": {
+			network: func() *CustomNetworkConfiguration {
+				block := genesis
+				block.Header.MerkleRoot = *chaincfg.MainNetParams.GenesisHash
+
+				var buf bytes.Buffer
+				assert.NoError(t, block.Serialize(&buf))
+
+				network := customNetwork()
+				network.GenesisBlock = hex.EncodeToString(buf.Bytes())
+				return network
+			},
+			err: ErrGenesisMismatch,
+		},
+		"genesis has parent": {
+			network: func() *CustomNetworkConfiguration {
+				block := genesis
+				block.Header.PrevBlock[0] = 0x01
+
+				var buf bytes.Buffer
+				assert.NoError(t, block.Serialize(&buf))
+
+				network := customNetwork()
+				network.GenesisBlock = hex.EncodeToString(buf.Bytes())
+				return network
+			},
+			err: ErrGenesisMismatch,
+		},
+		"invalid hd version bytes": {
+			network: func() *CustomNetworkConfiguration {
+				network := customNetwork()
+				network.HDPrivateKeyID = "0488ad"
+				return network
+			},
+			err: chaincfg.ErrInvalidHDKeyID,
+		},
+		"same address ids": {
+			network: func() *CustomNetworkConfiguration {
+				network := customNetwork()
+				network.ScriptHashAddrID = network.PubKeyHashAddrID
+				return network
+			},
+			err: chaincfg.ErrAddressIDCollision,
+		},
+		"mainnet magic": {
+			network: func() *CustomNetworkConfiguration {
+				network := customNetwork()
+				network.Net = chaincfg.MainNet
+				return network
+			},
+			registerErr: chaincfg.ErrDuplicateNet,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			contents, err := json.Marshal(test.network())
+			assert.NoError(t, err)
+
+			dir, err := ioutil.TempDir("", "custom-network")
+			assert.NoError(t, err)
+			defer os.RemoveAll(dir)
+
+			networkPath := path.Join(dir, "network.json")
+			assert.NoError(t, ioutil.WriteFile(networkPath, contents, 0600))
+
+			os.Setenv(ModeEnv, string(Offline))
+			os.Setenv(NetworkEnv, Custom)
+			os.Setenv(PortEnv, "1000")
+			os.Setenv(CustomNetworkPathEnv, networkPath)
+			defer os.Unsetenv(CustomNetworkPathEnv)
+
+			cfg, err := LoadConfiguration("")
+			if test.err != nil {
+				assert.Nil(t, cfg)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, &types.NetworkIdentifier{
+				Blockchain: ravencoin.Blockchain,
+				Network:    "customnet",
+			}, cfg.Network)
+			assert.Equal(t, &types.BlockIdentifier{Hash: genesisHash}, cfg.GenesisBlockIdentifier)
+			assert.Equal(t, &types.Currency{Symbol: "CST", Decimals: 8}, cfg.Currency)
+			assert.Equal(t, 18771, cfg.RPCPort)
+			assert.Equal(t, "/app/customnet.conf", cfg.ConfigPath)
+			assert.Equal(t, chaincfg.MainNetParams.SubsidyReductionInterval, cfg.Params.SubsidyReductionInterval)
+
+			err = RegisterCustomNetwork(cfg)
+			if test.registerErr != nil {
+				assert.True(t, errors.Is(err, test.registerErr))
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, chaincfg.IsPubKeyHashAddrID(0x1c))
+
+			// Registering the same network again fails.
+			assert.True(t, errors.Is(RegisterCustomNetwork(cfg), chaincfg.ErrDuplicateNet))
+
+			address, err := cfg.Params.EncodeP2PKH(make([]byte, 20))
+			assert.NoError(t, err)
+			assert.Equal(t, test.p2pkhAddress, address)
+
+			p2pkh, err := btcutil.NewAddressPubKeyHash(make([]byte, 20), cfg.Params.BtcdParams())
+			assert.NoError(t, err)
+			assert.Equal(t, test.p2pkhAddress, p2pkh.EncodeAddress())
+		})
+	}
+}
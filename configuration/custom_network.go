@@ -0,0 +1,229 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package configuration
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+)
+
+// ErrGenesisMismatch is returned when the genesis block
+// of a custom network has a parent or does not commit
+// to its transactions.
+var ErrGenesisMismatch = errors.New("genesis block mismatch")
+
+// CustomNetworkConfiguration describes a Ravencoin-derived
+// chain that is not one of the default networks. It is
+// read from the JSON file at CustomNetworkPathEnv.
+//
+// Consensus parameters (subsidy, retargeting, deployments
+// and asset burns) are those of mainnet.
+type CustomNetworkConfiguration struct {
+	Name string                `json:"name"`
+	Net  chaincfg.RavencoinNet `json:"magic"`
+
+	// Port is the peer-to-peer port of the network and
+	// RPCPort is the port ravend serves RPC on.
+	Port       string `json:"port"`
+	RPCPort    int    `json:"rpc_port"`
+	ConfigPath string `json:"config_path"`
+
+	// GenesisBlock is the hex-encoded serialized genesis
+	// block. Its hash cannot be computed without the
+	// chain's proof of work, so it is provided as
+	// GenesisHash.
+	GenesisBlock string `json:"genesis_block"`
+	GenesisHash  string `json:"genesis_hash"`
+
+	CurrencySymbol string `json:"currency_symbol"`
+
+	PubKeyHashAddrID byte   `json:"pubkey_hash_addr_id"`
+	ScriptHashAddrID byte   `json:"script_hash_addr_id"`
+	PrivateKeyID     byte   `json:"private_key_id"`
+	Bech32HRPSegwit  string `json:"bech32_hrp_segwit"`
+
+	// HDPublicKeyID and HDPrivateKeyID are the
+	// hex-encoded BIP32 version bytes.
+	HDPublicKeyID  string `json:"hd_public_key_id"`
+	HDPrivateKeyID string `json:"hd_private_key_id"`
+	HDCoinType     uint32 `json:"hd_coin_type"`
+}
+
+// loadCustomNetworkConfiguration reads the
+// *CustomNetworkConfiguration at filePath.
+func loadCustomNetworkConfiguration(filePath string) (*CustomNetworkConfiguration, error) {
+	contents, err := ioutil.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to read custom network %s", err, filePath)
+	}
+
+	var network CustomNetworkConfiguration
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&network); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse custom network %s", err, filePath)
+	}
+
+	return &network, nil
+}
+
+// Params returns the *chaincfg.Params of the network.
+// An error wrapping ErrGenesisMismatch is returned if
+// the genesis block does not commit to its transactions
+// or has a parent.
+func (c *CustomNetworkConfiguration) Params() (*chaincfg.Params, error) {
+	if len(c.Name) == 0 {
+		return nil, errors.New("custom network name must be populated")
+	}
+
+	if c.RPCPort <= 0 {
+		return nil, fmt.Errorf("custom network rpc port %d is invalid", c.RPCPort)
+	}
+
+	if len(c.CurrencySymbol) == 0 {
+		return nil, errors.New("custom network currency symbol must be populated")
+	}
+
+	if c.PubKeyHashAddrID == c.ScriptHashAddrID {
+		return nil, fmt.Errorf(
+			"%w: pubkey hash and script hash ids are both %#x",
+			chaincfg.ErrAddressIDCollision,
+			c.PubKeyHashAddrID,
+		)
+	}
+
+	genesisBlock, err := c.genesisBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	genesisHash, err := chainhash.NewHashFromStr(c.GenesisHash)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse genesis hash %s", err, c.GenesisHash)
+	}
+
+	params := chaincfg.MainNetParams
+	params.Name = c.Name
+	params.Net = c.Net
+	params.DefaultPort = c.Port
+	params.DNSSeeds = nil
+	params.Checkpoints = nil
+	params.GenesisBlock = genesisBlock
+	params.GenesisHash = genesisHash
+	params.Bech32HRPSegwit = c.Bech32HRPSegwit
+	params.PubKeyHashAddrID = c.PubKeyHashAddrID
+	params.ScriptHashAddrID = c.ScriptHashAddrID
+	params.PrivateKeyID = c.PrivateKeyID
+	params.HDCoinType = c.HDCoinType
+
+	if err := decodeHDKeyID(c.HDPublicKeyID, &params.HDPublicKeyID); err != nil {
+		return nil, err
+	}
+
+	if err := decodeHDKeyID(c.HDPrivateKeyID, &params.HDPrivateKeyID); err != nil {
+		return nil, err
+	}
+
+	return &params, nil
+}
+
+// genesisBlock decodes GenesisBlock and checks that
+// its header commits to its transactions.
+func (c *CustomNetworkConfiguration) genesisBlock() (*wire.MsgBlock, error) {
+	raw, err := hex.DecodeString(c.GenesisBlock)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode genesis block", err)
+	}
+
+	var block wire.MsgBlock
+	if err := block.Deserialize(bytes.NewReader(raw)); err != nil {
+		return nil, fmt.Errorf("%w: unable to deserialize genesis block", err)
+	}
+
+	if block.Header.PrevBlock != (chainhash.Hash{}) {
+		return nil, fmt.Errorf(
+			"%w: genesis block has parent %s",
+			ErrGenesisMismatch,
+			block.Header.PrevBlock,
+		)
+	}
+
+	if len(block.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: genesis block has no transactions", ErrGenesisMismatch)
+	}
+
+	txs := make([]*btcutil.Tx, len(block.Transactions))
+	for i, tx := range block.Transactions {
+		txs[i] = btcutil.NewTx(tx)
+	}
+
+	merkles := blockchain.BuildMerkleTreeStore(txs, false)
+	merkleRoot := merkles[len(merkles)-1]
+	if !block.Header.MerkleRoot.IsEqual(merkleRoot) {
+		return nil, fmt.Errorf(
+			"%w: genesis merkle root is %s but transactions hash to %s",
+			ErrGenesisMismatch,
+			block.Header.MerkleRoot,
+			merkleRoot,
+		)
+	}
+
+	return &block, nil
+}
+
+// decodeHDKeyID decodes the hex-encoded HD version
+// bytes value into id.
+func decodeHDKeyID(value string, id *[4]byte) error {
+	decoded, err := hex.DecodeString(value)
+	if err != nil || len(decoded) != len(id) {
+		return fmt.Errorf("%w: unable to parse hd version bytes %s", chaincfg.ErrInvalidHDKeyID, value)
+	}
+	copy(id[:], decoded)
+
+	return nil
+}
+
+// RegisterCustomNetwork registers the Params of the
+// configured custom network so that its addresses and
+// extended keys can be resolved. It does nothing if no
+// custom network is configured and should be called
+// once at startup.
+func RegisterCustomNetwork(config *Configuration) error {
+	if config.CustomNetwork == nil {
+		return nil
+	}
+
+	if err := chaincfg.Register(config.Params); err != nil {
+		return fmt.Errorf(
+			"%w: unable to register custom network %s (%s)",
+			err,
+			config.Params.Name,
+			config.Params.Net,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/neilotoole/errgroup"
+)
+
+const (
+	// addressHistoryNamespace prefixes all
+	// keys stored by addressHistoryStorage.
+	addressHistoryNamespace = "address-history"
+)
+
+var _ modules.BlockWorker = (*addressHistoryStorage)(nil)
+
+// addressHistoryEntry is stored for each transaction
+// with an operation on an address.
+type addressHistoryEntry struct {
+	BlockIdentifier       *types.BlockIdentifier       `json:"block_identifier"`
+	TransactionIdentifier *types.TransactionIdentifier `json:"transaction_identifier"`
+}
+
+// addressHistoryStorage indexes the transactions of each
+// address as blocks are added and removed, so that the
+// history of an address can be found without scanning
+// all blocks. Keys sort by block index and position in
+// the block, so a scan returns the history oldest first.
+type addressHistoryStorage struct {
+	db database.Database
+}
+
+func newAddressHistoryStorage(db database.Database) *addressHistoryStorage {
+	return &addressHistoryStorage{db: db}
+}
+
+func addressHistoryPrefix(address string) []byte {
+	return []byte(fmt.Sprintf("%s/%s/", addressHistoryNamespace, address))
+}
+
+func addressHistoryKey(address string, blockIndex int64, position int) []byte {
+	return []byte(fmt.Sprintf(
+		"%s%016x/%08x",
+		addressHistoryPrefix(address),
+		blockIndex,
+		position,
+	))
+}
+
+// transactionAddresses returns the distinct addresses with an
+// operation in transaction.
+func transactionAddresses(transaction *types.Transaction) []string {
+	seen := map[string]struct{}{}
+	addresses := []string{}
+	for _, op := range transaction.Operations {
+		if op.Account == nil {
+			continue
+		}
+
+		if _, ok := seen[op.Account.Address]; ok {
+			continue
+		}
+
+		seen[op.Account.Address] = struct{}{}
+		addresses = append(addresses, op.Account.Address)
+	}
+
+	return addresses
+}
+
+// AddingBlock is called by BlockStorage when adding a block.
+func (a *addressHistoryStorage) AddingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	for position, tx := range block.Transactions {
+		value, err := json.Marshal(&addressHistoryEntry{
+			BlockIdentifier:       block.BlockIdentifier,
+			TransactionIdentifier: tx.TransactionIdentifier,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to encode address history entry", err)
+		}
+
+		for _, address := range transactionAddresses(tx) {
+			key := addressHistoryKey(address, block.BlockIdentifier.Index, position)
+			if err := transaction.Set(ctx, key, value, true); err != nil {
+				return nil, fmt.Errorf("%w: unable to store address history entry", err)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// RemovingBlock is called by BlockStorage when removing a block.
+func (a *addressHistoryStorage) RemovingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	for position, tx := range block.Transactions {
+		for _, address := range transactionAddresses(tx) {
+			key := addressHistoryKey(address, block.BlockIdentifier.Index, position)
+			if err := transaction.Delete(ctx, key); err != nil {
+				return nil, fmt.Errorf("%w: unable to delete address history entry", err)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+// GetEntries returns the history of address, newest first.
+func (a *addressHistoryStorage) GetEntries(
+	ctx context.Context,
+	address string,
+) ([]*addressHistoryEntry, error) {
+	dbTx := a.db.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	prefix := addressHistoryPrefix(address)
+	entries := []*addressHistoryEntry{}
+	_, err := dbTx.Scan(
+		ctx,
+		prefix,
+		prefix,
+		func(k []byte, v []byte) error {
+			var entry addressHistoryEntry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return fmt.Errorf("%w: unable to decode address history entry %s", err, string(k))
+			}
+
+			entries = append(entries, &entry)
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to scan address history", err)
+	}
+
+	for l, r := 0, len(entries)-1; l < r; l, r = l+1, r-1 {
+		entries[l], entries[r] = entries[r], entries[l]
+	}
+
+	return entries, nil
+}
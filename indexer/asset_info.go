@@ -0,0 +1,235 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// assetInfoCacheEntry is a cached *ravencoin.AssetInfo.
+type assetInfoCacheEntry struct {
+	name    string
+	info    *ravencoin.AssetInfo
+	expires time.Time
+}
+
+// assetInfoCache caches the *ravencoin.AssetInfo of
+// each asset fetched from ravend, keyed by asset name.
+// Asset metadata only changes when an asset is reissued,
+// so entries are invalidated when a reissuance is indexed
+// (or a block is removed) and are otherwise only evicted
+// to bound memory usage (least recently used first) or
+// when they are older than the configured TTL.
+type assetInfoCache struct {
+	size int
+	ttl  time.Duration
+
+	entries map[string]*list.Element
+	order   *list.List
+	lock    sync.Mutex
+
+	// generation is incremented on each invalidation
+	// so that lookups that raced with an invalidation
+	// are not cached.
+	generation uint64
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// newAssetInfoCache returns a new *assetInfoCache.
+func newAssetInfoCache(
+	config *configuration.AssetInfoCacheConfiguration,
+) *assetInfoCache {
+	return &assetInfoCache{
+		size:    config.Size,
+		ttl:     config.TTL,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached *ravencoin.AssetInfo of the
+// asset with name (if it exists) and the current
+// generation of the cache.
+func (c *assetInfoCache) get(name string) (*ravencoin.AssetInfo, uint64, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[name]
+	if !ok {
+		return nil, c.generation, false
+	}
+
+	entry := element.Value.(*assetInfoCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, name)
+		return nil, c.generation, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.info, c.generation, true
+}
+
+// put caches the *ravencoin.AssetInfo of the asset with
+// name, unless the cache was invalidated since generation.
+func (c *assetInfoCache) put(name string, info *ravencoin.AssetInfo, generation uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[name]; ok {
+		entry := element.Value.(*assetInfoCacheEntry)
+		entry.info = info
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[name] = c.order.PushFront(&assetInfoCacheEntry{
+		name:    name,
+		info:    info,
+		expires: expires,
+	})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*assetInfoCacheEntry).name)
+	}
+}
+
+// invalidate removes the cached *ravencoin.AssetInfo
+// of each asset in names.
+func (c *assetInfoCache) invalidate(names []string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generation++
+	for _, name := range names {
+		element, ok := c.entries[name]
+		if !ok {
+			continue
+		}
+
+		c.order.Remove(element)
+		delete(c.entries, name)
+	}
+}
+
+// purge removes all cached *ravencoin.AssetInfo.
+func (c *assetInfoCache) purge() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.generation++
+	c.entries = map[string]*list.Element{}
+	c.order.Init()
+}
+
+// GetAssetInfo returns the *ravencoin.AssetInfo of the asset
+// with name, only fetching it from ravend if it is not cached.
+func (i *Indexer) GetAssetInfo(
+	ctx context.Context,
+	name string,
+) (*ravencoin.AssetInfo, error) {
+	var generation uint64
+	if i.assetInfo != nil {
+		info, cachedGeneration, ok := i.assetInfo.get(name)
+		if ok {
+			return info, nil
+		}
+
+		generation = cachedGeneration
+	}
+
+	info, err := i.client.GetAssetData(ctx, name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get asset data %s", err, name)
+	}
+
+	if i.assetInfo != nil {
+		i.assetInfo.put(name, info, generation)
+	}
+
+	return info, nil
+}
+
+// reissuedAssets returns the names of all assets
+// reissued in block.
+func reissuedAssets(block *types.Block) ([]string, error) {
+	names := []string{}
+	for _, tx := range block.Transactions {
+		for _, op := range tx.Operations {
+			if op.Type != ravencoin.OutputOpType || op.Amount == nil {
+				continue
+			}
+
+			var metadata ravencoin.OperationMetadata
+			if err := types.UnmarshalMap(op.Metadata, &metadata); err != nil {
+				return nil, fmt.Errorf(
+					"%w: unable to unmarshal metadata of operation %d in %s",
+					err,
+					op.OperationIdentifier.Index,
+					tx.TransactionIdentifier.Hash,
+				)
+			}
+
+			if metadata.ScriptPubKey == nil ||
+				metadata.ScriptPubKey.Type != ravencoin.ReissueAssetType {
+				continue
+			}
+
+			names = append(names, op.Amount.Currency.Symbol)
+		}
+	}
+
+	return names, nil
+}
+
+// invalidateReissuedAssets removes the cached
+// *ravencoin.AssetInfo of each asset reissued
+// in block.
+func (i *Indexer) invalidateReissuedAssets(block *types.Block) error {
+	if i.assetInfo == nil {
+		return nil
+	}
+
+	names, err := reissuedAssets(block)
+	if err != nil {
+		return err
+	}
+
+	if len(names) > 0 {
+		i.assetInfo.invalidate(names)
+	}
+
+	return nil
+}
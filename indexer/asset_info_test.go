@@ -0,0 +1,132 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/indexer"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func assetTestBlock(t *testing.T, scriptType string, name string) *types.Block {
+	metadata, err := (&ravencoin.Output{
+		ScriptPubKey: &ravencoin.ScriptPubKey{
+			Type: scriptType,
+		},
+	}).Metadata()
+	assert.NoError(t, err)
+
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  "block 1",
+			Index: 1,
+		},
+		Transactions: []*types.Transaction{
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{
+					Hash: "tx",
+				},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index: 0,
+						},
+						Type: ravencoin.OutputOpType,
+						Amount: &types.Amount{
+							Value:    "100000000000",
+							Currency: ravencoin.AssetCurrency(name, ravencoin.MaxAssetUnits),
+						},
+						Metadata: metadata,
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestIndexer_GetAssetInfo(t *testing.T) {
+	ctx := context.Background()
+	mockClient := &mocks.Client{}
+	i := &Indexer{
+		client: mockClient,
+		assetInfo: newAssetInfoCache(&configuration.AssetInfoCacheConfiguration{
+			Size: 10,
+			TTL:  time.Minute,
+		}),
+	}
+
+	info := &ravencoin.AssetInfo{
+		Name:       "ROSETTA",
+		Amount:     100000000000,
+		Units:      0,
+		Reissuable: true,
+	}
+	mockClient.On("GetAssetData", ctx, "ROSETTA").Return(info, nil).Once()
+
+	fetched, err := i.GetAssetInfo(ctx, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, info, fetched)
+
+	// The second lookup is served from the cache.
+	fetched, err = i.GetAssetInfo(ctx, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, info, fetched)
+	mockClient.AssertNumberOfCalls(t, "GetAssetData", 1)
+
+	// Transfers do not change asset metadata.
+	assert.NoError(t, i.invalidateReissuedAssets(
+		assetTestBlock(t, ravencoin.TransferAssetType, "ROSETTA"),
+	))
+	fetched, err = i.GetAssetInfo(ctx, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, info, fetched)
+	mockClient.AssertNumberOfCalls(t, "GetAssetData", 1)
+
+	// Observing a reissuance invalidates the cached
+	// metadata of the asset.
+	reissued := &ravencoin.AssetInfo{
+		Name:       "ROSETTA",
+		Amount:     200000000000,
+		Units:      2,
+		Reissuable: false,
+	}
+	mockClient.On("GetAssetData", ctx, "ROSETTA").Return(reissued, nil).Once()
+	assert.NoError(t, i.invalidateReissuedAssets(
+		assetTestBlock(t, ravencoin.ReissueAssetType, "ROSETTA"),
+	))
+	fetched, err = i.GetAssetInfo(ctx, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, reissued, fetched)
+	mockClient.AssertNumberOfCalls(t, "GetAssetData", 2)
+
+	// Expired entries are fetched again.
+	i.assetInfo.now = func() time.Time {
+		return time.Now().Add(2 * time.Minute)
+	}
+	mockClient.On("GetAssetData", ctx, "ROSETTA").Return(reissued, nil).Once()
+	fetched, err = i.GetAssetInfo(ctx, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, reissued, fetched)
+	mockClient.AssertNumberOfCalls(t, "GetAssetData", 3)
+
+	mockClient.AssertExpectations(t)
+}
@@ -0,0 +1,240 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	storageErrs "github.com/coinbase/rosetta-sdk-go/storage/errors"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// BalanceExportCSV exports balances as CSV rows
+	// of address, currency and balance.
+	BalanceExportCSV = "csv"
+
+	// BalanceExportJSON exports balances as a JSON
+	// array of balanceExportRow.
+	BalanceExportJSON = "json"
+
+	// balanceAccountPrefix prefixes the record of each
+	// account and currency tracked by balance storage
+	// (see modules.GetAccountKey).
+	balanceAccountPrefix = "acc/"
+)
+
+// balanceExportRow is the balance of a single
+// account and currency in a balance export.
+type balanceExportRow struct {
+	Address  string `json:"address"`
+	Currency string `json:"currency"`
+	Balance  string `json:"balance"`
+}
+
+// balanceExportWriter writes the rows of
+// a balance export in some format.
+type balanceExportWriter interface {
+	Write(*balanceExportRow) error
+	Close() error
+}
+
+// csvBalanceExportWriter writes balances as CSV.
+type csvBalanceExportWriter struct {
+	w *csv.Writer
+}
+
+func newCSVBalanceExportWriter(w io.Writer) (*csvBalanceExportWriter, error) {
+	csvWriter := csv.NewWriter(w)
+	if err := csvWriter.Write([]string{"address", "currency", "balance"}); err != nil {
+		return nil, err
+	}
+
+	return &csvBalanceExportWriter{w: csvWriter}, nil
+}
+
+func (c *csvBalanceExportWriter) Write(row *balanceExportRow) error {
+	return c.w.Write([]string{row.Address, row.Currency, row.Balance})
+}
+
+func (c *csvBalanceExportWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonBalanceExportWriter writes balances as a JSON
+// array, one element at a time.
+type jsonBalanceExportWriter struct {
+	w     io.Writer
+	count int
+}
+
+func newJSONBalanceExportWriter(w io.Writer) (*jsonBalanceExportWriter, error) {
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+
+	return &jsonBalanceExportWriter{w: w}, nil
+}
+
+func (j *jsonBalanceExportWriter) Write(row *balanceExportRow) error {
+	encoded, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	if j.count > 0 {
+		if _, err := io.WriteString(j.w, ","); err != nil {
+			return err
+		}
+	}
+	j.count++
+
+	_, err = j.w.Write(encoded)
+	return err
+}
+
+func (j *jsonBalanceExportWriter) Close() error {
+	_, err := io.WriteString(j.w, "]")
+	return err
+}
+
+// ExportBalances writes the balance of every tracked account
+// and currency (RVN and assets) at height to w in format
+// (BalanceExportCSV or BalanceExportJSON). Accounts are read
+// with a prefix scan of balance storage and each row is
+// written as its account is read, so the export is never
+// held in memory. Rows are in storage order (by hash of
+// account and currency), which is the same for every export.
+//
+// ExportBalances is not exposed by the Rosetta API. Operators
+// invoke it through the admin endpoint (see
+// services.AdminMiddleware).
+func (i *Indexer) ExportBalances(
+	ctx context.Context,
+	w io.Writer,
+	height int32,
+	format string,
+) error {
+	dbTx := i.database.ReadTransaction(ctx)
+	defer dbTx.Discard(ctx)
+
+	headBlock, err := i.blockStorage.GetHeadBlockIdentifierTransactional(ctx, dbTx)
+	if err != nil {
+		return fmt.Errorf("%w: unable to get head block", err)
+	}
+
+	if height < 0 || int64(height) > headBlock.Index {
+		return fmt.Errorf("height %d has not been indexed (head is %d)", height, headBlock.Index)
+	}
+
+	// Balances are not pruned with block data, so
+	// any height up to the head can be exported.
+	index := int64(height)
+
+	var writer balanceExportWriter
+	switch format {
+	case BalanceExportCSV:
+		writer, err = newCSVBalanceExportWriter(w)
+	case BalanceExportJSON:
+		writer, err = newJSONBalanceExportWriter(w)
+	default:
+		return fmt.Errorf("%s is not a valid balance export format", format)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: unable to write export header", err)
+	}
+
+	_, err = dbTx.Scan(
+		ctx,
+		[]byte(balanceAccountPrefix),
+		[]byte(balanceAccountPrefix),
+		func(k []byte, v []byte) error {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+
+			var account types.AccountCurrency
+			if err := i.database.Encoder().DecodeAccountCurrency(v, &account, false); err != nil {
+				return fmt.Errorf("%w: unable to decode account %s", err, string(k))
+			}
+
+			return i.exportBalance(ctx, dbTx, writer, &account, index)
+		},
+		false,
+		false,
+	)
+	if err != nil {
+		return fmt.Errorf("%w: unable to export balances", err)
+	}
+
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("%w: unable to finish export", err)
+	}
+
+	return nil
+}
+
+// exportBalance writes the balance of account at
+// index to writer, unless it is zero.
+func (i *Indexer) exportBalance(
+	ctx context.Context,
+	dbTx database.Transaction,
+	writer balanceExportWriter,
+	account *types.AccountCurrency,
+	index int64,
+) error {
+	amount, err := i.balanceStorage.GetBalanceTransactional(
+		ctx,
+		dbTx,
+		account.Account,
+		account.Currency,
+		index,
+	)
+	if errors.Is(err, storageErrs.ErrAccountMissing) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf(
+			"%w: unable to get balance of %s in %s",
+			err,
+			account.Account.Address,
+			account.Currency.Symbol,
+		)
+	}
+
+	// Accounts first funded after height (or
+	// emptied at or before it) have a zero balance
+	// and are left out of the export.
+	if amount.Value == "0" {
+		return nil
+	}
+
+	if err := writer.Write(&balanceExportRow{
+		Address:  account.Account.Address,
+		Currency: account.Currency.Symbol,
+		Balance:  amount.Value,
+	}); err != nil {
+		return fmt.Errorf("%w: unable to write balance", err)
+	}
+
+	return nil
+}
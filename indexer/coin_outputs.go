@@ -0,0 +1,246 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/coinbase/rosetta-sdk-go/storage/database"
+	"github.com/coinbase/rosetta-sdk-go/storage/modules"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/neilotoole/errgroup"
+)
+
+const (
+	// coinOutputNamespace prefixes the output
+	// stored for each coin by coinOutputStorage.
+	coinOutputNamespace = "coin-output"
+
+	// coinSpendNamespace prefixes the record of
+	// each coin spent in a block that has not
+	// been pruned.
+	coinSpendNamespace = "coin-spend"
+)
+
+var (
+	_ modules.BlockWorker = (*coinOutputStorage)(nil)
+
+	// errStopScan stops a scan once
+	// all relevant keys have been read.
+	errStopScan = errors.New("stop scan")
+)
+
+// coinOutputEntry is stored for each coin created.
+type coinOutputEntry struct {
+	BlockIdentifier *types.BlockIdentifier `json:"block_identifier"`
+	Coinbase        bool                   `json:"coinbase,omitempty"`
+	Operation       *types.Operation       `json:"operation"`
+}
+
+// coinOutputStorage keeps the output operation that
+// created each coin, so that the script and amount of
+// unspent coins can still be found once the block that
+// created them is pruned. The outputs of spent coins are
+// dropped when the block that spent them is pruned, as
+// that spend can no longer be rolled back by a reorg.
+type coinOutputStorage struct {
+	db database.Database
+}
+
+func newCoinOutputStorage(db database.Database) *coinOutputStorage {
+	return &coinOutputStorage{db: db}
+}
+
+func coinOutputKey(coinIdentifier string) []byte {
+	return []byte(fmt.Sprintf("%s/%s", coinOutputNamespace, coinIdentifier))
+}
+
+func coinSpendPrefix() []byte {
+	return []byte(fmt.Sprintf("%s/", coinSpendNamespace))
+}
+
+func coinSpendKey(blockIndex int64, coinIdentifier string) []byte {
+	return []byte(fmt.Sprintf(
+		"%s%016x/%s",
+		coinSpendPrefix(),
+		blockIndex,
+		coinIdentifier,
+	))
+}
+
+// coinOperations calls handle with each operation
+// in block that creates or spends a coin.
+func coinOperations(
+	block *types.Block,
+	handle func(*types.Transaction, *types.Operation) error,
+) error {
+	for _, tx := range block.Transactions {
+		for _, op := range tx.Operations {
+			if op.CoinChange == nil {
+				continue
+			}
+
+			if err := handle(tx, op); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// AddingBlock is called by BlockStorage when adding a block.
+func (c *coinOutputStorage) AddingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	err := coinOperations(block, func(tx *types.Transaction, op *types.Operation) error {
+		identifier := op.CoinChange.CoinIdentifier.Identifier
+		if op.CoinChange.CoinAction == types.CoinSpent {
+			if err := transaction.Set(
+				ctx,
+				coinSpendKey(block.BlockIdentifier.Index, identifier),
+				[]byte{},
+				true,
+			); err != nil {
+				return fmt.Errorf("%w: unable to store spend of coin %s", err, identifier)
+			}
+
+			return nil
+		}
+
+		value, err := json.Marshal(&coinOutputEntry{
+			BlockIdentifier: block.BlockIdentifier,
+			Coinbase:        isCoinbaseTransaction(tx),
+			Operation:       op,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: unable to encode output of coin %s", err, identifier)
+		}
+
+		if err := transaction.Set(ctx, coinOutputKey(identifier), value, true); err != nil {
+			return fmt.Errorf("%w: unable to store output of coin %s", err, identifier)
+		}
+
+		return nil
+	})
+
+	return nil, err
+}
+
+// RemovingBlock is called by BlockStorage when removing a block.
+func (c *coinOutputStorage) RemovingBlock(
+	ctx context.Context,
+	g *errgroup.Group,
+	block *types.Block,
+	transaction database.Transaction,
+) (database.CommitWorker, error) {
+	err := coinOperations(block, func(tx *types.Transaction, op *types.Operation) error {
+		identifier := op.CoinChange.CoinIdentifier.Identifier
+		key := coinOutputKey(identifier)
+		if op.CoinChange.CoinAction == types.CoinSpent {
+			key = coinSpendKey(block.BlockIdentifier.Index, identifier)
+		}
+
+		if err := transaction.Delete(ctx, key); err != nil {
+			return fmt.Errorf("%w: unable to delete record of coin %s", err, identifier)
+		}
+
+		return nil
+	})
+
+	return nil, err
+}
+
+// GetOutput returns the output that created coinIdentifier
+// or nil if it is not stored.
+func (c *coinOutputStorage) GetOutput(
+	ctx context.Context,
+	dbTx database.Transaction,
+	coinIdentifier *types.CoinIdentifier,
+) (*coinOutputEntry, error) {
+	exists, value, err := dbTx.Get(ctx, coinOutputKey(coinIdentifier.Identifier))
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get output of coin %s", err, coinIdentifier.Identifier)
+	}
+
+	if !exists {
+		return nil, nil
+	}
+
+	var entry coinOutputEntry
+	if err := json.Unmarshal(value, &entry); err != nil {
+		return nil, fmt.Errorf("%w: unable to decode output of coin %s", err, coinIdentifier.Identifier)
+	}
+
+	return &entry, nil
+}
+
+// PruneSpent drops the outputs of all coins spent in
+// blocks with index <= pruneHeight. It returns the number
+// of outputs dropped.
+func (c *coinOutputStorage) PruneSpent(
+	ctx context.Context,
+	pruneHeight int64,
+) (int, error) {
+	dbTx := c.db.WriteTransaction(ctx, coinOutputNamespace, false)
+	defer dbTx.Discard(ctx)
+
+	lastKey := coinSpendKey(pruneHeight, "")
+	spends := [][]byte{}
+	_, err := dbTx.Scan(
+		ctx,
+		coinSpendPrefix(),
+		coinSpendPrefix(),
+		func(k []byte, v []byte) error {
+			// Keys sort by block index, so no later
+			// key was spent at or below pruneHeight.
+			if bytes.Compare(k[:len(lastKey)], lastKey) > 0 {
+				return errStopScan
+			}
+
+			spends = append(spends, append([]byte{}, k...))
+			return nil
+		},
+		false,
+		false,
+	)
+	if err != nil && !errors.Is(err, errStopScan) {
+		return 0, fmt.Errorf("%w: unable to scan coin spends", err)
+	}
+
+	for _, key := range spends {
+		identifier := string(key[len(lastKey):])
+		if err := dbTx.Delete(ctx, coinOutputKey(identifier)); err != nil {
+			return 0, fmt.Errorf("%w: unable to delete output of coin %s", err, identifier)
+		}
+
+		if err := dbTx.Delete(ctx, key); err != nil {
+			return 0, fmt.Errorf("%w: unable to delete spend of coin %s", err, identifier)
+		}
+	}
+
+	if err := dbTx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("%w: unable to commit pruned coin outputs", err)
+	}
+
+	return len(spends), nil
+}
@@ -0,0 +1,217 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// feeWindowCapacity is the maximum number of recent
+	// blocks that total fees are tracked for.
+	feeWindowCapacity = 1000
+)
+
+var (
+	// ErrInvalidFeeWindow is returned when fee stats are
+	// requested over a window that is not tracked.
+	ErrInvalidFeeWindow = errors.New("invalid fee window")
+)
+
+// FeeStats are statistics of the total fees (in Satoshis)
+// paid in each block of a window of recent blocks.
+type FeeStats struct {
+	// Blocks is the number of blocks the stats
+	// are computed over. This may be less than
+	// the requested window if fewer blocks have been
+	// added since the indexer started.
+	Blocks int `json:"blocks"`
+
+	Average float64 `json:"average"`
+	Median  float64 `json:"median"`
+	Min     int64   `json:"min"`
+	Max     int64   `json:"max"`
+}
+
+// blockFee is the total fee paid in a block.
+type blockFee struct {
+	index int64
+	fee   int64
+}
+
+// feeWindow is a rolling window of the total fees paid
+// in the most recently added blocks, ordered by index.
+type feeWindow struct {
+	mutex sync.Mutex
+	fees  []*blockFee
+}
+
+// add records the total fee of the block at index. Any
+// blocks at or above index (left over from a reorg)
+// are dropped first.
+func (w *feeWindow) add(index int64, fee int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.truncate(index)
+	w.fees = append(w.fees, &blockFee{index: index, fee: fee})
+	if len(w.fees) > feeWindowCapacity {
+		w.fees = w.fees[len(w.fees)-feeWindowCapacity:]
+	}
+}
+
+// remove drops the total fee of the block at index
+// (and any blocks above it).
+func (w *feeWindow) remove(index int64) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.truncate(index)
+}
+
+// truncate drops all blocks at or above index. The
+// caller must hold the mutex.
+func (w *feeWindow) truncate(index int64) {
+	for len(w.fees) > 0 && w.fees[len(w.fees)-1].index >= index {
+		w.fees = w.fees[:len(w.fees)-1]
+	}
+}
+
+// stats returns the *FeeStats of the last
+// windowBlocks blocks.
+func (w *feeWindow) stats(windowBlocks int) *FeeStats {
+	w.mutex.Lock()
+	fees := w.fees
+	if len(fees) > windowBlocks {
+		fees = fees[len(fees)-windowBlocks:]
+	}
+
+	sorted := make([]int64, len(fees))
+	for i, fee := range fees {
+		sorted[i] = fee.fee
+	}
+	w.mutex.Unlock()
+
+	stats := &FeeStats{Blocks: len(sorted)}
+	if len(sorted) == 0 {
+		return stats
+	}
+
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a] < sorted[b] })
+
+	total := int64(0)
+	for _, fee := range sorted {
+		total += fee
+	}
+
+	stats.Average = float64(total) / float64(len(sorted))
+	stats.Min = sorted[0]
+	stats.Max = sorted[len(sorted)-1]
+
+	middle := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		stats.Median = float64(sorted[middle-1]+sorted[middle]) / 2 // nolint:gomnd
+	} else {
+		stats.Median = float64(sorted[middle])
+	}
+
+	return stats
+}
+
+// blockFeeTotal returns the total fee (in Satoshis) paid
+// by the transactions in block. The fee of a transaction
+// is the value of its inputs less the value of its outputs,
+// which is the negated sum of its operation amounts.
+// Coinbase transactions and asset amounts are ignored.
+func blockFeeTotal(block *types.Block, currency *types.Currency) (int64, error) {
+	total := new(big.Int)
+	for _, tx := range block.Transactions {
+		if isCoinbaseTransaction(tx) {
+			continue
+		}
+
+		for _, op := range tx.Operations {
+			if op.Amount == nil || types.Hash(op.Amount.Currency) != types.Hash(currency) {
+				continue
+			}
+
+			value, err := types.BigInt(op.Amount.Value)
+			if err != nil {
+				return 0, fmt.Errorf(
+					"%w: unable to parse amount of transaction %s",
+					err,
+					tx.TransactionIdentifier.Hash,
+				)
+			}
+
+			total.Sub(total, value)
+		}
+	}
+
+	return total.Int64(), nil
+}
+
+// isCoinbaseTransaction returns a boolean indicating
+// if tx is a coinbase transaction.
+func isCoinbaseTransaction(tx *types.Transaction) bool {
+	for _, op := range tx.Operations {
+		if op.Type == ravencoin.CoinbaseOpType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordBlockFee adds the total fee paid
+// in block to the fee window.
+func (i *Indexer) recordBlockFee(block *types.Block) error {
+	fee, err := blockFeeTotal(block, i.currency)
+	if err != nil {
+		return err
+	}
+
+	i.fees.add(block.BlockIdentifier.Index, fee)
+	return nil
+}
+
+// GetRecentFeeStats returns the *FeeStats of the total fees
+// paid in each of the last windowBlocks blocks added to
+// the indexer. The window is kept in memory, so it only
+// includes blocks added since the indexer started.
+func (i *Indexer) GetRecentFeeStats(
+	ctx context.Context,
+	windowBlocks int,
+) (*FeeStats, error) {
+	if windowBlocks <= 0 || windowBlocks > feeWindowCapacity {
+		return nil, fmt.Errorf(
+			"%w: window of %d blocks must be between 1 and %d",
+			ErrInvalidFeeWindow,
+			windowBlocks,
+			feeWindowCapacity,
+		)
+	}
+
+	return i.fees.stats(windowBlocks), nil
+}
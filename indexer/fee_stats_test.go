@@ -0,0 +1,153 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package indexer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func feeTestOperation(opType string, value string, currency *types.Currency) *types.Operation {
+	return &types.Operation{
+		Type: opType,
+		Amount: &types.Amount{
+			Value:    value,
+			Currency: currency,
+		},
+	}
+}
+
+func feeTestBlock(index int64, transactions ...*types.Transaction) *types.Block {
+	coinbase := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: fmt.Sprintf("coinbase %d", index),
+		},
+		Operations: []*types.Operation{
+			{Type: ravencoin.CoinbaseOpType},
+			feeTestOperation(ravencoin.OutputOpType, "500000000000", ravencoin.MainnetCurrency),
+		},
+	}
+
+	return &types.Block{
+		BlockIdentifier: &types.BlockIdentifier{
+			Hash:  getBlockHash(index),
+			Index: index,
+		},
+		Transactions: append([]*types.Transaction{coinbase}, transactions...),
+	}
+}
+
+func feeTestTransaction(hash string, input string, outputs ...string) *types.Transaction {
+	tx := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: hash,
+		},
+		Operations: []*types.Operation{
+			feeTestOperation(ravencoin.InputOpType, "-"+input, ravencoin.MainnetCurrency),
+		},
+	}
+	for _, output := range outputs {
+		tx.Operations = append(
+			tx.Operations,
+			feeTestOperation(ravencoin.OutputOpType, output, ravencoin.MainnetCurrency),
+		)
+	}
+
+	return tx
+}
+
+func TestIndexer_GetRecentFeeStats(t *testing.T) {
+	ctx := context.Background()
+	i := &Indexer{
+		currency: ravencoin.MainnetCurrency,
+		fees:     &feeWindow{},
+	}
+
+	// Asset amounts do not contribute to fees.
+	assetTransfer := feeTestTransaction("asset", "100000", "98000")
+	assetTransfer.Operations = append(
+		assetTransfer.Operations,
+		feeTestOperation(ravencoin.InputOpType, "-500000000", ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)),
+		feeTestOperation(ravencoin.OutputOpType, "400000000", ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)),
+	)
+
+	blocks := []*types.Block{
+		feeTestBlock(1, feeTestTransaction("tx1", "100000", "60000", "39000")),
+		feeTestBlock(2, feeTestTransaction("tx2", "50000", "49000"), assetTransfer),
+		feeTestBlock(3),
+		feeTestBlock(4, feeTestTransaction("tx4", "1000000", "994000")),
+	}
+	for _, block := range blocks {
+		assert.NoError(t, i.recordBlockFee(block))
+	}
+
+	// Block fees are [1000, 3000, 0, 6000].
+	stats, err := i.GetRecentFeeStats(ctx, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeStats{
+		Blocks:  3,
+		Average: 3000,
+		Median:  3000,
+		Min:     0,
+		Max:     6000,
+	}, stats)
+
+	// A window larger than the number of blocks
+	// added includes all blocks.
+	stats, err = i.GetRecentFeeStats(ctx, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeStats{
+		Blocks:  4,
+		Average: 2500,
+		Median:  2000,
+		Min:     0,
+		Max:     6000,
+	}, stats)
+
+	// Blocks removed in a reorg leave the window.
+	i.fees.remove(4)
+	assert.NoError(t, i.recordBlockFee(feeTestBlock(4, feeTestTransaction("tx4b", "1000", "500"))))
+	stats, err = i.GetRecentFeeStats(ctx, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, &FeeStats{
+		Blocks:  2,
+		Average: 250,
+		Median:  250,
+		Min:     0,
+		Max:     500,
+	}, stats)
+
+	// The window never grows beyond its capacity.
+	for index := int64(5); index < feeWindowCapacity+10; index++ {
+		i.fees.add(index, 1)
+	}
+	stats, err = i.GetRecentFeeStats(ctx, feeWindowCapacity)
+	assert.NoError(t, err)
+	assert.Equal(t, feeWindowCapacity, stats.Blocks)
+	assert.Equal(t, int64(1), stats.Min)
+
+	_, err = i.GetRecentFeeStats(ctx, 0)
+	assert.True(t, errors.Is(err, ErrInvalidFeeWindow))
+
+	_, err = i.GetRecentFeeStats(ctx, feeWindowCapacity+1)
+	assert.True(t, errors.Is(err, ErrInvalidFeeWindow))
+}
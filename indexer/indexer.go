@@ -81,6 +81,9 @@ type Client interface {
 	NetworkStatus(context.Context) (*types.NetworkStatusResponse, error)
 	PruneBlockchain(context.Context, int64) (int64, error)
 	GetRawBlock(context.Context, *types.PartialBlockIdentifier) (*ravencoin.Block, []string, error)
+	GetCoin(context.Context, string) (*types.AccountCoin, int64, error)
+	GetHashFromIndex(context.Context, int64) (string, error)
+	GetAssetData(context.Context, string) (*ravencoin.AssetInfo, error)
 	ParseBlock(
 		context.Context,
 		*ravencoin.Block,
@@ -99,6 +102,35 @@ type Indexer struct {
 	network       *types.NetworkIdentifier
 	pruningConfig *configuration.PruningConfiguration
 
+	// syncStartHeight is the height we begin syncing
+	// from if there is no head block in storage.
+	syncStartHeight int64
+
+	// coinbaseMaturity is the number of confirmations
+	// required before a coinbase output can be spent.
+	coinbaseMaturity int64
+
+	// maxServableHeight is the highest block we sync
+	// (if populated).
+	maxServableHeight int64
+
+	// pruneDepth is the number of blocks below the
+	// tip that block data is kept for (if populated).
+	pruneDepth int64
+
+	// currency is the native currency, used to
+	// compute the total fees paid in each block
+	// and the balances of scanned addresses.
+	currency *types.Currency
+
+	// fees tracks the total fees paid in
+	// recently added blocks.
+	fees *feeWindow
+
+	// assetInfo caches asset metadata fetched
+	// from ravend (nil if caching is disabled).
+	assetInfo *assetInfoCache
+
 	client Client
 
 	asserter       *asserter.Asserter
@@ -106,8 +138,13 @@ type Indexer struct {
 	blockStorage   *modules.BlockStorage
 	balanceStorage *modules.BalanceStorage
 	coinStorage    *modules.CoinStorage
+	addressHistory *addressHistoryStorage
 	workers        []modules.BlockWorker
 
+	// coinOutputs is nil if indexed
+	// blocks are not pruned.
+	coinOutputs *coinOutputStorage
+
 	waiter *waitTable
 
 	// Store coins created in pre-store before persisted
@@ -122,7 +159,22 @@ type Indexer struct {
 	seen      int64
 	seenMutex sync.Mutex
 
+	// seenThrough is the height every block since we
+	// began syncing has been seen (or added) through.
+	// seenAbove are the heights seen above it. Both are
+	// guarded by seenMutex.
+	seenThrough int64
+	seenAbove   map[int64]struct{}
+
 	seenSemaphore *semaphore.Weighted
+
+	// orphaned are the hashes of the blocks removed
+	// since a block was last added. onReorg (if
+	// populated) is called with them once the next
+	// block is added.
+	orphaned   []string
+	onReorg    func(commonAncestor int64, orphaned []string)
+	reorgMutex sync.Mutex
 }
 
 // CloseDatabase closes a storage.Database. This should be called
@@ -229,6 +281,30 @@ func Initialize(
 		coinCache:      map[string]*types.AccountCoin{},
 		coinCacheMutex: new(sdkUtils.PriorityMutex),
 		seenSemaphore:  semaphore.NewWeighted(int64(runtime.NumCPU())),
+		seenAbove:      map[int64]struct{}{},
+
+		syncStartHeight:   config.SyncStartHeight,
+		maxServableHeight: config.MaxServableHeight,
+
+		currency: config.Currency,
+		fees:     &feeWindow{},
+	}
+
+	if config.Params != nil {
+		i.coinbaseMaturity = int64(config.Params.CoinbaseMaturity)
+	}
+
+	if config.AssetInfoCache != nil {
+		i.assetInfo = newAssetInfoCache(config.AssetInfoCache)
+	}
+
+	if config.PruneDepth > 0 {
+		// Blocks that may still be reorged are never
+		// pruned, so their coins can be rolled back.
+		i.pruneDepth = config.PruneDepth
+		if i.pruneDepth < config.ConfirmationDepth {
+			i.pruneDepth = config.ConfirmationDepth
+		}
 	}
 
 	coinStorage := modules.NewCoinStorage(
@@ -245,7 +321,16 @@ func Initialize(
 	)
 	i.balanceStorage = balanceStorage
 
-	i.workers = []modules.BlockWorker{coinStorage, balanceStorage}
+	i.addressHistory = newAddressHistoryStorage(localStore)
+
+	i.workers = []modules.BlockWorker{coinStorage, balanceStorage, i.addressHistory}
+
+	// The outputs of unspent coins must outlive
+	// the blocks that created them.
+	if i.pruneDepth > 0 {
+		i.coinOutputs = newCoinOutputStorage(localStore)
+		i.workers = append(i.workers, i.coinOutputs)
+	}
 
 	return i, nil
 }
@@ -277,11 +362,19 @@ func (i *Indexer) Sync(ctx context.Context) error {
 	i.blockStorage.Initialize(i.workers)
 
 	startIndex := int64(indexPlaceholder)
+	if i.syncStartHeight > 0 {
+		startIndex = i.syncStartHeight
+	}
+
 	head, err := i.blockStorage.GetHeadBlockIdentifier(ctx)
 	if err == nil {
 		startIndex = head.Index + 1
 	}
 
+	i.seenMutex.Lock()
+	i.seenThrough = startIndex - 1
+	i.seenMutex.Unlock()
+
 	// Load in previous blocks into syncer cache to handle reorgs.
 	// If previously processed blocks exist in storage, they are fetched.
 	// Otherwise, none are provided to the cache (the syncer will not attempt
@@ -344,10 +437,52 @@ func (i *Indexer) Prune(ctx context.Context) error {
 	}
 }
 
+// OnReorg registers callback to be called when blocks are
+// orphaned by a reorg. It is called with the height of the
+// last block shared by both chains and the hashes of the
+// orphaned blocks (from the old tip down), once the coins
+// they created and spent have been rolled back and before
+// the first block of the new chain is added.
+func (i *Indexer) OnReorg(callback func(commonAncestor int64, orphaned []string)) {
+	i.reorgMutex.Lock()
+	defer i.reorgMutex.Unlock()
+
+	i.onReorg = callback
+}
+
+// reportReorg calls the OnReorg callback with the blocks
+// removed since a block was last added, if there are any.
+func (i *Indexer) reportReorg(ctx context.Context, commonAncestor int64) {
+	i.reorgMutex.Lock()
+	orphaned := i.orphaned
+	callback := i.onReorg
+	i.orphaned = nil
+	i.reorgMutex.Unlock()
+
+	if len(orphaned) == 0 {
+		return
+	}
+
+	logger := utils.ExtractLogger(ctx, "indexer")
+	logger.Infow(
+		"reorg",
+		"common ancestor", commonAncestor,
+		"orphaned", orphaned,
+	)
+
+	if callback != nil {
+		callback(commonAncestor, orphaned)
+	}
+}
+
 // BlockAdded is called by the syncer when a block is added.
 func (i *Indexer) BlockAdded(ctx context.Context, block *types.Block) error {
 	logger := utils.ExtractLogger(ctx, "indexer")
 
+	// All blocks orphaned by a reorg are removed
+	// before the new chain is added.
+	i.reportReorg(ctx, block.ParentBlockIdentifier.Index)
+
 	err := i.blockStorage.AddBlock(ctx, block)
 	if err != nil {
 		return fmt.Errorf(
@@ -358,6 +493,33 @@ func (i *Indexer) BlockAdded(ctx context.Context, block *types.Block) error {
 		)
 	}
 
+	if err := i.recordBlockFee(block); err != nil {
+		return fmt.Errorf(
+			"%w: unable to record fee of block %s:%d",
+			err,
+			block.BlockIdentifier.Hash,
+			block.BlockIdentifier.Index,
+		)
+	}
+
+	if err := i.invalidateReissuedAssets(block); err != nil {
+		return fmt.Errorf(
+			"%w: unable to invalidate reissued assets in block %s:%d",
+			err,
+			block.BlockIdentifier.Hash,
+			block.BlockIdentifier.Index,
+		)
+	}
+
+	if err := i.pruneBlocks(ctx, block.BlockIdentifier.Index); err != nil {
+		return fmt.Errorf(
+			"%w: unable to prune blocks below %s:%d",
+			err,
+			block.BlockIdentifier.Hash,
+			block.BlockIdentifier.Index,
+		)
+	}
+
 	ops := 0
 	for _, transaction := range block.Transactions {
 		ops += len(transaction.Operations)
@@ -410,6 +572,44 @@ func (i *Indexer) BlockAdded(ctx context.Context, block *types.Block) error {
 	return nil
 }
 
+// pruneBlocks prunes the data of all blocks more than
+// pruneDepth below tip (if pruneDepth is populated). Coin
+// storage is not pruned and the outputs of unspent coins
+// are kept, so only the record of the coins spent in these
+// blocks is dropped.
+func (i *Indexer) pruneBlocks(ctx context.Context, tip int64) error {
+	if i.pruneDepth == 0 {
+		return nil
+	}
+
+	pruneHeight := tip - i.pruneDepth
+	if pruneHeight < 0 {
+		return nil
+	}
+
+	firstPruned, lastPruned, err := i.blockStorage.Prune(ctx, pruneHeight, i.pruneDepth)
+	if err != nil {
+		return err
+	}
+
+	prunedOutputs, err := i.coinOutputs.PruneSpent(ctx, pruneHeight)
+	if err != nil {
+		return err
+	}
+
+	if lastPruned >= 0 {
+		logger := utils.ExtractLogger(ctx, "indexer")
+		logger.Debugw(
+			"pruned blocks",
+			"first", firstPruned,
+			"last", lastPruned,
+			"spent outputs", prunedOutputs,
+		)
+	}
+
+	return nil
+}
+
 // BlockSeen is called by the syncer when a block is encountered.
 func (i *Indexer) BlockSeen(ctx context.Context, block *types.Block) error {
 	if err := i.seenSemaphore.Acquire(ctx, semaphoreWeight); err != nil {
@@ -446,6 +646,7 @@ func (i *Indexer) BlockSeen(ctx context.Context, block *types.Block) error {
 	// Update so that lookers know it exists
 	i.seenMutex.Lock()
 	i.seen++
+	seenThrough := i.markSeen(block.BlockIdentifier.Index)
 	i.seenMutex.Unlock()
 
 	err := i.blockStorage.SeeBlock(ctx, block)
@@ -484,6 +685,27 @@ func (i *Indexer) BlockSeen(ctx context.Context, block *types.Block) error {
 		val.channelClosed = true
 		close(val.channel)
 	}
+
+	// Coins that are still missing once every block before
+	// the block spending them has been seen were created
+	// before we began syncing, so those waiting on them
+	// can fetch them from ravend instead.
+	if i.syncStartHeight > 0 {
+		for txHash, val := range i.waiter.table {
+			if val.channelClosed || val.earliestBlock > seenThrough+1 {
+				continue
+			}
+
+			logger.Debugw(
+				"releasing channel",
+				"hash", block.BlockIdentifier.Hash,
+				"index", block.BlockIdentifier.Index,
+				"channel", txHash,
+			)
+			val.channelClosed = true
+			close(val.channel)
+		}
+	}
 	i.waiter.Unlock()
 
 	logger.Debugw(
@@ -495,6 +717,26 @@ func (i *Indexer) BlockSeen(ctx context.Context, block *types.Block) error {
 	return nil
 }
 
+// markSeen records that the block at height has been seen
+// and returns the height all blocks have been seen through.
+// seenMutex must be held.
+func (i *Indexer) markSeen(height int64) int64 {
+	if height > i.seenThrough {
+		i.seenAbove[height] = struct{}{}
+	}
+
+	for {
+		if _, ok := i.seenAbove[i.seenThrough+1]; !ok {
+			break
+		}
+
+		delete(i.seenAbove, i.seenThrough+1)
+		i.seenThrough++
+	}
+
+	return i.seenThrough
+}
+
 // BlockRemoved is called by the syncer when a block is removed.
 func (i *Indexer) BlockRemoved(
 	ctx context.Context,
@@ -516,6 +758,32 @@ func (i *Indexer) BlockRemoved(
 		)
 	}
 
+	i.fees.remove(blockIdentifier.Index)
+
+	// The blocks replacing the removed block
+	// must be seen again.
+	i.seenMutex.Lock()
+	if i.seenThrough >= blockIdentifier.Index {
+		i.seenThrough = blockIdentifier.Index - 1
+	}
+	for height := range i.seenAbove {
+		if height >= blockIdentifier.Index {
+			delete(i.seenAbove, height)
+		}
+	}
+	i.seenMutex.Unlock()
+
+	i.reorgMutex.Lock()
+	i.orphaned = append(i.orphaned, blockIdentifier.Hash)
+	i.reorgMutex.Unlock()
+
+	// We don't know which assets were reissued in
+	// the removed block, so all asset metadata is
+	// fetched again.
+	if i.assetInfo != nil {
+		i.assetInfo.purge()
+	}
+
 	return nil
 }
 
@@ -525,16 +793,42 @@ func (i *Indexer) NetworkStatus(
 	ctx context.Context,
 	network *types.NetworkIdentifier,
 ) (*types.NetworkStatusResponse, error) {
-	return i.client.NetworkStatus(ctx)
+	status, err := i.client.NetworkStatus(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// We report the max servable height as the tip
+	// so that the syncer never fetches blocks above it.
+	if i.maxServableHeight <= 0 || status.CurrentBlockIdentifier.Index <= i.maxServableHeight {
+		return status, nil
+	}
+
+	hash, err := i.client.GetHashFromIndex(ctx, i.maxServableHeight)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get hash of max servable block", err)
+	}
+
+	status.CurrentBlockIdentifier = &types.BlockIdentifier{
+		Index: i.maxServableHeight,
+		Hash:  hash,
+	}
+
+	return status, nil
 }
 
 func (i *Indexer) findCoin(
 	ctx context.Context,
 	btcBlock *ravencoin.Block,
 	coinIdentifier string,
+	preStartCoins map[string]struct{},
 ) (*types.Coin, *types.AccountIdentifier, error) {
 	for ctx.Err() == nil {
+		i.seenMutex.Lock()
 		startSeen := i.seen
+		seenThrough := i.seenThrough
+		i.seenMutex.Unlock()
+
 		databaseTransaction := i.database.ReadTransaction(ctx)
 		defer databaseTransaction.Discard(ctx)
 
@@ -600,6 +894,30 @@ func (i *Indexer) findCoin(
 			continue
 		}
 
+		// If we did not sync from genesis and every block since
+		// we began syncing that could have created the coin has
+		// been seen, the coin was created before we started
+		// syncing. In this case, we fetch it from ravend as we
+		// will never see it.
+		if i.syncStartHeight > 0 && seenThrough >= btcBlock.Height-1 {
+			i.waiter.Unlock()
+
+			accCoin, height, err := i.client.GetCoin(ctx, coinIdentifier)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%w: unable to get coin %s", err, coinIdentifier)
+			}
+
+			if height < i.syncStartHeight {
+				preStartCoins[coinIdentifier] = struct{}{}
+				return accCoin.Coin, accCoin.Account, nil
+			}
+
+			// The coin was created after we started syncing but
+			// is not in any block we have seen, so btcBlock is
+			// not on the chain we have seen.
+			return nil, nil, syncer.ErrOrphanHead
+		}
+
 		// Put Transaction in WaitTable if doesn't already exist (could be
 		// multiple listeners)
 		transactionHash := ravencoin.TransactionHash(coinIdentifier)
@@ -643,10 +961,13 @@ func (i *Indexer) checkHeaderMatch(
 	return nil
 }
 
+// findCoins returns the coins spent in btcBlock. Any coins
+// created before syncStartHeight are added to preStartCoins.
 func (i *Indexer) findCoins(
 	ctx context.Context,
 	btcBlock *ravencoin.Block,
 	coins []string,
+	preStartCoins map[string]struct{},
 ) (map[string]*types.AccountCoin, error) {
 	if err := i.checkHeaderMatch(ctx, btcBlock); err != nil {
 		return nil, fmt.Errorf("%w: check header match failed", err)
@@ -659,6 +980,7 @@ func (i *Indexer) findCoins(
 			ctx,
 			btcBlock,
 			coinIdentifier,
+			preStartCoins,
 		)
 		if err == nil {
 			coinMap[coinIdentifier] = &types.AccountCoin{
@@ -727,7 +1049,7 @@ func (i *Indexer) findCoins(
 	// In the case of a reorg, we may still not be able to find
 	// the transactions. So, we need to repeat this same process
 	// recursively until we find the transactions we are looking for.
-	foundCoins, err := i.findCoins(ctx, btcBlock, remainingCoins)
+	foundCoins, err := i.findCoins(ctx, btcBlock, remainingCoins, preStartCoins)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to get remaining transactions", err)
 	}
@@ -767,8 +1089,19 @@ func (i *Indexer) Block(
 		}
 	}
 
+	// ensure the transactions we fetched are committed
+	// to by the block header
+	if err := ravencoin.VerifyMerkleRoot(btcBlock); err != nil {
+		return nil, fmt.Errorf("%w: unable to verify block %+v", err, blockIdentifier)
+	}
+
+	if err := ravencoin.VerifyWitnessCommitment(btcBlock); err != nil {
+		return nil, fmt.Errorf("%w: unable to verify block witnesses %+v", err, blockIdentifier)
+	}
+
 	// determine which coins must be fetched and get from coin storage
-	coinMap, err := i.findCoins(ctx, btcBlock, coins)
+	preStartCoins := map[string]struct{}{}
+	coinMap, err := i.findCoins(ctx, btcBlock, coins, preStartCoins)
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to find input transactions", err)
 	}
@@ -779,6 +1112,23 @@ func (i *Indexer) Block(
 		return nil, fmt.Errorf("%w: unable to parse block %+v", err, blockIdentifier)
 	}
 
+	// Spending coins created before we started syncing would
+	// make balances negative (we never saw them created), so
+	// we mark these operations as skipped.
+	if len(preStartCoins) > 0 {
+		for _, tx := range block.Transactions {
+			for _, op := range tx.Operations {
+				if op.CoinChange == nil || op.CoinChange.CoinAction != types.CoinSpent {
+					continue
+				}
+
+				if _, ok := preStartCoins[op.CoinChange.CoinIdentifier.Identifier]; ok {
+					op.Status = types.String(ravencoin.SkippedStatus)
+				}
+			}
+		}
+	}
+
 	// ensure block is valid
 	if err := i.asserter.Block(block); err != nil {
 		return nil, fmt.Errorf("%w: block is not valid %+v", err, blockIdentifier)
@@ -787,10 +1137,68 @@ func (i *Indexer) Block(
 	return block, nil
 }
 
+// findCoinOutput returns the output operation that
+// created the coin with coinIdentifier, along with the
+// block it was created in. The outputs of unspent coins
+// are found even if that block has been pruned.
+func (i *Indexer) findCoinOutput(
+	ctx context.Context,
+	databaseTransaction database.Transaction,
+	coinIdentifier *types.CoinIdentifier,
+) (*coinOutputEntry, error) {
+	if i.coinOutputs != nil {
+		entry, err := i.coinOutputs.GetOutput(ctx, databaseTransaction, coinIdentifier)
+		if err != nil {
+			return nil, err
+		}
+
+		if entry != nil {
+			return entry, nil
+		}
+	}
+
+	transactionHash, networkIndex, err := ravencoin.ParseCoinIdentifier(coinIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse coin identifier", err)
+	}
+
+	blockIdentifier, transaction, err := i.blockStorage.FindTransaction(
+		ctx,
+		&types.TransactionIdentifier{Hash: transactionHash.String()},
+		databaseTransaction,
+	)
+	if err != nil || transaction == nil {
+		return nil, fmt.Errorf(
+			"%w: unable to find transaction %s",
+			err,
+			transactionHash.String(),
+		)
+	}
+
+	for _, op := range transaction.Operations {
+		if op.Type != ravencoin.OutputOpType {
+			continue
+		}
+
+		if *op.OperationIdentifier.NetworkIndex != int64(networkIndex) {
+			continue
+		}
+
+		return &coinOutputEntry{
+			BlockIdentifier: blockIdentifier,
+			Coinbase:        isCoinbaseTransaction(transaction),
+			Operation:       op,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unable to find output for coin %s", coinIdentifier.Identifier)
+}
+
 // GetScriptPubKeys gets the ScriptPubKey for
 // a collection of *types.CoinIdentifier. It also
-// confirms that the amount provided with each coin
-// is valid.
+// confirms that the currency provided with each
+// coin is valid. The amount of each coin is
+// checked against GetCoinAmounts by callers.
 func (i *Indexer) GetScriptPubKeys(
 	ctx context.Context,
 	coins []*types.Coin,
@@ -800,73 +1208,60 @@ func (i *Indexer) GetScriptPubKeys(
 
 	scripts := make([]*ravencoin.ScriptPubKey, len(coins))
 	for j, coin := range coins {
-		coinIdentifier := coin.CoinIdentifier
-		transactionHash, networkIndex, err := ravencoin.ParseCoinIdentifier(coinIdentifier)
+		output, err := i.findCoinOutput(ctx, databaseTransaction, coin.CoinIdentifier)
 		if err != nil {
-			return nil, fmt.Errorf("%w: unable to parse coin identifier", err)
+			return nil, err
 		}
+		op := output.Operation
 
-		_, transaction, err := i.blockStorage.FindTransaction(
-			ctx,
-			&types.TransactionIdentifier{Hash: transactionHash.String()},
-			databaseTransaction,
-		)
-		if err != nil || transaction == nil {
+		var opMetadata ravencoin.OperationMetadata
+		if err := types.UnmarshalMap(op.Metadata, &opMetadata); err != nil {
 			return nil, fmt.Errorf(
-				"%w: unable to find transaction %s",
+				"%w: unable to unmarshal operation metadata %+v",
 				err,
-				transactionHash.String(),
+				op.Metadata,
 			)
 		}
 
-		for _, op := range transaction.Operations {
-			if op.Type != ravencoin.OutputOpType {
-				continue
-			}
-
-			if *op.OperationIdentifier.NetworkIndex != int64(networkIndex) {
-				continue
-			}
+		if types.Hash(op.Amount.Currency) != types.Hash(coin.Amount.Currency) {
+			return nil, fmt.Errorf(
+				"currency expected %s does not match coin %s",
+				types.PrintStruct(coin.Amount.Currency),
+				types.PrintStruct(op.Amount.Currency),
+			)
+		}
 
-			var opMetadata ravencoin.OperationMetadata
-			if err := types.UnmarshalMap(op.Metadata, &opMetadata); err != nil {
-				return nil, fmt.Errorf(
-					"%w: unable to unmarshal operation metadata %+v",
-					err,
-					op.Metadata,
-				)
-			}
+		if opMetadata.ScriptPubKey == nil {
+			return nil, fmt.Errorf("unable to find script for coin %s", coin.CoinIdentifier.Identifier)
+		}
 
-			if types.Hash(op.Amount.Currency) != types.Hash(coin.Amount.Currency) {
-				return nil, fmt.Errorf(
-					"currency expected %s does not match coin %s",
-					types.PrintStruct(coin.Amount.Currency),
-					types.PrintStruct(op.Amount.Currency),
-				)
-			}
+		scripts[j] = opMetadata.ScriptPubKey
+	}
 
-			addition, err := types.AddValues(op.Amount.Value, coin.Amount.Value)
-			if err != nil {
-				return nil, fmt.Errorf("%w: unable to add op amount and coin amount", err)
-			}
+	return scripts, nil
+}
 
-			if addition != "0" {
-				return nil, fmt.Errorf(
-					"coin amount does not match expected with difference %s",
-					addition,
-				)
-			}
+// GetCoinAmounts returns the *types.Amount recorded when
+// each of coins was created. Unlike the amount of a
+// *types.Coin spent by an input operation, it is positive.
+func (i *Indexer) GetCoinAmounts(
+	ctx context.Context,
+	coins []*types.Coin,
+) ([]*types.Amount, error) {
+	databaseTransaction := i.database.ReadTransaction(ctx)
+	defer databaseTransaction.Discard(ctx)
 
-			scripts[j] = opMetadata.ScriptPubKey
-			break
+	amounts := make([]*types.Amount, len(coins))
+	for j, coin := range coins {
+		output, err := i.findCoinOutput(ctx, databaseTransaction, coin.CoinIdentifier)
+		if err != nil {
+			return nil, err
 		}
 
-		if scripts[j] == nil {
-			return nil, fmt.Errorf("unable to find script for coin %s", coinIdentifier.Identifier)
-		}
+		amounts[j] = output.Operation.Amount
 	}
 
-	return scripts, nil
+	return amounts, nil
 }
 
 // GetBlockLazy returns a *types.BlockResponse from the indexer's block storage.
@@ -939,3 +1334,226 @@ func (i *Indexer) GetBalance(
 
 	return amount, blockResponse.Block.BlockIdentifier, nil
 }
+
+// GetAssetBalance returns the current balance of
+// asset held by an account. Owner tokens are queried
+// by their full name (i.e. ASSETNAME!).
+func (i *Indexer) GetAssetBalance(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+	asset string,
+) (*types.Amount, error) {
+	amount, _, err := i.GetBalance(
+		ctx,
+		accountIdentifier,
+		ravencoin.AssetCurrency(asset, ravencoin.MaxAssetUnits),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get balance of asset %s", err, asset)
+	}
+
+	return amount, nil
+}
+
+// GetAssetCoins returns all unspent coins carrying asset
+// for a particular *types.AccountIdentifier. Coins carrying
+// an asset are stored in the currency of the asset (parsed
+// from the OP_RVN_ASSET script suffix), so they can be
+// selected independently of coins carrying RVN.
+func (i *Indexer) GetAssetCoins(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+	asset string,
+) ([]*types.Coin, error) {
+	coins, _, err := i.GetCoins(ctx, accountIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get coins", err)
+	}
+
+	assetCoins := []*types.Coin{}
+	for _, coin := range coins {
+		if coin.Amount.Currency.Symbol == asset {
+			assetCoins = append(assetCoins, coin)
+		}
+	}
+
+	return assetCoins, nil
+}
+
+// GetTransactionsByAddress returns the transactions with an
+// operation on addr (sending, receiving or carrying an asset),
+// newest first. offset transactions are skipped and at most
+// limit transactions are returned. Transactions in pruned
+// blocks are not returned, so the history of addr ends at
+// the oldest block that has not been pruned.
+func (i *Indexer) GetTransactionsByAddress(
+	ctx context.Context,
+	addr string,
+	limit int,
+	offset int,
+) ([]*types.Transaction, error) {
+	if limit <= 0 || offset < 0 {
+		return nil, fmt.Errorf("invalid limit %d or offset %d", limit, offset)
+	}
+
+	entries, err := i.addressHistory.GetEntries(ctx, addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get history of %s", err, addr)
+	}
+
+	if offset >= len(entries) {
+		return []*types.Transaction{}, nil
+	}
+
+	entries = entries[offset:]
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	transactions := make([]*types.Transaction, 0, len(entries))
+	for _, entry := range entries {
+		transaction, err := i.GetBlockTransaction(
+			ctx,
+			entry.BlockIdentifier,
+			entry.TransactionIdentifier,
+		)
+		if errors.Is(err, storageErrs.ErrCannotAccessPrunedData) {
+			// All remaining entries are older.
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to get transaction %s in block %s:%d",
+				err,
+				entry.TransactionIdentifier.Hash,
+				entry.BlockIdentifier.Hash,
+				entry.BlockIdentifier.Index,
+			)
+		}
+
+		transactions = append(transactions, transaction)
+	}
+
+	return transactions, nil
+}
+
+// AddressUsed returns true if addr has any transactions
+// (sending, receiving or carrying an asset). An address
+// whose coins have all been spent is still used.
+func (i *Indexer) AddressUsed(ctx context.Context, addr string) (bool, error) {
+	entries, err := i.addressHistory.GetEntries(ctx, addr)
+	if err != nil {
+		return false, fmt.Errorf("%w: unable to get history of %s", err, addr)
+	}
+
+	return len(entries) > 0, nil
+}
+
+// ScanAddresses returns the current balance of the native
+// currency held by each of addrs (i.e. the addresses derived
+// from an HD wallet, in derivation order). The scan stops once
+// gapLimit consecutive addresses are unused, so addresses after
+// such a gap are not returned. An address is unused if it has
+// no transactions (see AddressUsed).
+func (i *Indexer) ScanAddresses(
+	ctx context.Context,
+	addrs []string,
+	gapLimit int,
+) (map[string]*types.Amount, error) {
+	if gapLimit <= 0 {
+		return nil, fmt.Errorf("invalid gap limit %d", gapLimit)
+	}
+
+	balances := map[string]*types.Amount{}
+	gap := 0
+	for _, addr := range addrs {
+		if gap >= gapLimit {
+			break
+		}
+
+		used, err := i.AddressUsed(ctx, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		if used {
+			gap = 0
+		} else {
+			gap++
+		}
+
+		amount, _, err := i.GetBalance(
+			ctx,
+			&types.AccountIdentifier{Address: addr},
+			i.currency,
+			nil,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to get balance of %s", err, addr)
+		}
+
+		balances[addr] = amount
+	}
+
+	return balances, nil
+}
+
+// GetSpendableCoins returns all unspent coins for a particular
+// *types.AccountIdentifier, flagging coinbase outputs that cannot
+// yet be spent because they have fewer than coinbaseMaturity
+// confirmations.
+func (i *Indexer) GetSpendableCoins(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+) ([]*ravencoin.SpendableCoin, *types.BlockIdentifier, error) {
+	coins, headBlock, err := i.GetCoins(ctx, accountIdentifier)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to get coins", err)
+	}
+
+	databaseTransaction := i.database.ReadTransaction(ctx)
+	defer databaseTransaction.Discard(ctx)
+
+	spendableCoins := make([]*ravencoin.SpendableCoin, len(coins))
+	for j, coin := range coins {
+		output, err := i.findCoinOutput(ctx, databaseTransaction, coin.CoinIdentifier)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		spendableCoins[j] = ravencoin.NewSpendableCoin(
+			coin,
+			accountIdentifier.Address,
+			output.Coinbase,
+			output.BlockIdentifier.Index,
+			headBlock.Index,
+			i.coinbaseMaturity,
+		)
+	}
+
+	return spendableCoins, headBlock, nil
+}
+
+// GetImmatureCoinbaseCoins returns all unspent coinbase coins
+// for a particular *types.AccountIdentifier that cannot yet be
+// spent because they have fewer than coinbaseMaturity
+// confirmations.
+func (i *Indexer) GetImmatureCoinbaseCoins(
+	ctx context.Context,
+	accountIdentifier *types.AccountIdentifier,
+) ([]*types.Coin, error) {
+	coins, _, err := i.GetSpendableCoins(ctx, accountIdentifier)
+	if err != nil {
+		return nil, err
+	}
+
+	immatureCoins := []*types.Coin{}
+	for _, coin := range coins {
+		if coin.Immature {
+			immatureCoins = append(immatureCoins, coin.Coin)
+		}
+	}
+
+	return immatureCoins, nil
+}
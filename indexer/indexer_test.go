@@ -15,11 +15,14 @@
 package indexer
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/rand"
+	"strings"
 	"testing"
 	"time"
 
@@ -402,6 +405,7 @@ func TestIndexer_Transactions(t *testing.T) {
 				// Ensure ScriptPubKeys are accessible.
 				allCoins := []*types.Coin{}
 				expectedPubKeys := []*ravencoin.ScriptPubKey{}
+				expectedValues := []string{}
 				for k, v := range coinBank {
 					allCoins = append(allCoins, &types.Coin{
 						CoinIdentifier: &types.CoinIdentifier{Identifier: k},
@@ -411,12 +415,22 @@ func TestIndexer_Transactions(t *testing.T) {
 						},
 					})
 					expectedPubKeys = append(expectedPubKeys, v.Script)
+					expectedValues = append(expectedValues, v.Coin.Amount.Value)
 				}
 
 				pubKeys, err := i.GetScriptPubKeys(ctx, allCoins)
 				assert.NoError(t, err)
 				assert.Equal(t, expectedPubKeys, pubKeys)
 
+				// Ensure the indexed value of each coin is accessible.
+				amounts, err := i.GetCoinAmounts(ctx, allCoins)
+				assert.NoError(t, err)
+				values := make([]string, len(amounts))
+				for j, amount := range amounts {
+					values[j] = amount.Value
+				}
+				assert.Equal(t, expectedValues, values)
+
 				cancel()
 				close(waitForFinish)
 				return
@@ -836,3 +850,1291 @@ func TestIndexer_HeaderReorg(t *testing.T) {
 	assert.Len(t, i.waiter.table, 0)
 	mockClient.AssertExpectations(t)
 }
+
+func TestIndexer_OnReorg(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Blocks are added directly instead of by Sync,
+	// so the workers must be registered here.
+	i.blockStorage.Initialize(i.workers)
+
+	// Each block pays a single coin to its own account.
+	account := func(hash string) *types.AccountIdentifier {
+		return &types.AccountIdentifier{Address: "address of " + hash}
+	}
+	block := func(hash string, index int64, parentHash string) *types.Block {
+		txHash := fmt.Sprintf("%x", sha256.Sum256([]byte(hash)))
+		return &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  hash,
+				Index: index,
+			},
+			ParentBlockIdentifier: &types.BlockIdentifier{
+				Hash:  parentHash,
+				Index: index - 1,
+			},
+			Timestamp: 1599002115110,
+			Transactions: []*types.Transaction{
+				{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: txHash,
+					},
+					Operations: []*types.Operation{
+						{
+							OperationIdentifier: &types.OperationIdentifier{
+								Index:        0,
+								NetworkIndex: &index0,
+							},
+							Status:  types.String(ravencoin.SuccessStatus),
+							Type:    ravencoin.OutputOpType,
+							Account: account(hash),
+							Amount: &types.Amount{
+								Value:    "1000",
+								Currency: ravencoin.MainnetCurrency,
+							},
+							CoinChange: &types.CoinChange{
+								CoinAction: types.CoinCreated,
+								CoinIdentifier: &types.CoinIdentifier{
+									Identifier: txHash + ":0",
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+	}
+	add := func(b *types.Block) {
+		assert.NoError(t, i.BlockSeen(ctx, b))
+		assert.NoError(t, i.BlockAdded(ctx, b))
+	}
+
+	genesis := block(getBlockHash(0), 0, getBlockHash(0))
+	genesis.ParentBlockIdentifier.Index = 0
+	add(genesis)
+	for j := int64(1); j <= 5; j++ {
+		add(block(getBlockHash(j), j, getBlockHash(j-1)))
+	}
+
+	// Blocks added without a reorg are not reported.
+	reorgs := 0
+	i.OnReorg(func(commonAncestor int64, orphaned []string) {
+		reorgs++
+		assert.Equal(t, int64(2), commonAncestor)
+		assert.Equal(t, []string{getBlockHash(5), getBlockHash(4), getBlockHash(3)}, orphaned)
+
+		// Coins created by the orphaned blocks are
+		// rolled back before the callback is called.
+		for _, hash := range orphaned {
+			coins, _, err := i.GetCoins(ctx, account(hash))
+			assert.NoError(t, err)
+			assert.Len(t, coins, 0)
+		}
+
+		coins, _, err := i.GetCoins(ctx, account(getBlockHash(2)))
+		assert.NoError(t, err)
+		assert.Len(t, coins, 1)
+
+		head, err := i.GetBlockLazy(ctx, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, getBlockHash(2), head.Block.BlockIdentifier.Hash)
+	})
+
+	// Replace blocks 3 to 5 with a longer fork.
+	for j := int64(5); j >= 3; j-- {
+		assert.NoError(t, i.BlockRemoved(ctx, &types.BlockIdentifier{
+			Hash:  getBlockHash(j),
+			Index: j,
+		}))
+	}
+	assert.Equal(t, 0, reorgs)
+
+	add(block("fork 3", 3, getBlockHash(2)))
+	assert.Equal(t, 1, reorgs)
+
+	add(block("fork 4", 4, "fork 3"))
+	add(block("fork 5", 5, "fork 4"))
+	add(block("fork 6", 6, "fork 5"))
+	assert.Equal(t, 1, reorgs)
+
+	coins, _, err := i.GetCoins(ctx, account("fork 6"))
+	assert.NoError(t, err)
+	assert.Len(t, coins, 1)
+}
+
+func TestIndexer_PruneDepth(t *testing.T) {
+	tests := map[string]struct {
+		pruneDepth        int64
+		confirmationDepth int64
+
+		prunedHeight int64
+	}{
+		"prune depth": {
+			pruneDepth:   5,
+			prunedHeight: 5,
+		},
+		"confirmation depth": {
+			pruneDepth:        5,
+			confirmationDepth: 8,
+			prunedHeight:      2,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Create Indexer
+			ctx := context.Background()
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			newDir, err := utils.CreateTempDir()
+			assert.NoError(t, err)
+			defer utils.RemoveTempDir(newDir)
+
+			mockClient := &mocks.Client{}
+			cfg := &configuration.Configuration{
+				Network: &types.NetworkIdentifier{
+					Network:    ravencoin.MainnetNetwork,
+					Blockchain: ravencoin.Blockchain,
+				},
+				GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+				IndexerPath:            newDir,
+				PruneDepth:             test.pruneDepth,
+				ConfirmationDepth:      test.confirmationDepth,
+			}
+
+			i, err := Initialize(ctx, cancel, cfg, mockClient)
+			assert.NoError(t, err)
+
+			// Blocks are added directly instead of by Sync,
+			// so the workers must be registered here.
+			i.blockStorage.Initialize(i.workers)
+
+			spent := &types.AccountIdentifier{Address: "spent"}
+			unspent := &types.AccountIdentifier{Address: "unspent"}
+			coin := func(
+				index int64,
+				opType string,
+				account *types.AccountIdentifier,
+				value string,
+				coinIdentifier string,
+				action types.CoinAction,
+			) *types.Operation {
+				return &types.Operation{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index:        index,
+						NetworkIndex: &index,
+					},
+					Status:  types.String(ravencoin.SuccessStatus),
+					Type:    opType,
+					Account: account,
+					Amount: &types.Amount{
+						Value:    value,
+						Currency: ravencoin.MainnetCurrency,
+					},
+					CoinChange: &types.CoinChange{
+						CoinAction: action,
+						CoinIdentifier: &types.CoinIdentifier{
+							Identifier: coinIdentifier,
+						},
+					},
+				}
+			}
+
+			unspentScript := &ravencoin.ScriptPubKey{
+				Hex:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+				Type: "pubkeyhash",
+			}
+			unspentScriptMap, err := types.MarshalMap(unspentScript)
+			assert.NoError(t, err)
+
+			unspentOutput := coin(1, ravencoin.OutputOpType, unspent, "1000", "tx 1:1", types.CoinCreated)
+			unspentOutput.Metadata = map[string]interface{}{
+				"scriptPubKey": unspentScriptMap,
+			}
+
+			// Block 1 creates a coin that is spent in block 2
+			// and a coin that is never spent.
+			transactions := map[int64][]*types.Transaction{
+				1: {
+					{
+						TransactionIdentifier: &types.TransactionIdentifier{
+							Hash: "tx 1",
+						},
+						Operations: []*types.Operation{
+							coin(0, ravencoin.OutputOpType, spent, "1000", "tx 1:0", types.CoinCreated),
+							unspentOutput,
+						},
+					},
+				},
+				2: {
+					{
+						TransactionIdentifier: &types.TransactionIdentifier{
+							Hash: "tx 2",
+						},
+						Operations: []*types.Operation{
+							coin(0, ravencoin.InputOpType, spent, "-1000", "tx 1:0", types.CoinSpent),
+						},
+					},
+				},
+			}
+
+			for j := int64(0); j <= 10; j++ {
+				parent := &types.BlockIdentifier{
+					Hash:  getBlockHash(j - 1),
+					Index: j - 1,
+				}
+				if j == 0 {
+					parent = &types.BlockIdentifier{
+						Hash:  getBlockHash(0),
+						Index: 0,
+					}
+				}
+
+				block := &types.Block{
+					BlockIdentifier: &types.BlockIdentifier{
+						Hash:  getBlockHash(j),
+						Index: j,
+					},
+					ParentBlockIdentifier: parent,
+					Timestamp:             1599002115110,
+					Transactions:          transactions[j],
+				}
+				assert.NoError(t, i.BlockSeen(ctx, block))
+				assert.NoError(t, i.BlockAdded(ctx, block))
+			}
+
+			// Blocks more than the depth below the tip are pruned.
+			for j := int64(1); j <= 10; j++ {
+				index := j
+				_, err := i.GetBlockLazy(ctx, &types.PartialBlockIdentifier{Index: &index})
+				if j <= test.prunedHeight {
+					assert.Error(t, err)
+				} else {
+					assert.NoError(t, err)
+				}
+			}
+
+			// The spent coin is no longer recorded anywhere...
+			_, err = i.GetBlockTransaction(
+				ctx,
+				&types.BlockIdentifier{Hash: getBlockHash(2), Index: 2},
+				&types.TransactionIdentifier{Hash: "tx 2"},
+			)
+			assert.Error(t, err)
+
+			coins, _, err := i.GetCoins(ctx, spent)
+			assert.NoError(t, err)
+			assert.Len(t, coins, 0)
+
+			_, err = i.GetCoinAmounts(ctx, []*types.Coin{
+				{
+					CoinIdentifier: &types.CoinIdentifier{Identifier: "tx 1:0"},
+					Amount: &types.Amount{
+						Value:    "1000",
+						Currency: ravencoin.MainnetCurrency,
+					},
+				},
+			})
+			assert.Error(t, err)
+
+			// ...but the unspent coin is kept, and it can
+			// still be spent.
+			coins, _, err = i.GetCoins(ctx, unspent)
+			assert.NoError(t, err)
+			assert.Equal(t, []*types.Coin{
+				{
+					CoinIdentifier: &types.CoinIdentifier{Identifier: "tx 1:1"},
+					Amount: &types.Amount{
+						Value:    "1000",
+						Currency: ravencoin.MainnetCurrency,
+					},
+				},
+			}, coins)
+
+			scripts, err := i.GetScriptPubKeys(ctx, coins)
+			assert.NoError(t, err)
+			assert.Equal(t, []*ravencoin.ScriptPubKey{unspentScript}, scripts)
+
+			amounts, err := i.GetCoinAmounts(ctx, coins)
+			assert.NoError(t, err)
+			assert.Equal(t, []*types.Amount{coins[0].Amount}, amounts)
+
+			spendableCoins, _, err := i.GetSpendableCoins(ctx, unspent)
+			assert.NoError(t, err)
+			assert.Len(t, spendableCoins, 1)
+			assert.False(t, spendableCoins[0].Immature)
+
+			// The history of an address ends at the
+			// oldest block that has not been pruned.
+			txs, err := i.GetTransactionsByAddress(ctx, spent.Address, 10, 0)
+			assert.NoError(t, err)
+			assert.Len(t, txs, 0)
+
+			// Balances are not pruned.
+			var export bytes.Buffer
+			assert.NoError(t, i.ExportBalances(ctx, &export, 1, BalanceExportCSV))
+			assert.Contains(t, export.String(), "unspent")
+		})
+	}
+}
+
+func TestIndexer_GetTransactionsByAddress(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Blocks are added directly instead of by Sync,
+	// so the workers must be registered here.
+	i.blockStorage.Initialize(i.workers)
+
+	addressA := &types.AccountIdentifier{Address: "a"}
+	addressB := &types.AccountIdentifier{Address: "b"}
+	coin := func(
+		index int64,
+		opType string,
+		account *types.AccountIdentifier,
+		amount *types.Amount,
+		coinIdentifier string,
+		action types.CoinAction,
+	) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index:        index,
+				NetworkIndex: &index,
+			},
+			Status:  types.String(ravencoin.SuccessStatus),
+			Type:    opType,
+			Account: account,
+			Amount:  amount,
+			CoinChange: &types.CoinChange{
+				CoinAction: action,
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: coinIdentifier,
+				},
+			},
+		}
+	}
+	rvn := func(value string) *types.Amount {
+		return &types.Amount{
+			Value:    value,
+			Currency: ravencoin.MainnetCurrency,
+		}
+	}
+
+	// a receives in block 1, sends to b (with change)
+	// in block 2 and receives an asset from b in block 3.
+	transactions := map[int64][]*types.Transaction{
+		1: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				Operations: []*types.Operation{
+					coin(0, ravencoin.OutputOpType, addressA, rvn("1000"), "tx 1:0", types.CoinCreated),
+				},
+			},
+		},
+		2: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Operations: []*types.Operation{
+					coin(0, ravencoin.InputOpType, addressA, rvn("-1000"), "tx 1:0", types.CoinSpent),
+					coin(1, ravencoin.OutputOpType, addressB, rvn("500"), "tx 2:0", types.CoinCreated),
+					coin(2, ravencoin.OutputOpType, addressA, rvn("400"), "tx 2:1", types.CoinCreated),
+				},
+			},
+		},
+		3: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 3"},
+				Operations: []*types.Operation{
+					coin(0, ravencoin.InputOpType, addressB, rvn("-500"), "tx 2:0", types.CoinSpent),
+					coin(1, ravencoin.OutputOpType, addressA, &types.Amount{
+						Value:    "100000000",
+						Currency: ravencoin.AssetCurrency("RAVEN", 8),
+					}, "tx 3:0", types.CoinCreated),
+				},
+			},
+		},
+	}
+
+	blocks := []*types.Block{}
+	for j := int64(0); j <= 3; j++ {
+		parent := &types.BlockIdentifier{
+			Hash:  getBlockHash(j - 1),
+			Index: j - 1,
+		}
+		if j == 0 {
+			parent = &types.BlockIdentifier{
+				Hash:  getBlockHash(0),
+				Index: 0,
+			}
+		}
+
+		block := &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  getBlockHash(j),
+				Index: j,
+			},
+			ParentBlockIdentifier: parent,
+			Timestamp:             1599002115110,
+			Transactions:          transactions[j],
+		}
+		assert.NoError(t, i.BlockSeen(ctx, block))
+		assert.NoError(t, i.BlockAdded(ctx, block))
+		blocks = append(blocks, block)
+	}
+
+	history := func(addr string, limit int, offset int) []string {
+		txs, err := i.GetTransactionsByAddress(ctx, addr, limit, offset)
+		assert.NoError(t, err)
+
+		hashes := []string{}
+		for _, tx := range txs {
+			hashes = append(hashes, tx.TransactionIdentifier.Hash)
+		}
+
+		return hashes
+	}
+
+	assert.Equal(t, []string{"tx 3", "tx 2"}, history("a", 2, 0))
+	assert.Equal(t, []string{"tx 1"}, history("a", 2, 2))
+	assert.Equal(t, []string{}, history("a", 2, 4))
+	assert.Equal(t, []string{"tx 3", "tx 2"}, history("b", 10, 0))
+	assert.Equal(t, []string{}, history("c", 10, 0))
+
+	txs, err := i.GetTransactionsByAddress(ctx, "a", 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, transactions[1], txs)
+
+	// Transactions in removed blocks are no longer returned.
+	assert.NoError(t, i.BlockRemoved(ctx, blocks[3].BlockIdentifier))
+	assert.Equal(t, []string{"tx 2", "tx 1"}, history("a", 10, 0))
+	assert.Equal(t, []string{"tx 2"}, history("b", 10, 0))
+
+	txs, err = i.GetTransactionsByAddress(ctx, "a", 0, 0)
+	assert.Nil(t, txs)
+	assert.EqualError(t, err, "invalid limit 0 or offset 0")
+}
+
+func TestIndexer_ScanAddresses(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		Currency:               ravencoin.MainnetCurrency,
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Blocks are added directly instead of by Sync,
+	// so the workers must be registered here.
+	i.blockStorage.Initialize(i.workers)
+
+	addrs := make([]string, 10)
+	for j := range addrs {
+		addrs[j] = fmt.Sprintf("addr %d", j)
+	}
+	coin := func(
+		index int64,
+		opType string,
+		addr string,
+		value string,
+		coinIdentifier string,
+		action types.CoinAction,
+	) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index:        index,
+				NetworkIndex: &index,
+			},
+			Status:  types.String(ravencoin.SuccessStatus),
+			Type:    opType,
+			Account: &types.AccountIdentifier{Address: addr},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: ravencoin.MainnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinAction: action,
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: coinIdentifier,
+				},
+			},
+		}
+	}
+
+	// The first four addresses and the tenth receive in
+	// block 1, and the fourth sends all of its coins to
+	// the first in block 2. The fifth to ninth addresses
+	// are never used.
+	transactions := map[int64][]*types.Transaction{
+		1: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 1"},
+				Operations: []*types.Operation{
+					coin(0, ravencoin.OutputOpType, addrs[0], "1000", "tx 1:0", types.CoinCreated),
+					coin(1, ravencoin.OutputOpType, addrs[1], "1000", "tx 1:1", types.CoinCreated),
+					coin(2, ravencoin.OutputOpType, addrs[2], "1000", "tx 1:2", types.CoinCreated),
+					coin(3, ravencoin.OutputOpType, addrs[3], "1000", "tx 1:3", types.CoinCreated),
+					coin(4, ravencoin.OutputOpType, addrs[9], "1000", "tx 1:4", types.CoinCreated),
+				},
+			},
+		},
+		2: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx 2"},
+				Operations: []*types.Operation{
+					coin(0, ravencoin.InputOpType, addrs[3], "-1000", "tx 1:3", types.CoinSpent),
+					coin(1, ravencoin.OutputOpType, addrs[0], "1000", "tx 2:0", types.CoinCreated),
+				},
+			},
+		},
+	}
+
+	for j := int64(0); j <= 2; j++ {
+		parent := &types.BlockIdentifier{
+			Hash:  getBlockHash(j - 1),
+			Index: j - 1,
+		}
+		if j == 0 {
+			parent = &types.BlockIdentifier{
+				Hash:  getBlockHash(0),
+				Index: 0,
+			}
+		}
+
+		block := &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Hash:  getBlockHash(j),
+				Index: j,
+			},
+			ParentBlockIdentifier: parent,
+			Timestamp:             1599002115110,
+			Transactions:          transactions[j],
+		}
+		assert.NoError(t, i.BlockSeen(ctx, block))
+		assert.NoError(t, i.BlockAdded(ctx, block))
+	}
+
+	rvn := func(value string) *types.Amount {
+		return &types.Amount{
+			Value:    value,
+			Currency: ravencoin.MainnetCurrency,
+		}
+	}
+	scanned := map[string]*types.Amount{
+		addrs[0]: rvn("2000"),
+		addrs[1]: rvn("1000"),
+		addrs[2]: rvn("1000"),
+		addrs[3]: rvn("0"),
+		addrs[4]: rvn("0"),
+		addrs[5]: rvn("0"),
+		addrs[6]: rvn("0"),
+		addrs[7]: rvn("0"),
+		addrs[8]: rvn("0"),
+	}
+
+	// The scan stops after the five unused addresses,
+	// so the tenth is not found.
+	balances, err := i.ScanAddresses(ctx, addrs, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, scanned, balances)
+
+	// A larger gap limit reaches the tenth address.
+	scanned[addrs[9]] = rvn("1000")
+	balances, err = i.ScanAddresses(ctx, addrs, 6)
+	assert.NoError(t, err)
+	assert.Equal(t, scanned, balances)
+
+	balances, err = i.ScanAddresses(ctx, addrs, 0)
+	assert.Nil(t, balances)
+	assert.EqualError(t, err, "invalid gap limit 0")
+}
+
+func TestIndexer_OwnerToken(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Sync to 1
+	mockClient.On("NetworkStatus", ctx).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 1,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+	}, nil)
+
+	issuer := &types.AccountIdentifier{
+		Address: "issuer",
+	}
+	assetCurrency := ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)
+	ownerCurrency := ravencoin.AssetCurrency(ravencoin.OwnerTokenName("ROSETTA"), ravencoin.MaxAssetUnits)
+	assert.True(t, ravencoin.IsOwnerToken(ownerCurrency.Symbol))
+	assert.False(t, ravencoin.IsOwnerToken(assetCurrency.Symbol))
+
+	issuance := &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: "issuance",
+		},
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index:        0,
+					NetworkIndex: types.Int64(0),
+				},
+				Status:  types.String(ravencoin.SuccessStatus),
+				Type:    ravencoin.OutputOpType,
+				Account: issuer,
+				Amount: &types.Amount{
+					Value:    "100000000",
+					Currency: ownerCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinAction: types.CoinCreated,
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "issuance:0",
+					},
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index:        1,
+					NetworkIndex: types.Int64(1),
+				},
+				Status:  types.String(ravencoin.SuccessStatus),
+				Type:    ravencoin.OutputOpType,
+				Account: issuer,
+				Amount: &types.Amount{
+					Value:    "100000000000",
+					Currency: assetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinAction: types.CoinCreated,
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "issuance:1",
+					},
+				},
+			},
+		},
+	}
+
+	for i := int64(0); i <= 1; i++ {
+		identifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(i),
+			Index: i,
+		}
+		parentIdentifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(0),
+			Index: 0,
+		}
+
+		transactions := []*types.Transaction{}
+		if i == 1 {
+			transactions = append(transactions, issuance)
+		}
+
+		block := &ravencoin.Block{
+			Hash:              identifier.Hash,
+			Height:            identifier.Index,
+			PreviousBlockHash: parentIdentifier.Hash,
+		}
+		mockClient.On(
+			"GetRawBlock",
+			mock.Anything,
+			&types.PartialBlockIdentifier{Index: &identifier.Index},
+		).Return(
+			block,
+			[]string{},
+			nil,
+		).Once()
+		mockClient.On(
+			"ParseBlock",
+			mock.Anything,
+			block,
+			map[string]*types.AccountCoin{},
+		).Return(
+			&types.Block{
+				BlockIdentifier:       identifier,
+				ParentBlockIdentifier: parentIdentifier,
+				Timestamp:             1599002115110,
+				Transactions:          transactions,
+			},
+			nil,
+		).Once()
+	}
+
+	go func() {
+		err := i.Sync(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	for {
+		currBlockResponse, err := i.GetBlockLazy(ctx, nil)
+		if currBlockResponse == nil || currBlockResponse.Block.BlockIdentifier.Index < 1 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		assert.NoError(t, err)
+		break
+	}
+
+	// The asset and its owner token are reported as
+	// separate holdings of the issuer.
+	assetBalance, _, err := i.GetBalance(ctx, issuer, assetCurrency, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Amount{
+		Value:    "100000000000",
+		Currency: assetCurrency,
+	}, assetBalance)
+
+	ownerBalance, _, err := i.GetBalance(ctx, issuer, ownerCurrency, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.Amount{
+		Value:    "100000000",
+		Currency: ownerCurrency,
+	}, ownerBalance)
+
+	coins, _, err := i.GetCoins(ctx, issuer)
+	assert.NoError(t, err)
+	assert.Len(t, coins, 2)
+	currencies := map[string]string{}
+	for _, coin := range coins {
+		currencies[coin.Amount.Currency.Symbol] = coin.Amount.Value
+	}
+	assert.Equal(t, map[string]string{
+		"ROSETTA":  "100000000000",
+		"ROSETTA!": "100000000",
+	}, currencies)
+
+	ownerCoins, err := i.GetAssetCoins(ctx, issuer, ownerCurrency.Symbol)
+	assert.NoError(t, err)
+	assert.Len(t, ownerCoins, 1)
+	assert.Equal(t, "issuance:0", ownerCoins[0].CoinIdentifier.Identifier)
+
+	cancel()
+	mockClient.AssertExpectations(t)
+}
+
+func TestIndexer_SyncStartHeight(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+		SyncStartHeight:        5,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Sync to 6
+	mockClient.On("NetworkStatus", ctx).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 6,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+	}, nil)
+
+	preStartOwner := &types.AccountIdentifier{Address: "pre-start owner"}
+	owner := &types.AccountIdentifier{Address: "owner"}
+	recipient := &types.AccountIdentifier{Address: "recipient"}
+	preStartCoin := &types.AccountCoin{
+		Account: preStartOwner,
+		Coin: &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{Identifier: "pre-start:0"},
+			Amount: &types.Amount{
+				Value:    "50",
+				Currency: ravencoin.MainnetCurrency,
+			},
+		},
+	}
+	coin := &types.AccountCoin{
+		Account: owner,
+		Coin: &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{Identifier: "block 5:0"},
+			Amount: &types.Amount{
+				Value:    "100",
+				Currency: ravencoin.MainnetCurrency,
+			},
+		},
+	}
+
+	// The pre-start coin is fetched from ravend because
+	// it was created before we started syncing. Coins
+	// created after that are never fetched from ravend,
+	// even if they are spent before they are seen.
+	mockClient.On("GetCoin", mock.Anything, "pre-start:0").Return(preStartCoin, int64(2), nil).Once()
+
+	transactions := map[int64][]*types.Transaction{
+		5: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "block 5"},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index:        0,
+							NetworkIndex: &index0,
+						},
+						Status:  types.String(ravencoin.SuccessStatus),
+						Type:    ravencoin.OutputOpType,
+						Account: owner,
+						Amount:  coin.Coin.Amount,
+						CoinChange: &types.CoinChange{
+							CoinAction:     types.CoinCreated,
+							CoinIdentifier: coin.Coin.CoinIdentifier,
+						},
+					},
+				},
+			},
+		},
+		6: {
+			{
+				TransactionIdentifier: &types.TransactionIdentifier{Hash: "block 6"},
+				Operations: []*types.Operation{
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index:        0,
+							NetworkIndex: &index0,
+						},
+						Status:  types.String(ravencoin.SuccessStatus),
+						Type:    ravencoin.InputOpType,
+						Account: preStartOwner,
+						Amount: &types.Amount{
+							Value:    "-50",
+							Currency: ravencoin.MainnetCurrency,
+						},
+						CoinChange: &types.CoinChange{
+							CoinAction:     types.CoinSpent,
+							CoinIdentifier: preStartCoin.Coin.CoinIdentifier,
+						},
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index:        1,
+							NetworkIndex: types.Int64(1),
+						},
+						Status:  types.String(ravencoin.SuccessStatus),
+						Type:    ravencoin.InputOpType,
+						Account: owner,
+						Amount: &types.Amount{
+							Value:    "-100",
+							Currency: ravencoin.MainnetCurrency,
+						},
+						CoinChange: &types.CoinChange{
+							CoinAction:     types.CoinSpent,
+							CoinIdentifier: coin.Coin.CoinIdentifier,
+						},
+					},
+					{
+						OperationIdentifier: &types.OperationIdentifier{
+							Index:        2,
+							NetworkIndex: &index0,
+						},
+						Status:  types.String(ravencoin.SuccessStatus),
+						Type:    ravencoin.OutputOpType,
+						Account: recipient,
+						Amount: &types.Amount{
+							Value:    "140",
+							Currency: ravencoin.MainnetCurrency,
+						},
+						CoinChange: &types.CoinChange{
+							CoinAction: types.CoinCreated,
+							CoinIdentifier: &types.CoinIdentifier{
+								Identifier: "block 6:0",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	requiredCoins := map[int64][]string{
+		5: {},
+		6: {"pre-start:0", "block 5:0"},
+	}
+	coinMaps := map[int64]map[string]*types.AccountCoin{
+		5: {},
+		6: {
+			"pre-start:0": preStartCoin,
+			"block 5:0":   coin,
+		},
+	}
+
+	// Blocks below the start height are never requested.
+	for i := int64(5); i <= 6; i++ {
+		identifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(i),
+			Index: i,
+		}
+		parentIdentifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(i - 1),
+			Index: i - 1,
+		}
+
+		block := &ravencoin.Block{
+			Hash:              identifier.Hash,
+			Height:            identifier.Index,
+			PreviousBlockHash: parentIdentifier.Hash,
+		}
+		mockClient.On(
+			"GetRawBlock",
+			mock.Anything,
+			&types.PartialBlockIdentifier{Index: &identifier.Index},
+		).Return(
+			block,
+			requiredCoins[i],
+			nil,
+		).Once()
+		mockClient.On(
+			"ParseBlock",
+			mock.Anything,
+			block,
+			coinMaps[i],
+		).Return(
+			&types.Block{
+				BlockIdentifier:       identifier,
+				ParentBlockIdentifier: parentIdentifier,
+				Timestamp:             1599002115110,
+				Transactions:          transactions[i],
+			},
+			nil,
+		).Once()
+	}
+
+	go func() {
+		err := i.Sync(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	for {
+		currBlockResponse, err := i.GetBlockLazy(ctx, nil)
+		if currBlockResponse == nil || currBlockResponse.Block.BlockIdentifier.Index < 6 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		assert.NoError(t, err)
+		break
+	}
+
+	_, err = i.GetBlockLazy(ctx, &types.PartialBlockIdentifier{Index: types.Int64(4)})
+	assert.Error(t, err)
+
+	// Spending the pre-start coin is skipped
+	// instead of making the balance negative.
+	tx, err := i.GetBlockTransaction(
+		ctx,
+		&types.BlockIdentifier{Hash: getBlockHash(6), Index: 6},
+		&types.TransactionIdentifier{Hash: "block 6"},
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, ravencoin.SkippedStatus, *tx.Operations[0].Status)
+	assert.Equal(t, ravencoin.SuccessStatus, *tx.Operations[1].Status)
+
+	balances := map[*types.AccountIdentifier]string{
+		preStartOwner: "0",
+		owner:         "0",
+		recipient:     "140",
+	}
+	for account, value := range balances {
+		amount, _, err := i.GetBalance(ctx, account, ravencoin.MainnetCurrency, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, value, amount.Value)
+	}
+
+	cancel()
+	mockClient.AssertExpectations(t)
+}
+
+func TestIndexer_MaxServableHeight(t *testing.T) {
+	mockClient := &mocks.Client{}
+	i := &Indexer{
+		client:            mockClient,
+		maxServableHeight: 500,
+	}
+	ctx := context.Background()
+
+	// The node is past the max servable height
+	mockClient.On("NetworkStatus", ctx).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 1000,
+			Hash:  getBlockHash(1000),
+		},
+	}, nil).Once()
+	mockClient.On("GetHashFromIndex", ctx, int64(500)).Return(getBlockHash(500), nil).Once()
+	status, err := i.NetworkStatus(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.BlockIdentifier{
+		Index: 500,
+		Hash:  getBlockHash(500),
+	}, status.CurrentBlockIdentifier)
+
+	// The node has not reached the max servable height
+	mockClient.On("NetworkStatus", ctx).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 400,
+			Hash:  getBlockHash(400),
+		},
+	}, nil).Once()
+	status, err = i.NetworkStatus(ctx, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, &types.BlockIdentifier{
+		Index: 400,
+		Hash:  getBlockHash(400),
+	}, status.CurrentBlockIdentifier)
+
+	mockClient.AssertExpectations(t)
+}
+
+func TestIndexer_ExportBalances(t *testing.T) {
+	// Create Indexer
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	newDir, err := utils.CreateTempDir()
+	assert.NoError(t, err)
+	defer utils.RemoveTempDir(newDir)
+
+	mockClient := &mocks.Client{}
+	cfg := &configuration.Configuration{
+		Network: &types.NetworkIdentifier{
+			Network:    ravencoin.MainnetNetwork,
+			Blockchain: ravencoin.Blockchain,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		IndexerPath:            newDir,
+	}
+
+	i, err := Initialize(ctx, cancel, cfg, mockClient)
+	assert.NoError(t, err)
+
+	// Sync to 2
+	mockClient.On("NetworkStatus", ctx).Return(&types.NetworkStatusResponse{
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 2,
+		},
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+	}, nil)
+
+	output := func(hash string, index int64, address string, value string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index:        index,
+				NetworkIndex: types.Int64(index),
+			},
+			Status: types.String(ravencoin.SuccessStatus),
+			Type:   ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: ravencoin.MainnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinAction: types.CoinCreated,
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: fmt.Sprintf("%s:%d", hash, index),
+				},
+			},
+		}
+	}
+
+	transactions := map[int64]*types.Transaction{
+		1: {
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: "tx1",
+			},
+			Operations: []*types.Operation{
+				output("tx1", 0, "bob", "50000000"),
+				output("tx1", 1, "alice", "100000000"),
+			},
+		},
+		2: {
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: "tx2",
+			},
+			Operations: []*types.Operation{
+				output("tx2", 0, "alice", "25000000"),
+				output("tx2", 1, "carol", "10000000"),
+			},
+		},
+	}
+
+	for i := int64(0); i <= 2; i++ {
+		identifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(i),
+			Index: i,
+		}
+		parentIdentifier := &types.BlockIdentifier{
+			Hash:  getBlockHash(i - 1),
+			Index: i - 1,
+		}
+		if i == 0 {
+			parentIdentifier = identifier
+		}
+
+		blockTransactions := []*types.Transaction{}
+		if tx, ok := transactions[i]; ok {
+			blockTransactions = append(blockTransactions, tx)
+		}
+
+		block := &ravencoin.Block{
+			Hash:              identifier.Hash,
+			Height:            identifier.Index,
+			PreviousBlockHash: parentIdentifier.Hash,
+		}
+		mockClient.On(
+			"GetRawBlock",
+			mock.Anything,
+			&types.PartialBlockIdentifier{Index: &identifier.Index},
+		).Return(
+			block,
+			[]string{},
+			nil,
+		).Once()
+		mockClient.On(
+			"ParseBlock",
+			mock.Anything,
+			block,
+			map[string]*types.AccountCoin{},
+		).Return(
+			&types.Block{
+				BlockIdentifier:       identifier,
+				ParentBlockIdentifier: parentIdentifier,
+				Timestamp:             1599002115110,
+				Transactions:          blockTransactions,
+			},
+			nil,
+		).Once()
+	}
+
+	go func() {
+		err := i.Sync(ctx)
+		assert.True(t, errors.Is(err, context.Canceled))
+	}()
+
+	for {
+		currBlockResponse, err := i.GetBlockLazy(ctx, nil)
+		if currBlockResponse == nil || currBlockResponse.Block.BlockIdentifier.Index < 2 {
+			time.Sleep(1 * time.Second)
+			continue
+		}
+
+		assert.NoError(t, err)
+		break
+	}
+
+	// carol is first funded at height 2, so she is
+	// not included in an export at height 1. Rows are
+	// in storage order, so only the header is fixed.
+	var csvExport bytes.Buffer
+	assert.NoError(t, i.ExportBalances(ctx, &csvExport, 1, BalanceExportCSV))
+	rows := strings.Split(strings.TrimSuffix(csvExport.String(), "\n"), "\n")
+	assert.Equal(t, "address,currency,balance", rows[0])
+	assert.ElementsMatch(t, []string{
+		"alice,RVN,100000000",
+		"bob,RVN,50000000",
+	}, rows[1:])
+
+	var jsonExport bytes.Buffer
+	assert.NoError(t, i.ExportBalances(ctx, &jsonExport, 2, BalanceExportJSON))
+	var jsonRows []*balanceExportRow
+	assert.NoError(t, json.Unmarshal(jsonExport.Bytes(), &jsonRows))
+	assert.ElementsMatch(t, []*balanceExportRow{
+		{Address: "alice", Currency: "RVN", Balance: "125000000"},
+		{Address: "bob", Currency: "RVN", Balance: "50000000"},
+		{Address: "carol", Currency: "RVN", Balance: "10000000"},
+	}, jsonRows)
+
+	// Heights that have not been indexed and unknown
+	// formats are rejected.
+	var discard bytes.Buffer
+	assert.Error(t, i.ExportBalances(ctx, &discard, 3, BalanceExportCSV))
+	assert.Error(t, i.ExportBalances(ctx, &discard, -1, BalanceExportCSV))
+	assert.Error(t, i.ExportBalances(ctx, &discard, 1, "xml"))
+
+	cancel()
+	mockClient.AssertExpectations(t)
+}
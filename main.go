@@ -86,8 +86,11 @@ func startOnlineDependencies(
 		cfg.Currency,
 	)
 
+	// Coins created before SyncStartHeight are fetched
+	// by transaction, which requires ravend's txindex.
+	txIndex := cfg.SyncStartHeight > 0
 	g.Go(func() error {
-		return ravencoin.StartRavend(ctx, cfg.ConfigPath, g)
+		return ravencoin.StartRavend(ctx, cfg.ConfigPath, txIndex, g)
 	})
 
 	i, err := indexer.Initialize(
@@ -104,9 +107,13 @@ func startOnlineDependencies(
 		return i.Sync(ctx)
 	})
 
-	g.Go(func() error {
-		return i.Prune(ctx)
-	})
+	// ravend cannot be pruned while it
+	// maintains a transaction index.
+	if !txIndex {
+		g.Go(func() error {
+			return i.Prune(ctx)
+		})
+	}
 
 	return client, i, nil
 }
@@ -136,6 +143,14 @@ func main() {
 	logger.Infow("loaded configuration", "configuration", types.PrintStruct(cfg))
 	logger.Infow("Test Log!")
 
+	if err := configuration.RegisterCustomNetwork(cfg); err != nil {
+		logger.Fatalw("unable to register custom network", "error", err)
+	}
+
+	if err := services.RegisterHDVersionBytes(cfg); err != nil {
+		logger.Fatalw("unable to register hd version bytes", "error", err)
+	}
+
 	g, ctx := errgroup.WithContext(ctx)
 
 	g.Go(func() error {
@@ -154,7 +169,7 @@ func main() {
 	// The asserter automatically rejects incorrectly formatted
 	// requests.
 	asserter, err := asserter.NewServer(
-		ravencoin.OperationTypes,
+		cfg.OperationTypes.Supported(),
 		services.HistoricalBalanceLookup,
 		[]*types.NetworkIdentifier{cfg.Network},
 		nil,
@@ -164,7 +179,27 @@ func main() {
 		logger.Fatalw("unable to create new server asserter", "error", err)
 	}
 
-	router := services.NewBlockchainRouter(cfg, client, i, asserter)
+	var router http.Handler = services.NewBlockchainRouter(
+		cfg,
+		client,
+		i,
+		asserter,
+		loggerRaw.Sugar().Named("construction"),
+	)
+	if cfg.RateLimit != nil {
+		limiter, err := services.NewRateLimiter(cfg.RateLimit)
+		if err != nil {
+			logger.Fatalw("unable to create rate limiter", "error", err)
+		}
+
+		router = services.RateLimiterMiddleware(limiter, router)
+	}
+
+	// The indexer only runs in online mode.
+	if i != nil {
+		router = services.AdminMiddleware(cfg, i, router)
+	}
+
 	loggedRouter := services.LoggerMiddleware(loggerRaw, router)
 	corsRouter := server.CorsMiddleware(loggedRouter)
 	server := &http.Server{
@@ -17,6 +17,80 @@ type Client struct {
 	mock.Mock
 }
 
+// GetAssetData provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetAssetData(_a0 context.Context, _a1 string) (*ravencoin.AssetInfo, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.AssetInfo
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.AssetInfo); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.AssetInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetCoin provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetCoin(_a0 context.Context, _a1 string) (*types.AccountCoin, int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *types.AccountCoin
+	if rf, ok := ret.Get(0).(func(context.Context, string) *types.AccountCoin); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.AccountCoin)
+		}
+	}
+
+	var r1 int64
+	if rf, ok := ret.Get(1).(func(context.Context, string) int64); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context, string) error); ok {
+		r2 = rf(_a0, _a1)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetHashFromIndex provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetHashFromIndex(_a0 context.Context, _a1 int64) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, int64) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetRawBlock provides a mock function with given fields: _a0, _a1
 func (_m *Client) GetRawBlock(_a0 context.Context, _a1 *types.PartialBlockIdentifier) (*ravencoin.Block, []string, error) {
 	ret := _m.Called(_a0, _a1)
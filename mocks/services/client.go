@@ -5,6 +5,8 @@ package services
 import (
 	context "context"
 
+	ravencoin "github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
 	mock "github.com/stretchr/testify/mock"
 
 	types "github.com/coinbase/rosetta-sdk-go/types"
@@ -15,6 +17,184 @@ type Client struct {
 	mock.Mock
 }
 
+// GetBestBlockHash provides a mock function with given fields: _a0
+func (_m *Client) GetBestBlockHash(_a0 context.Context) (string, error) {
+	ret := _m.Called(_a0)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context) string); ok {
+		r0 = rf(_a0)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlock provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetBlock(_a0 context.Context, _a1 string) (*ravencoin.Block, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.Block
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.Block); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.Block)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockHeader provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetBlockHeader(_a0 context.Context, _a1 string) (*ravencoin.BlockHeader, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.BlockHeader
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.BlockHeader); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.BlockHeader)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockHeight provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetBlockHeight(_a0 context.Context, _a1 string) (int64, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 int64
+	if rf, ok := ret.Get(0).(func(context.Context, string) int64); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetHashFromIndex provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetHashFromIndex(_a0 context.Context, _a1 int64) (string, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 string
+	if rf, ok := ret.Get(0).(func(context.Context, int64) string); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMempoolEntry provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetMempoolEntry(_a0 context.Context, _a1 string) (*ravencoin.MempoolEntry, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.MempoolEntry
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.MempoolEntry); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.MempoolEntry)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetMempoolTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetMempoolTransaction(_a0 context.Context, _a1 string) (*types.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *types.Transaction
+	if rf, ok := ret.Get(0).(func(context.Context, string) *types.Transaction); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetNodeInfo provides a mock function with given fields: _a0
+func (_m *Client) GetNodeInfo(_a0 context.Context) (*ravencoin.NodeInfo, error) {
+	ret := _m.Called(_a0)
+
+	var r0 *ravencoin.NodeInfo
+	if rf, ok := ret.Get(0).(func(context.Context) *ravencoin.NodeInfo); ok {
+		r0 = rf(_a0)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.NodeInfo)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = rf(_a0)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetPeers provides a mock function with given fields: _a0
 func (_m *Client) GetPeers(_a0 context.Context) ([]*types.Peer, error) {
 	ret := _m.Called(_a0)
@@ -38,8 +218,8 @@ func (_m *Client) GetPeers(_a0 context.Context) ([]*types.Peer, error) {
 	return r0, r1
 }
 
-// RawMempool provides a mock function with given fields: _a0
-func (_m *Client) RawMempool(_a0 context.Context) ([]string, error) {
+// GetRawMempool provides a mock function with given fields: _a0
+func (_m *Client) GetRawMempool(_a0 context.Context) ([]string, error) {
 	ret := _m.Called(_a0)
 
 	var r0 []string
@@ -61,6 +241,29 @@ func (_m *Client) RawMempool(_a0 context.Context) ([]string, error) {
 	return r0, r1
 }
 
+// GetRawTransaction provides a mock function with given fields: _a0, _a1
+func (_m *Client) GetRawTransaction(_a0 context.Context, _a1 string) (*ravencoin.Transaction, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *ravencoin.Transaction
+	if rf, ok := ret.Get(0).(func(context.Context, string) *ravencoin.Transaction); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*ravencoin.Transaction)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // SendRawTransaction provides a mock function with given fields: _a0, _a1
 func (_m *Client) SendRawTransaction(_a0 context.Context, _a1 string) (string, error) {
 	ret := _m.Called(_a0, _a1)
@@ -82,8 +285,8 @@ func (_m *Client) SendRawTransaction(_a0 context.Context, _a1 string) (string, e
 	return r0, r1
 }
 
-// SuggestedFeeRate provides a mock function with given fields: _a0, _a1
-func (_m *Client) SuggestedFeeRate(_a0 context.Context, _a1 int64) (float64, error) {
+// SuggestedFeeRatePerKB provides a mock function with given fields: _a0, _a1
+func (_m *Client) SuggestedFeeRatePerKB(_a0 context.Context, _a1 int64) (float64, error) {
 	ret := _m.Called(_a0, _a1)
 
 	var r0 float64
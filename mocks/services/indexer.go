@@ -17,6 +17,73 @@ type Indexer struct {
 	mock.Mock
 }
 
+// AddressUsed provides a mock function with given fields: _a0, _a1
+func (_m *Indexer) AddressUsed(_a0 context.Context, _a1 string) (bool, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, string) bool); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Get(0).(bool)
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssetBalance provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Indexer) GetAssetBalance(_a0 context.Context, _a1 *types.AccountIdentifier, _a2 string) (*types.Amount, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 *types.Amount
+	if rf, ok := ret.Get(0).(func(context.Context, *types.AccountIdentifier, string) *types.Amount); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*types.Amount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.AccountIdentifier, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetAssetCoins provides a mock function with given fields: _a0, _a1, _a2
+func (_m *Indexer) GetAssetCoins(_a0 context.Context, _a1 *types.AccountIdentifier, _a2 string) ([]*types.Coin, error) {
+	ret := _m.Called(_a0, _a1, _a2)
+
+	var r0 []*types.Coin
+	if rf, ok := ret.Get(0).(func(context.Context, *types.AccountIdentifier, string) []*types.Coin); ok {
+		r0 = rf(_a0, _a1, _a2)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Coin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.AccountIdentifier, string) error); ok {
+		r1 = rf(_a0, _a1, _a2)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: _a0, _a1, _a2, _a3
 func (_m *Indexer) GetBalance(_a0 context.Context, _a1 *types.AccountIdentifier, _a2 *types.Currency, _a3 *types.PartialBlockIdentifier) (*types.Amount, *types.BlockIdentifier, error) {
 	ret := _m.Called(_a0, _a1, _a2, _a3)
@@ -95,6 +162,29 @@ func (_m *Indexer) GetBlockTransaction(_a0 context.Context, _a1 *types.BlockIden
 	return r0, r1
 }
 
+// GetCoinAmounts provides a mock function with given fields: _a0, _a1
+func (_m *Indexer) GetCoinAmounts(_a0 context.Context, _a1 []*types.Coin) ([]*types.Amount, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*types.Amount
+	if rf, ok := ret.Get(0).(func(context.Context, []*types.Coin) []*types.Amount); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Amount)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, []*types.Coin) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCoins provides a mock function with given fields: _a0, _a1
 func (_m *Indexer) GetCoins(_a0 context.Context, _a1 *types.AccountIdentifier) ([]*types.Coin, *types.BlockIdentifier, error) {
 	ret := _m.Called(_a0, _a1)
@@ -127,6 +217,29 @@ func (_m *Indexer) GetCoins(_a0 context.Context, _a1 *types.AccountIdentifier) (
 	return r0, r1, r2
 }
 
+// GetImmatureCoinbaseCoins provides a mock function with given fields: _a0, _a1
+func (_m *Indexer) GetImmatureCoinbaseCoins(_a0 context.Context, _a1 *types.AccountIdentifier) ([]*types.Coin, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 []*types.Coin
+	if rf, ok := ret.Get(0).(func(context.Context, *types.AccountIdentifier) []*types.Coin); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*types.Coin)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *types.AccountIdentifier) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetScriptPubKeys provides a mock function with given fields: _a0, _a1
 func (_m *Indexer) GetScriptPubKeys(_a0 context.Context, _a1 []*types.Coin) ([]*ravencoin.ScriptPubKey, error) {
 	ret := _m.Called(_a0, _a1)
@@ -0,0 +1,104 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+// AddressType is the type of script an address pays to.
+type AddressType string
+
+const (
+	// P2PKH is a pay-to-pubkey-hash address.
+	P2PKH AddressType = "p2pkh"
+
+	// P2SH is a pay-to-script-hash address.
+	P2SH AddressType = "p2sh"
+
+	// P2WPKH is a pay-to-witness-pubkey-hash (bech32) address.
+	P2WPKH AddressType = "p2wpkh"
+
+	// P2WSH is a pay-to-witness-script-hash (bech32) address.
+	P2WSH AddressType = "p2wsh"
+
+	// base58HashLength is the length of the hash
+	// encoded in a P2PKH or P2SH address.
+	base58HashLength = 20
+)
+
+var (
+	// ErrInvalidAddress is returned when an address
+	// cannot be decoded.
+	ErrInvalidAddress = errors.New("invalid address")
+
+	// ErrWrongNetworkAddress is returned when an address is
+	// valid for a known network but not the one provided.
+	ErrWrongNetworkAddress = errors.New("address is for a different network")
+)
+
+// ValidateAddress returns the AddressType of addr if it is a
+// valid address for params.
+func ValidateAddress(addr string, params *chaincfg.Params) (AddressType, error) {
+	// Segwit addresses are prefixed by the human-readable
+	// part of their network followed by '1'.
+	if separator := strings.LastIndex(addr, "1"); separator > 0 &&
+		chaincfg.IsBech32SegwitPrefix(addr[:separator+1]) {
+		if !strings.EqualFold(addr[:separator], params.Bech32HRPSegwit) {
+			return "", fmt.Errorf("%w: %s is not a %s address", ErrWrongNetworkAddress, addr, params.Name)
+		}
+
+		decoded, err := btcutil.DecodeAddress(addr, params.BtcdParams())
+		if err != nil {
+			return "", fmt.Errorf("%w: %s: %s", ErrInvalidAddress, addr, err.Error())
+		}
+
+		switch decoded.(type) {
+		case *btcutil.AddressWitnessPubKeyHash:
+			return P2WPKH, nil
+		case *btcutil.AddressWitnessScriptHash:
+			return P2WSH, nil
+		default:
+			return "", fmt.Errorf("%w: %s has an unsupported witness version", ErrInvalidAddress, addr)
+		}
+	}
+
+	decoded, version, err := base58.CheckDecode(addr)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %s", ErrInvalidAddress, addr, err.Error())
+	}
+
+	if len(decoded) != base58HashLength {
+		return "", fmt.Errorf("%w: %s has an invalid length", ErrInvalidAddress, addr)
+	}
+
+	switch {
+	case version == params.PubKeyHashAddrID:
+		return P2PKH, nil
+	case version == params.ScriptHashAddrID:
+		return P2SH, nil
+	case chaincfg.IsPubKeyHashAddrID(version) || chaincfg.IsScriptHashAddrID(version):
+		return "", fmt.Errorf("%w: %s is not a %s address", ErrWrongNetworkAddress, addr, params.Name)
+	default:
+		return "", fmt.Errorf("%w: %s has unknown version %d", ErrInvalidAddress, addr, version)
+	}
+}
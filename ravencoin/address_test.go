@@ -0,0 +1,103 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateAddress(t *testing.T) {
+	tests := map[string]struct {
+		address string
+		params  *chaincfg.Params
+
+		addressType AddressType
+		err         error
+	}{
+		"testnet p2pkh": {
+			address:     "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfn",
+			params:      TestnetParams,
+			addressType: P2PKH,
+		},
+		"testnet p2sh": {
+			address:     "2MzQwSSnBHWHqSAqtTVQ6v47XtaisrJa1Vc",
+			params:      TestnetParams,
+			addressType: P2SH,
+		},
+		"testnet p2wpkh": {
+			address:     "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			params:      TestnetParams,
+			addressType: P2WPKH,
+		},
+		"testnet p2wsh": {
+			address:     "tb1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3q0sl5k7",
+			params:      TestnetParams,
+			addressType: P2WSH,
+		},
+		"mainnet p2pkh": {
+			address:     "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			params:      MainnetParams,
+			addressType: P2PKH,
+		},
+		"mainnet p2pkh on testnet": {
+			address: "1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2",
+			params:  TestnetParams,
+			err:     ErrWrongNetworkAddress,
+		},
+		"mainnet p2sh on testnet": {
+			address: "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy",
+			params:  TestnetParams,
+			err:     ErrWrongNetworkAddress,
+		},
+		"testnet p2wpkh on mainnet": {
+			address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			params:  MainnetParams,
+			err:     ErrWrongNetworkAddress,
+		},
+		"bad checksum": {
+			address: "mipcBbFg9gMiCh81Kj8tqqdgoZub1ZJRfm",
+			params:  TestnetParams,
+			err:     ErrInvalidAddress,
+		},
+		"bad bech32 checksum": {
+			address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvn",
+			params:  TestnetParams,
+			err:     ErrInvalidAddress,
+		},
+		"empty": {
+			params: TestnetParams,
+			err:    ErrInvalidAddress,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			addressType, err := ValidateAddress(test.address, test.params)
+			if test.err != nil {
+				assert.Equal(t, AddressType(""), addressType)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.addressType, addressType)
+		})
+	}
+}
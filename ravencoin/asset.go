@@ -0,0 +1,901 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	// OpRvnAsset is the opcode that separates a standard
+	// locking script from the asset it carries.
+	OpRvnAsset = 0xc0
+
+	// OwnerAssetAmount is the quantity (in Satoshis) of every
+	// owner token.
+	OwnerAssetAmount = SatoshisInRavencoin
+
+	// p2pkhScriptLength and p2shScriptLength are the lengths
+	// of the standard locking scripts preceding OpRvnAsset.
+	p2pkhScriptLength = 25
+	p2shScriptLength  = 23
+
+	// assetAmountLength is the length of the little-endian
+	// asset amount in an asset script.
+	assetAmountLength = 8
+
+	// compactSizeMax1Byte is the largest length encoded
+	// in a single CompactSize byte.
+	compactSizeMax1Byte = 0xfc
+
+	// MaxAssetAmount is the largest quantity (in Satoshis)
+	// of an asset that can be issued.
+	MaxAssetAmount = 21000000000 * SatoshisInRavencoin
+
+	// MaxAssetUnits is the largest number of decimal
+	// places an asset can be divided into.
+	MaxAssetUnits = 8
+
+	// ipfsHashLength is the length of a decoded IPFS
+	// (CIDv0) hash attached to an asset.
+	ipfsHashLength = 34
+
+	// expireTimeLength is the length of the little-endian
+	// expiry of a message attached to an asset transfer.
+	expireTimeLength = 8
+
+	// minRootAssetNameLength and maxRootAssetNameLength
+	// bound the length of root asset names.
+	minRootAssetNameLength = 3
+	maxRootAssetNameLength = 30
+
+	// maxAssetNameLength bounds the length of
+	// all asset names (including the name of
+	// any parent asset).
+	maxAssetNameLength = 32
+
+	// subAssetSeparator separates the name of a
+	// sub-asset from its parent asset name.
+	subAssetSeparator = "/"
+
+	// RestrictedAssetPrefix prefixes the name of
+	// a restricted asset.
+	RestrictedAssetPrefix = "$"
+
+	// KeepAssetUnits is the units of a reissuance
+	// that does not change the units of the asset.
+	KeepAssetUnits = -1
+)
+
+// AssetNameType is the kind of asset an asset name refers to.
+type AssetNameType int
+
+// Asset name types.
+const (
+	// AssetNameTypeRoot is the type of root
+	// asset names (i.e. ROSETTA).
+	AssetNameTypeRoot AssetNameType = iota
+
+	// AssetNameTypeSub is the type of sub-asset
+	// names (i.e. ROSETTA/SUB).
+	AssetNameTypeSub
+
+	// AssetNameTypeUnique is the type of unique
+	// asset names (i.e. ROSETTA#Tag).
+	AssetNameTypeUnique
+
+	// AssetNameTypeRestricted is the type of
+	// restricted asset names (i.e. $ROSETTA).
+	AssetNameTypeRestricted
+
+	// AssetNameTypeOwner is the type of owner
+	// token names (i.e. ROSETTA!).
+	AssetNameTypeOwner
+)
+
+// String returns the name of the asset name type.
+func (t AssetNameType) String() string {
+	switch t {
+	case AssetNameTypeRoot:
+		return "root"
+	case AssetNameTypeSub:
+		return "sub"
+	case AssetNameTypeUnique:
+		return "unique"
+	case AssetNameTypeRestricted:
+		return "restricted"
+	case AssetNameTypeOwner:
+		return "owner"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(t))
+	}
+}
+
+// Asset script types (the byte following the "rvn" prefix).
+const (
+	assetScriptNew      = 'q'
+	assetScriptTransfer = 't'
+	assetScriptReissue  = 'r'
+	assetScriptOwner    = 'o'
+)
+
+var (
+	// assetScriptPrefix prefixes all asset data pushed
+	// after OpRvnAsset.
+	assetScriptPrefix = []byte("rvn")
+
+	// ErrInvalidAssetScript is returned when a script
+	// contains OpRvnAsset but its asset data is malformed.
+	ErrInvalidAssetScript = errors.New("invalid asset script")
+
+	// ErrNotAssetScript is returned by ExtractAsset when
+	// a script does not carry an asset.
+	ErrNotAssetScript = errors.New("script does not carry an asset")
+
+	// ErrInvalidAssetName is returned when an asset
+	// name does not follow the naming rules.
+	ErrInvalidAssetName = errors.New("invalid asset name")
+
+	// ErrInvalidAssetIssuance is returned when the
+	// parameters of an asset issuance are invalid.
+	ErrInvalidAssetIssuance = errors.New("invalid asset issuance")
+
+	// rootAssetNameCharacters are the characters
+	// allowed in root asset names.
+	rootAssetNameCharacters = regexp.MustCompile(`^[A-Z0-9._]+$`)
+
+	// subAssetNameCharacters are the characters
+	// allowed in each part of a sub-asset name.
+	subAssetNameCharacters = regexp.MustCompile(`^[A-Z0-9._]+$`)
+
+	// uniqueAssetTagCharacters are the characters
+	// allowed in the tag of a unique asset.
+	uniqueAssetTagCharacters = regexp.MustCompile(`^[-A-Za-z0-9@$%&*()[\]{}_.?:]+$`)
+
+	// assetNamePunctuation matches punctuation that
+	// is leading, trailing or repeated.
+	assetNamePunctuation = regexp.MustCompile(`^[._]|[._]$|[._]{2}`)
+
+	// reservedAssetNames cannot be issued.
+	reservedAssetNames = map[string]struct{}{
+		"RVN":       {},
+		"RAVEN":     {},
+		"RAVENCOIN": {},
+	}
+)
+
+// AssetScript is the asset carried by a locking script,
+// parsed from the data following OpRvnAsset.
+type AssetScript struct {
+	// Type is the ScriptPubKey.Type ravend reports
+	// for the script (i.e. NewAssetType).
+	Type string
+
+	Name string
+
+	// Amount is the quantity of the asset in
+	// Satoshis (10^-8 units).
+	Amount int64
+
+	// Units, Reissuable and IPFSHash are only
+	// populated when an asset is issued or
+	// reissued. Reissuances that keep the units
+	// of the asset have Units KeepAssetUnits.
+	Units      int
+	Reissuable bool
+	IPFSHash   string
+
+	// Message and ExpireTime are only populated
+	// when a transfer carries a RIP5 message (the
+	// IPFS hash of the message and the unix time
+	// after which it expires, if any).
+	Message    string
+	ExpireTime int64
+}
+
+// assetScriptStart returns the index of OpRvnAsset in a
+// P2PKH or P2SH script that carries an asset. If the script
+// does not carry an asset, -1 is returned.
+func assetScriptStart(script []byte) int {
+	switch {
+	case len(script) > p2pkhScriptLength &&
+		script[0] == txscript.OP_DUP &&
+		script[p2pkhScriptLength] == OpRvnAsset:
+		return p2pkhScriptLength
+	case len(script) > p2shScriptLength &&
+		script[0] == txscript.OP_HASH160 &&
+		script[p2shScriptLength] == OpRvnAsset:
+		return p2shScriptLength
+	default:
+		return -1
+	}
+}
+
+// ParseAssetScript returns the *AssetScript carried by
+// script. If script does not carry an asset, nil is returned.
+func ParseAssetScript(script []byte) (*AssetScript, error) {
+	start := assetScriptStart(script)
+	if start == -1 {
+		return nil, nil
+	}
+
+	// The asset data is a single push following OpRvnAsset.
+	data := script[start+1:]
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: missing asset data", ErrInvalidAssetScript)
+	}
+
+	length := int(data[0])
+	data = data[1:]
+	if length == txscript.OP_PUSHDATA1 {
+		if len(data) == 0 {
+			return nil, fmt.Errorf("%w: missing asset data length", ErrInvalidAssetScript)
+		}
+
+		length = int(data[0])
+		data = data[1:]
+	} else if length > txscript.OP_DATA_75 {
+		return nil, fmt.Errorf("%w: unexpected opcode %d", ErrInvalidAssetScript, length)
+	}
+
+	if len(data) < length {
+		return nil, fmt.Errorf("%w: asset data is truncated", ErrInvalidAssetScript)
+	}
+	data = data[:length]
+
+	if len(data) < len(assetScriptPrefix)+2 || string(data[:len(assetScriptPrefix)]) != string(assetScriptPrefix) {
+		return nil, fmt.Errorf("%w: missing rvn prefix", ErrInvalidAssetScript)
+	}
+
+	assetType := data[len(assetScriptPrefix)]
+	data = data[len(assetScriptPrefix)+1:]
+
+	nameLength := int(data[0])
+	if nameLength > compactSizeMax1Byte || len(data) < nameLength+1 {
+		return nil, fmt.Errorf("%w: invalid asset name length", ErrInvalidAssetScript)
+	}
+
+	asset := &AssetScript{
+		Name: string(data[1 : nameLength+1]),
+	}
+	data = data[nameLength+1:]
+
+	switch assetType {
+	case assetScriptOwner:
+		asset.Type = NewAssetType
+		asset.Amount = OwnerAssetAmount
+		return asset, nil
+	case assetScriptNew:
+		asset.Type = NewAssetType
+	case assetScriptTransfer:
+		asset.Type = TransferAssetType
+	case assetScriptReissue:
+		asset.Type = ReissueAssetType
+	default:
+		return nil, fmt.Errorf("%w: unknown asset type %q", ErrInvalidAssetScript, assetType)
+	}
+
+	if len(data) < assetAmountLength {
+		return nil, fmt.Errorf("%w: missing asset amount", ErrInvalidAssetScript)
+	}
+
+	asset.Amount = int64(binary.LittleEndian.Uint64(data[:assetAmountLength]))
+	if asset.Amount < 0 {
+		return nil, fmt.Errorf("%w: negative asset amount", ErrInvalidAssetScript)
+	}
+	data = data[assetAmountLength:]
+
+	switch assetType {
+	case assetScriptNew:
+		if err := parseIssuance(asset, data); err != nil {
+			return nil, err
+		}
+	case assetScriptReissue:
+		if err := parseReissuance(asset, data); err != nil {
+			return nil, err
+		}
+	case assetScriptTransfer:
+		if err := parseTransferMessage(asset, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return asset, nil
+}
+
+// parseTransferMessage populates the RIP5 message and
+// expiry of a transferred asset from the data following
+// its amount. Both are optional, but an expiry is only
+// present after a message.
+func parseTransferMessage(asset *AssetScript, data []byte) error {
+	if len(data) == 0 {
+		return nil
+	}
+
+	if len(data) < ipfsHashLength {
+		return fmt.Errorf("%w: message is truncated", ErrInvalidAssetScript)
+	}
+
+	asset.Message = base58.Encode(data[:ipfsHashLength])
+	data = data[ipfsHashLength:]
+	if len(data) == 0 {
+		return nil
+	}
+
+	if len(data) < expireTimeLength {
+		return fmt.Errorf("%w: message expiry is truncated", ErrInvalidAssetScript)
+	}
+
+	asset.ExpireTime = int64(binary.LittleEndian.Uint64(data[:expireTimeLength]))
+	if asset.ExpireTime < 0 {
+		return fmt.Errorf("%w: negative message expiry", ErrInvalidAssetScript)
+	}
+
+	return nil
+}
+
+// parseReissuance populates the units, reissuable flag and
+// IPFS hash of a reissued asset from the data following its
+// amount. Unlike issuances, the IPFS hash is not preceded
+// by a flag and is present if any data remains.
+func parseReissuance(asset *AssetScript, data []byte) error {
+	// units and reissuable
+	if len(data) < 2 { // nolint:gomnd
+		return fmt.Errorf("%w: missing reissuance parameters", ErrInvalidAssetScript)
+	}
+
+	asset.Units = int(int8(data[0]))
+	asset.Reissuable = data[1] == 1
+
+	data = data[2:]
+	if len(data) == 0 {
+		return nil
+	}
+
+	if len(data) < ipfsHashLength {
+		return fmt.Errorf("%w: IPFS hash is truncated", ErrInvalidAssetScript)
+	}
+
+	asset.IPFSHash = base58.Encode(data[:ipfsHashLength])
+	return nil
+}
+
+// parseIssuance populates the units, reissuable flag and
+// IPFS hash of a new asset from the data following its amount.
+func parseIssuance(asset *AssetScript, data []byte) error {
+	// units, reissuable and has IPFS
+	if len(data) < 3 { // nolint:gomnd
+		return fmt.Errorf("%w: missing asset issuance", ErrInvalidAssetScript)
+	}
+
+	asset.Units = int(data[0])
+	asset.Reissuable = data[1] == 1
+	if data[2] == 0 {
+		return nil
+	}
+
+	data = data[3:]
+	if len(data) < ipfsHashLength {
+		return fmt.Errorf("%w: IPFS hash is truncated", ErrInvalidAssetScript)
+	}
+
+	asset.IPFSHash = base58.Encode(data[:ipfsHashLength])
+	return nil
+}
+
+// IsAssetScript returns true if pkScript is a P2PKH or P2SH
+// locking script followed by OpRvnAsset. The asset data is
+// not validated (see ExtractAsset).
+func IsAssetScript(pkScript []byte) bool {
+	return assetScriptStart(pkScript) != -1
+}
+
+// ExtractAsset returns the *AssetScript carried by pkScript.
+// Unlike ParseAssetScript, ErrNotAssetScript is returned if
+// pkScript does not carry an asset. Owner tokens are
+// returned with Type NewAssetType and a name ending
+// in OwnerTokenSuffix.
+func ExtractAsset(pkScript []byte) (*AssetScript, error) {
+	if !IsAssetScript(pkScript) {
+		return nil, ErrNotAssetScript
+	}
+
+	return ParseAssetScript(pkScript)
+}
+
+// AssetScriptBase returns the standard locking script
+// preceding OpRvnAsset. If script does not carry an asset,
+// it is returned unchanged.
+func AssetScriptBase(script []byte) []byte {
+	start := assetScriptStart(script)
+	if start == -1 {
+		return script
+	}
+
+	return script[:start]
+}
+
+// ParseAssetScriptHex is a convenience wrapper around
+// ParseAssetScript for hex-encoded scripts.
+func ParseAssetScriptHex(scriptHex string) (*AssetScript, error) {
+	script, err := hex.DecodeString(scriptHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode script %s", err, scriptHex)
+	}
+
+	return ParseAssetScript(script)
+}
+
+// ValidateRootAssetName returns an error if name is
+// not a valid root asset name.
+func ValidateRootAssetName(name string) error {
+	if len(name) < minRootAssetNameLength || len(name) > maxRootAssetNameLength {
+		return fmt.Errorf(
+			"%w: %s must be between %d and %d characters",
+			ErrInvalidAssetName,
+			name,
+			minRootAssetNameLength,
+			maxRootAssetNameLength,
+		)
+	}
+
+	if !rootAssetNameCharacters.MatchString(name) {
+		return fmt.Errorf("%w: %s may only contain A-Z, 0-9, . and _", ErrInvalidAssetName, name)
+	}
+
+	if assetNamePunctuation.MatchString(name) {
+		return fmt.Errorf(
+			"%w: %s has leading, trailing or consecutive punctuation",
+			ErrInvalidAssetName,
+			name,
+		)
+	}
+
+	if _, ok := reservedAssetNames[name]; ok {
+		return fmt.Errorf("%w: %s is reserved", ErrInvalidAssetName, name)
+	}
+
+	return nil
+}
+
+// ValidateAssetName returns the AssetNameType of name or
+// an error if name is not a valid root, sub, unique,
+// restricted or owner asset name.
+func ValidateAssetName(name string) (AssetNameType, error) {
+	if len(name) > maxAssetNameLength {
+		return 0, fmt.Errorf(
+			"%w: %s is longer than %d characters",
+			ErrInvalidAssetName,
+			name,
+			maxAssetNameLength,
+		)
+	}
+
+	switch {
+	case strings.HasPrefix(name, RestrictedAssetPrefix):
+		if err := ValidateRootAssetName(name[len(RestrictedAssetPrefix):]); err != nil {
+			return 0, err
+		}
+
+		return AssetNameTypeRestricted, nil
+	case IsOwnerToken(name):
+		if err := validateParentAssetName(name[:len(name)-len(OwnerTokenSuffix)]); err != nil {
+			return 0, err
+		}
+
+		return AssetNameTypeOwner, nil
+	case strings.Contains(name, UniqueAssetTag):
+		tag := strings.Index(name, UniqueAssetTag)
+		if err := validateParentAssetName(name[:tag]); err != nil {
+			return 0, err
+		}
+
+		if !uniqueAssetTagCharacters.MatchString(name[tag+len(UniqueAssetTag):]) {
+			return 0, fmt.Errorf("%w: %s has an invalid unique tag", ErrInvalidAssetName, name)
+		}
+
+		return AssetNameTypeUnique, nil
+	case strings.Contains(name, subAssetSeparator):
+		if err := validateParentAssetName(name); err != nil {
+			return 0, err
+		}
+
+		return AssetNameTypeSub, nil
+	default:
+		if err := ValidateRootAssetName(name); err != nil {
+			return 0, err
+		}
+
+		return AssetNameTypeRoot, nil
+	}
+}
+
+// validateParentAssetName returns an error if name is
+// not a valid root or sub-asset name.
+func validateParentAssetName(name string) error {
+	parts := strings.Split(name, subAssetSeparator)
+	if err := ValidateRootAssetName(parts[0]); err != nil {
+		return err
+	}
+
+	for _, part := range parts[1:] {
+		if len(part) == 0 {
+			return fmt.Errorf("%w: %s has an empty sub-asset name", ErrInvalidAssetName, name)
+		}
+
+		if !subAssetNameCharacters.MatchString(part) {
+			return fmt.Errorf(
+				"%w: sub-asset name %s may only contain A-Z, 0-9, . and _",
+				ErrInvalidAssetName,
+				part,
+			)
+		}
+
+		if assetNamePunctuation.MatchString(part) {
+			return fmt.Errorf(
+				"%w: sub-asset name %s has leading, trailing or consecutive punctuation",
+				ErrInvalidAssetName,
+				part,
+			)
+		}
+	}
+
+	return nil
+}
+
+// assetScript returns script followed by OpRvnAsset
+// and the asset data pushed by payload.
+func assetScript(script []byte, payload []byte) ([]byte, error) {
+	isP2PKH := len(script) == p2pkhScriptLength && script[0] == txscript.OP_DUP
+	isP2SH := len(script) == p2shScriptLength && script[0] == txscript.OP_HASH160
+	if !isP2PKH && !isP2SH {
+		return nil, fmt.Errorf(
+			"%w: assets can only be sent to P2PKH or P2SH scripts",
+			ErrInvalidAssetIssuance,
+		)
+	}
+
+	return txscript.NewScriptBuilder().
+		AddOps(script).
+		AddOp(OpRvnAsset).
+		AddData(payload).
+		AddOp(txscript.OP_DROP).
+		Script()
+}
+
+// assetPayload returns the asset data of the provided
+// type for name, starting with the "rvn" prefix.
+func assetPayload(assetType byte, name string) []byte {
+	payload := append([]byte{}, assetScriptPrefix...)
+	payload = append(payload, assetType, byte(len(name)))
+	return append(payload, name...)
+}
+
+// assetPrecision returns the number of Satoshis in
+// the smallest fraction of an asset with units.
+func assetPrecision(units int) int64 {
+	precision := int64(1)
+	for i := units; i < MaxAssetUnits; i++ {
+		precision *= 10 // nolint:gomnd
+	}
+
+	return precision
+}
+
+// AssetValue returns amount (in Satoshis) of an asset
+// with units as a whole number of its smallest unit. Any
+// precision beyond units is truncated, although valid
+// amounts never have any. Currencies are always in
+// Satoshis (see AssetCurrency).
+func AssetValue(amount int64, units int) int64 {
+	return amount / assetPrecision(units)
+}
+
+// AssetAmount returns the amount (in Satoshis) of value,
+// a whole number of the smallest unit of an asset with
+// units. It is the inverse of AssetValue.
+func AssetAmount(value int64, units int) (int64, error) {
+	if units < 0 || units > MaxAssetUnits {
+		return 0, fmt.Errorf("units %d must be between 0 and %d", units, MaxAssetUnits)
+	}
+
+	precision := assetPrecision(units)
+	if value > math.MaxInt64/precision || value < math.MinInt64/precision {
+		return 0, fmt.Errorf("asset value %d with %d units overflows", value, units)
+	}
+
+	return value * precision, nil
+}
+
+// FormatAssetAmount returns amount (in Satoshis) of an asset
+// with units as a decimal string with exactly units decimal
+// places (truncating any beyond them). An asset with 0 units
+// never shows a decimal point and an asset with MaxAssetUnits
+// is formatted like RVN. units outside of 0 and MaxAssetUnits
+// are clamped.
+func FormatAssetAmount(amount int64, units int) string {
+	if units < 0 {
+		units = 0
+	}
+	if units > MaxAssetUnits {
+		units = MaxAssetUnits
+	}
+
+	value := AssetValue(amount, units)
+	sign := ""
+	magnitude := uint64(value)
+	if value < 0 {
+		// -(value+1) cannot overflow (unlike -value).
+		sign = "-"
+		magnitude = uint64(-(value + 1)) + 1
+	}
+
+	if units == 0 {
+		return fmt.Sprintf("%s%d", sign, magnitude)
+	}
+
+	divisor := uint64(SatoshisInRavencoin / assetPrecision(units))
+	return fmt.Sprintf("%s%d.%0*d", sign, magnitude/divisor, units, magnitude%divisor)
+}
+
+// NewAssetScript returns script (a P2PKH or P2SH locking
+// script) extended to issue amount (in Satoshis) of the
+// root asset name, divisible into units decimal places.
+// If ipfsHash is not empty, it is attached to the asset.
+func NewAssetScript(
+	script []byte,
+	name string,
+	amount int64,
+	units int,
+	reissuable bool,
+	ipfsHash string,
+) ([]byte, error) {
+	if err := ValidateRootAssetName(name); err != nil {
+		return nil, err
+	}
+
+	if units < 0 || units > MaxAssetUnits {
+		return nil, fmt.Errorf(
+			"%w: units %d must be between 0 and %d",
+			ErrInvalidAssetIssuance,
+			units,
+			MaxAssetUnits,
+		)
+	}
+
+	if amount <= 0 || amount > MaxAssetAmount {
+		return nil, fmt.Errorf(
+			"%w: amount %d must be between 1 and %d",
+			ErrInvalidAssetIssuance,
+			amount,
+			int64(MaxAssetAmount),
+		)
+	}
+
+	// The amount cannot be more precise than units.
+	if amount%assetPrecision(units) != 0 {
+		return nil, fmt.Errorf(
+			"%w: amount %d has more than %d decimal places",
+			ErrInvalidAssetIssuance,
+			amount,
+			units,
+		)
+	}
+
+	payload := assetPayload(assetScriptNew, name)
+	payload = append(payload, make([]byte, assetAmountLength)...)
+	binary.LittleEndian.PutUint64(payload[len(payload)-assetAmountLength:], uint64(amount))
+
+	reissuableFlag := byte(0)
+	if reissuable {
+		reissuableFlag = 1
+	}
+
+	if len(ipfsHash) == 0 {
+		// units, reissuable and has IPFS
+		payload = append(payload, byte(units), reissuableFlag, 0)
+		return assetScript(script, payload)
+	}
+
+	hash, err := decodeIPFSHash(ipfsHash)
+	if err != nil {
+		return nil, err
+	}
+
+	payload = append(payload, byte(units), reissuableFlag, 1)
+	payload = append(payload, hash...)
+
+	return assetScript(script, payload)
+}
+
+// OwnerAssetScript returns script (a P2PKH or P2SH
+// locking script) extended to issue the owner token
+// of the root asset name.
+func OwnerAssetScript(script []byte, name string) ([]byte, error) {
+	if err := ValidateRootAssetName(name); err != nil {
+		return nil, err
+	}
+
+	return assetScript(script, assetPayload(assetScriptOwner, OwnerTokenName(name)))
+}
+
+// decodeIPFSHash returns the raw bytes of ipfsHash.
+func decodeIPFSHash(ipfsHash string) ([]byte, error) {
+	hash := base58.Decode(ipfsHash)
+	if len(hash) != ipfsHashLength {
+		return nil, fmt.Errorf(
+			"%w: IPFS hash %s must decode to %d bytes",
+			ErrInvalidAssetIssuance,
+			ipfsHash,
+			ipfsHashLength,
+		)
+	}
+
+	return hash, nil
+}
+
+// TransferAssetScript returns script (a P2PKH or P2SH
+// locking script) extended to transfer amount (in
+// Satoshis) of the asset name.
+func TransferAssetScript(script []byte, name string, amount int64) ([]byte, error) {
+	return TransferAssetMessageScript(script, name, amount, "", 0)
+}
+
+// TransferAssetMessageScript returns script (a P2PKH or
+// P2SH locking script) extended to transfer amount (in
+// Satoshis) of the asset name with the RIP5 message
+// (an IPFS hash) expiring at expireTime (a unix time).
+// If message is empty, no message is attached. If
+// expireTime is 0, the message does not expire.
+//
+// Messages are only valid once the msg_rest_assets
+// deployment is active.
+func TransferAssetMessageScript(
+	script []byte,
+	name string,
+	amount int64,
+	message string,
+	expireTime int64,
+) ([]byte, error) {
+	if _, err := ValidateAssetName(name); err != nil {
+		return nil, err
+	}
+
+	if amount <= 0 || amount > MaxAssetAmount {
+		return nil, fmt.Errorf(
+			"%w: amount %d must be between 1 and %d",
+			ErrInvalidAssetIssuance,
+			amount,
+			int64(MaxAssetAmount),
+		)
+	}
+
+	if expireTime < 0 || (expireTime > 0 && len(message) == 0) {
+		return nil, fmt.Errorf(
+			"%w: expire time %d requires a message and cannot be negative",
+			ErrInvalidAssetIssuance,
+			expireTime,
+		)
+	}
+
+	payload := assetPayload(assetScriptTransfer, name)
+	payload = append(payload, make([]byte, assetAmountLength)...)
+	binary.LittleEndian.PutUint64(payload[len(payload)-assetAmountLength:], uint64(amount))
+
+	if len(message) > 0 {
+		hash, err := decodeIPFSHash(message)
+		if err != nil {
+			return nil, err
+		}
+
+		payload = append(payload, hash...)
+	}
+
+	if expireTime > 0 {
+		payload = append(payload, make([]byte, expireTimeLength)...)
+		binary.LittleEndian.PutUint64(payload[len(payload)-expireTimeLength:], uint64(expireTime))
+	}
+
+	return assetScript(script, payload)
+}
+
+// NewReissueAssetScript returns script (a P2PKH or P2SH
+// locking script) extended to reissue amount (in Satoshis)
+// of the root or sub-asset name. If units is not
+// KeepAssetUnits, the asset becomes divisible into units
+// decimal places. If ipfsHash is not empty, it replaces
+// the IPFS hash attached to the asset.
+func NewReissueAssetScript(
+	script []byte,
+	name string,
+	amount int64,
+	units int,
+	reissuable bool,
+	ipfsHash string,
+) ([]byte, error) {
+	nameType, err := ValidateAssetName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if nameType != AssetNameTypeRoot && nameType != AssetNameTypeSub {
+		return nil, fmt.Errorf(
+			"%w: %s is a %s asset but only root and sub assets can be reissued",
+			ErrInvalidAssetIssuance,
+			name,
+			nameType,
+		)
+	}
+
+	if units < KeepAssetUnits || units > MaxAssetUnits {
+		return nil, fmt.Errorf(
+			"%w: units %d must be between %d and %d",
+			ErrInvalidAssetIssuance,
+			units,
+			KeepAssetUnits,
+			MaxAssetUnits,
+		)
+	}
+
+	if amount < 0 || amount > MaxAssetAmount {
+		return nil, fmt.Errorf(
+			"%w: amount %d must be between 0 and %d",
+			ErrInvalidAssetIssuance,
+			amount,
+			int64(MaxAssetAmount),
+		)
+	}
+
+	// If the units are changed, the amount cannot
+	// be more precise than the new units.
+	if units != KeepAssetUnits && amount%assetPrecision(units) != 0 {
+		return nil, fmt.Errorf(
+			"%w: amount %d has more than %d decimal places",
+			ErrInvalidAssetIssuance,
+			amount,
+			units,
+		)
+	}
+
+	payload := assetPayload(assetScriptReissue, name)
+	payload = append(payload, make([]byte, assetAmountLength)...)
+	binary.LittleEndian.PutUint64(payload[len(payload)-assetAmountLength:], uint64(amount))
+
+	reissuableFlag := byte(0)
+	if reissuable {
+		reissuableFlag = 1
+	}
+	payload = append(payload, byte(int8(units)), reissuableFlag)
+
+	if len(ipfsHash) > 0 {
+		hash, err := decodeIPFSHash(ipfsHash)
+		if err != nil {
+			return nil, err
+		}
+
+		payload = append(payload, hash...)
+	}
+
+	return assetScript(script, payload)
+}
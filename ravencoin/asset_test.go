@@ -0,0 +1,565 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAssetScriptHex(t *testing.T) {
+	tests := map[string]struct {
+		script string
+
+		asset *AssetScript
+		err   error
+	}{
+		"transfer": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01472766e7407524f53455454410065cd1d0000000075", // nolint
+			asset: &AssetScript{
+				Type:   TransferAssetType,
+				Name:   "ROSETTA",
+				Amount: 500000000,
+			},
+		},
+		"new asset": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01772766e7107524f534554544100e876481700000000010075", // nolint
+			asset: &AssetScript{
+				Type:       NewAssetType,
+				Name:       "ROSETTA",
+				Amount:     100000000000,
+				Reissuable: true,
+			},
+		},
+		"new asset with IPFS hash": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc03972766e7107524f534554544100e876481700000002010112209d6c2be50f706953479ab9df2ce3edca90b68053c00b3004b7f0accbe1e8eedf75", // nolint
+			asset: &AssetScript{
+				Type:       NewAssetType,
+				Name:       "ROSETTA",
+				Amount:     100000000000,
+				Units:      2,
+				Reissuable: true,
+				IPFSHash:   "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			},
+		},
+		"owner token": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc00d72766e6f08524f53455454412175",
+			asset: &AssetScript{
+				Type:   NewAssetType,
+				Name:   "ROSETTA!",
+				Amount: OwnerAssetAmount,
+			},
+		},
+		"no asset": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+		},
+		"segwit": {
+			script: "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+		},
+		"truncated issuance": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01572766e7107524f534554544100e87648170000000075",
+			err:    ErrInvalidAssetScript,
+		},
+		"truncated amount": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01072766e7407524f5345545441006575",
+			err:    ErrInvalidAssetScript,
+		},
+		"missing prefix": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01478796e7407524f53455454410065cd1d0000000075", // nolint
+			err:    ErrInvalidAssetScript,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			asset, err := ParseAssetScriptHex(test.script)
+			if test.err != nil {
+				assert.Nil(t, asset)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.asset, asset)
+		})
+	}
+}
+
+func TestExtractAsset(t *testing.T) {
+	tests := map[string]struct {
+		script string
+
+		isAsset bool
+		asset   *AssetScript
+		err     error
+	}{
+		"transfer": {
+			script:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01472766e7407524f53455454410065cd1d0000000075", // nolint
+			isAsset: true,
+			asset: &AssetScript{
+				Type:   TransferAssetType,
+				Name:   "ROSETTA",
+				Amount: 500000000,
+			},
+		},
+		"issuance": {
+			script:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01772766e7107524f534554544100e876481700000000010075", // nolint
+			isAsset: true,
+			asset: &AssetScript{
+				Type:       NewAssetType,
+				Name:       "ROSETTA",
+				Amount:     100000000000,
+				Reissuable: true,
+			},
+		},
+		"owner token": {
+			script:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc00d72766e6f08524f53455454412175",
+			isAsset: true,
+			asset: &AssetScript{
+				Type:   NewAssetType,
+				Name:   "ROSETTA!",
+				Amount: OwnerAssetAmount,
+			},
+		},
+		"p2pkh": {
+			script: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+			err:    ErrNotAssetScript,
+		},
+		"truncated issuance": {
+			script:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01572766e7107524f534554544100e87648170000000075",
+			isAsset: true,
+			err:     ErrInvalidAssetScript,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := hex.DecodeString(test.script)
+			assert.NoError(t, err)
+			assert.Equal(t, test.isAsset, IsAssetScript(script))
+
+			asset, err := ExtractAsset(script)
+			if test.err != nil {
+				assert.Nil(t, asset)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.asset, asset)
+		})
+	}
+}
+
+func TestCanonicalAssetName(t *testing.T) {
+	tests := map[string]struct {
+		name string
+
+		canonical string
+	}{
+		"canonical": {
+			name:      "ROSETTA",
+			canonical: "ROSETTA",
+		},
+		"lowercase": {
+			name:      "rosetta",
+			canonical: "ROSETTA",
+		},
+		"owner token": {
+			name:      "Rosetta!",
+			canonical: "ROSETTA!",
+		},
+		"sub asset": {
+			name:      "ROSETTA/sub",
+			canonical: "ROSETTA/SUB",
+		},
+		"unique asset": {
+			name:      "rosetta#Tag",
+			canonical: "ROSETTA#Tag",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.canonical, CanonicalAssetName(test.name))
+		})
+	}
+}
+
+func TestValidateRootAssetName(t *testing.T) {
+	tests := map[string]struct {
+		name string
+
+		valid bool
+	}{
+		"valid":                  {name: "ROSETTA", valid: true},
+		"valid with punctuation": {name: "ROSETTA_ASSET.1", valid: true},
+		"too short":              {name: "RS"},
+		"too long":               {name: "ROSETTAROSETTAROSETTAROSETTAROS"},
+		"lowercase":              {name: "Rosetta"},
+		"leading punctuation":    {name: "_ROSETTA"},
+		"trailing punctuation":   {name: "ROSETTA."},
+		"double punctuation":     {name: "ROSETTA._ASSET"},
+		"reserved":               {name: "RAVEN"},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := ValidateRootAssetName(test.name)
+			if test.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, errors.Is(err, ErrInvalidAssetName))
+			}
+		})
+	}
+}
+
+func TestValidateAssetName(t *testing.T) {
+	tests := map[string]struct {
+		name string
+
+		nameType AssetNameType
+		err      bool
+	}{
+		"root":                    {name: "ROSETTA", nameType: AssetNameTypeRoot},
+		"sub":                     {name: "ROSETTA/SUB", nameType: AssetNameTypeSub},
+		"nested sub":              {name: "ROSETTA/SUB/SUB_2.A", nameType: AssetNameTypeSub},
+		"unique":                  {name: "ROSETTA#Tag-1", nameType: AssetNameTypeUnique},
+		"unique of sub":           {name: "ROSETTA/SUB#tag@home", nameType: AssetNameTypeUnique},
+		"restricted":              {name: "$ROSETTA", nameType: AssetNameTypeRestricted},
+		"owner":                   {name: "ROSETTA!", nameType: AssetNameTypeOwner},
+		"sub owner":               {name: "ROSETTA/SUB!", nameType: AssetNameTypeOwner},
+		"bad root characters":     {name: "ROSETTA-1", err: true},
+		"bad sub characters":      {name: "ROSETTA/sub", err: true},
+		"bad unique tag":          {name: "ROSETTA#tag with spaces", err: true},
+		"empty unique tag":        {name: "ROSETTA#", err: true},
+		"double slash":            {name: "ROSETTA//SUB", err: true},
+		"trailing slash":          {name: "ROSETTA/", err: true},
+		"sub punctuation":         {name: "ROSETTA/_SUB", err: true},
+		"root too long":           {name: "ROSETTAROSETTAROSETTAROSETTAROS", err: true},
+		"too long":                {name: "ROSETTA/ROSETTAROSETTAROSETTAROSE", err: true},
+		"restricted sub":          {name: "$ROSETTA/SUB", err: true},
+		"restricted reserved":     {name: "$RVN", err: true},
+		"reserved":                {name: "RAVEN", err: true},
+		"unique with short root":  {name: "RS#tag", err: true},
+		"owner of unique":         {name: "ROSETTA#tag!", err: true},
+		"lowercase restricted":    {name: "$rosetta", err: true},
+		"unique with empty root":  {name: "#tag", err: true},
+		"sub with reserved root":  {name: "RVN/SUB", err: true},
+		"owner with invalid root": {name: "R!", err: true},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			nameType, err := ValidateAssetName(test.name)
+			if test.err {
+				assert.True(t, errors.Is(err, ErrInvalidAssetName))
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.nameType, nameType)
+			}
+		})
+	}
+}
+
+func TestNewAssetScript(t *testing.T) {
+	p2pkh, _ := hex.DecodeString("76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac")
+	p2wpkh, _ := hex.DecodeString("0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55")
+
+	tests := map[string]struct {
+		script []byte
+		name   string
+		amount int64
+		units  int
+		ipfs   string
+
+		expected string
+		err      error
+	}{
+		"issuance": {
+			script:   p2pkh,
+			name:     "ROSETTA",
+			amount:   100000000000,
+			expected: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01772766e7107524f534554544100e876481700000000010075", // nolint
+		},
+		"issuance with IPFS hash": {
+			script:   p2pkh,
+			name:     "ROSETTA",
+			amount:   100000000000,
+			units:    2,
+			ipfs:     "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			expected: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc03972766e7107524f534554544100e876481700000002010112209d6c2be50f706953479ab9df2ce3edca90b68053c00b3004b7f0accbe1e8eedf75", // nolint
+		},
+		"invalid IPFS hash": {
+			script: p2pkh,
+			name:   "ROSETTA",
+			amount: 100000000000,
+			ipfs:   "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79",
+			err:    ErrInvalidAssetIssuance,
+		},
+		"too precise": {
+			script: p2pkh,
+			name:   "ROSETTA",
+			amount: 150000000,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"invalid units": {
+			script: p2pkh,
+			name:   "ROSETTA",
+			amount: 100000000,
+			units:  9,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"invalid name": {
+			script: p2pkh,
+			name:   "rosetta",
+			amount: 100000000,
+			err:    ErrInvalidAssetName,
+		},
+		"segwit": {
+			script: p2wpkh,
+			name:   "ROSETTA",
+			amount: 100000000,
+			err:    ErrInvalidAssetIssuance,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := NewAssetScript(
+				test.script,
+				test.name,
+				test.amount,
+				test.units,
+				true,
+				test.ipfs,
+			)
+			if test.err != nil {
+				assert.Nil(t, script)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, hex.EncodeToString(script))
+
+			asset, err := ParseAssetScript(script)
+			assert.NoError(t, err)
+			assert.Equal(t, &AssetScript{
+				Type:       NewAssetType,
+				Name:       test.name,
+				Amount:     test.amount,
+				Units:      test.units,
+				Reissuable: true,
+				IPFSHash:   test.ipfs,
+			}, asset)
+		})
+	}
+
+	owner, err := OwnerAssetScript(p2pkh, "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc00d72766e6f08524f53455454412175",
+		hex.EncodeToString(owner),
+	)
+}
+
+func TestNewReissueAssetScript(t *testing.T) {
+	p2pkh, _ := hex.DecodeString("76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac")
+
+	tests := map[string]struct {
+		name       string
+		amount     int64
+		units      int
+		reissuable bool
+		ipfs       string
+
+		expected string
+		err      error
+	}{
+		"keep units": {
+			name:       "ROSETTA",
+			amount:     50000000000,
+			units:      KeepAssetUnits,
+			reissuable: true,
+			expected:   "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01672766e7207524f534554544100743ba40b000000ff0175", // nolint
+		},
+		"sub-asset metadata only": {
+			name:     "ROSETTA/SUB",
+			units:    2,
+			ipfs:     "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			expected: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc03c72766e720b524f53455454412f5355420000000000000000020012209d6c2be50f706953479ab9df2ce3edca90b68053c00b3004b7f0accbe1e8eedf75", // nolint
+		},
+		"too precise": {
+			name:   "ROSETTA",
+			amount: 150000000,
+			units:  0,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"invalid units": {
+			name:   "ROSETTA",
+			amount: 100000000,
+			units:  -2,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"negative amount": {
+			name:   "ROSETTA",
+			amount: -1,
+			units:  KeepAssetUnits,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"unique asset": {
+			name:   "ROSETTA#tag",
+			amount: 100000000,
+			units:  KeepAssetUnits,
+			err:    ErrInvalidAssetIssuance,
+		},
+		"invalid name": {
+			name:   "rosetta",
+			amount: 100000000,
+			units:  KeepAssetUnits,
+			err:    ErrInvalidAssetName,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := NewReissueAssetScript(
+				p2pkh,
+				test.name,
+				test.amount,
+				test.units,
+				test.reissuable,
+				test.ipfs,
+			)
+			if test.err != nil {
+				assert.Nil(t, script)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expected, hex.EncodeToString(script))
+
+			asset, err := ParseAssetScript(script)
+			assert.NoError(t, err)
+			assert.Equal(t, &AssetScript{
+				Type:       ReissueAssetType,
+				Name:       test.name,
+				Amount:     test.amount,
+				Units:      test.units,
+				Reissuable: test.reissuable,
+				IPFSHash:   test.ipfs,
+			}, asset)
+		})
+	}
+}
+
+func TestTransferAssetScript(t *testing.T) {
+	p2pkh, _ := hex.DecodeString("76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac")
+
+	script, err := TransferAssetScript(p2pkh, "ROSETTA!", OwnerAssetAmount)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		"76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01572766e7408524f53455454412100e1f5050000000075",
+		hex.EncodeToString(script),
+	)
+
+	asset, err := ParseAssetScript(script)
+	assert.NoError(t, err)
+	assert.Equal(t, &AssetScript{
+		Type:   TransferAssetType,
+		Name:   "ROSETTA!",
+		Amount: OwnerAssetAmount,
+	}, asset)
+
+	script, err = TransferAssetScript(p2pkh, "ROSETTA!", 0)
+	assert.Nil(t, script)
+	assert.True(t, errors.Is(err, ErrInvalidAssetIssuance))
+}
+
+func TestTransferAssetMessageScript(t *testing.T) {
+	p2pkh, _ := hex.DecodeString("76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac")
+
+	tests := map[string]struct {
+		message    string
+		expireTime int64
+
+		script string
+		asset  *AssetScript
+		err    error
+	}{
+		"message with expiry": {
+			message:    "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			expireTime: 1893456000,
+			script:     "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc03e72766e7407524f534554544100743ba40b00000012209d6c2be50f706953479ab9df2ce3edca90b68053c00b3004b7f0accbe1e8eedf80d8db700000000075", // nolint
+			asset: &AssetScript{
+				Type:       TransferAssetType,
+				Name:       "ROSETTA",
+				Amount:     50000000000,
+				Message:    "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+				ExpireTime: 1893456000,
+			},
+		},
+		"message without expiry": {
+			message: "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			script:  "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc03672766e7407524f534554544100743ba40b00000012209d6c2be50f706953479ab9df2ce3edca90b68053c00b3004b7f0accbe1e8eedf75", // nolint
+			asset: &AssetScript{
+				Type:    TransferAssetType,
+				Name:    "ROSETTA",
+				Amount:  50000000000,
+				Message: "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG",
+			},
+		},
+		"expiry without message": {
+			expireTime: 1893456000,
+			err:        ErrInvalidAssetIssuance,
+		},
+		"invalid message": {
+			message: "not-an-ipfs-hash",
+			err:     ErrInvalidAssetIssuance,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			script, err := TransferAssetMessageScript(
+				p2pkh,
+				"ROSETTA",
+				50000000000,
+				test.message,
+				test.expireTime,
+			)
+			if test.err != nil {
+				assert.Nil(t, script)
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.script, hex.EncodeToString(script))
+
+			asset, err := ParseAssetScript(script)
+			assert.NoError(t, err)
+			assert.Equal(t, test.asset, asset)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"fmt"
+	"strings"
+
+	btcdchaincfg "github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil/base58"
+)
+
+const (
+	// base58Alphabet is the alphabet used to encode base58 addresses.
+	base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+	// base58AddressPayloadLen is the length of the payload encoded in a
+	// base58 address after the version byte (a 20 byte hash and a 4 byte
+	// checksum).
+	base58AddressPayloadLen = 24
+
+	// hash160Length is the length of the hash encoded in a base58
+	// address.
+	hash160Length = 20
+)
+
+// AddressPrefixInfo describes the leading characters of the addresses of a
+// network, for display to users.  PubKeyHash and ScriptHash are the
+// characters a pay-to-pubkey-hash or pay-to-script-hash address may start
+// with and Bech32 is the prefix of every segwit address.
+type AddressPrefixInfo struct {
+	PubKeyHash []string `json:"pubkey_hash"`
+	ScriptHash []string `json:"script_hash"`
+	Bech32     string   `json:"bech32"`
+}
+
+// BtcdParams returns the subset of the network parameters used by btcutil
+// and txscript to encode and decode addresses, expressed as btcd
+// parameters.  Only the address encoding magics and the BIP44 coin type are
+// populated, so the result must not be used for consensus checks.
+func (p *Params) BtcdParams() *btcdchaincfg.Params {
+	return &btcdchaincfg.Params{
+		Name:                    p.Name,
+		Net:                     wire.BitcoinNet(p.Net),
+		DefaultPort:             p.DefaultPort,
+		Bech32HRPSegwit:         p.Bech32HRPSegwit,
+		PubKeyHashAddrID:        p.PubKeyHashAddrID,
+		ScriptHashAddrID:        p.ScriptHashAddrID,
+		PrivateKeyID:            p.PrivateKeyID,
+		WitnessPubKeyHashAddrID: p.WitnessPubKeyHashAddrID,
+		WitnessScriptHashAddrID: p.WitnessScriptHashAddrID,
+		HDPrivateKeyID:          p.HDPrivateKeyID,
+		HDPublicKeyID:           p.HDPublicKeyID,
+		HDCoinType:              p.HDCoinType,
+	}
+}
+
+// EncodeP2PKH returns the base58check pay-to-pubkey-hash address of the
+// provided hash160 (the RIPEMD160 of the SHA256 of a public key) for the
+// network.
+func (p *Params) EncodeP2PKH(hash160 []byte) (string, error) {
+	return encodeBase58Address(hash160, p.PubKeyHashAddrID)
+}
+
+// EncodeP2SH returns the base58check pay-to-script-hash address of the
+// provided hash160 (the RIPEMD160 of the SHA256 of a redeem script) for
+// the network.
+func (p *Params) EncodeP2SH(hash160 []byte) (string, error) {
+	return encodeBase58Address(hash160, p.ScriptHashAddrID)
+}
+
+// encodeBase58Address returns the base58check encoding of hash160 with the
+// provided version byte.
+func encodeBase58Address(hash160 []byte, version byte) (string, error) {
+	if len(hash160) != hash160Length {
+		return "", fmt.Errorf("%w: got %d bytes", ErrInvalidHash160, len(hash160))
+	}
+
+	return base58.CheckEncode(hash160, version), nil
+}
+
+// AddressPrefixes returns the leading characters of the addresses encoded
+// with the address magics of the network.  The characters of base58
+// addresses are computed from the range of values a version byte followed
+// by a hash and checksum can encode to, so they stay correct when the
+// address magics are overridden.
+func (p *Params) AddressPrefixes() AddressPrefixInfo {
+	return AddressPrefixInfo{
+		PubKeyHash: base58Prefixes(p.PubKeyHashAddrID),
+		ScriptHash: base58Prefixes(p.ScriptHashAddrID),
+		Bech32:     p.Bech32HRPSegwit + "1",
+	}
+}
+
+// base58Prefixes returns the characters a base58 address with the provided
+// version byte may start with.
+func base58Prefixes(version byte) []string {
+	// Leading zero bytes are each encoded as a leading 1.
+	if version == 0 {
+		return []string{base58Alphabet[:1]}
+	}
+
+	// The smallest and largest payloads after the version byte.
+	low := make([]byte, base58AddressPayloadLen+1)
+	low[0] = version
+	high := make([]byte, base58AddressPayloadLen+1)
+	high[0] = version
+	for i := 1; i < len(high); i++ {
+		high[i] = 0xff
+	}
+
+	lowEncoded := base58.Encode(low)
+	highEncoded := base58.Encode(high)
+	first := strings.IndexByte(base58Alphabet, lowEncoded[0])
+	last := strings.IndexByte(base58Alphabet, highEncoded[0])
+
+	// If the encodings differ in length, the range wraps from the
+	// largest leading digit of the shorter encoding to the smallest
+	// non-zero leading digit of the longer one.
+	var indexes []int
+	if len(lowEncoded) == len(highEncoded) {
+		indexes = indexRange(first, last)
+	} else {
+		indexes = append(indexRange(first, len(base58Alphabet)-1), indexRange(1, last)...)
+	}
+
+	seen := map[int]struct{}{}
+	prefixes := []string{}
+	for _, index := range indexes {
+		if _, ok := seen[index]; ok {
+			continue
+		}
+
+		seen[index] = struct{}{}
+		prefixes = append(prefixes, base58Alphabet[index:index+1])
+	}
+
+	return prefixes
+}
+
+// indexRange returns the integers from first to last (inclusive).
+func indexRange(first int, last int) []int {
+	indexes := []int{}
+	for i := first; i <= last; i++ {
+		indexes = append(indexes, i)
+	}
+
+	return indexes
+}
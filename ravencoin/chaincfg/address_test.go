@@ -0,0 +1,91 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddressPrefixes(t *testing.T) {
+	ravencoinMainNet := MainNetParams
+	ravencoinMainNet.PubKeyHashAddrID = 0x3c
+	ravencoinMainNet.ScriptHashAddrID = 0x7a
+
+	tests := map[string]struct {
+		params *Params
+
+		prefixes AddressPrefixInfo
+	}{
+		"mainnet": {
+			params: &ravencoinMainNet,
+			prefixes: AddressPrefixInfo{
+				PubKeyHash: []string{"R"},
+				ScriptHash: []string{"r"},
+				Bech32:     "bc1",
+			},
+		},
+		"testnet": {
+			params: &TestNet7Params,
+			prefixes: AddressPrefixInfo{
+				PubKeyHash: []string{"m", "n"},
+				ScriptHash: []string{"2"},
+				Bech32:     "tb1",
+			},
+		},
+		"zero version byte": {
+			params: &MainNetParams,
+			prefixes: AddressPrefixInfo{
+				PubKeyHash: []string{"1"},
+				ScriptHash: []string{"3"},
+				Bech32:     "bc1",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.prefixes, test.params.AddressPrefixes())
+		})
+	}
+}
+
+func TestEncodeAddress(t *testing.T) {
+	hash, err := hex.DecodeString("5dd186b3d880571415be6d87ea2ef14d17d505cb")
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		hash160 []byte
+
+		p2pkh string
+		p2sh  string
+		err   error
+	}{
+		"valid": {
+			hash160: hash,
+			p2pkh:   "mp5292cPX7EdBy42fZUHQm5CX8ZH7T2uyb",
+			p2sh:    "2N1oHqFwssSd6houPkDnNdRD521TTaCmZke",
+		},
+		"short hash": {
+			hash160: hash[:19],
+			err:     ErrInvalidHash160,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			p2pkh, err := TestNet7Params.EncodeP2PKH(test.hash160)
+			assert.True(t, errors.Is(err, test.err))
+			assert.Equal(t, test.p2pkh, p2pkh)
+
+			p2sh, err := TestNet7Params.EncodeP2SH(test.hash160)
+			assert.True(t, errors.Is(err, test.err))
+			assert.Equal(t, test.p2sh, p2sh)
+		})
+	}
+}
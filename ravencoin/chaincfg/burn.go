@@ -0,0 +1,99 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+// BurnKind identifies an asset operation that requires paying a fixed fee to
+// a network-specific burn address.
+type BurnKind int
+
+// Constants that define the asset operations that require a burn.
+const (
+	// BurnIssueAsset is the burn required to issue a root asset.
+	BurnIssueAsset BurnKind = iota
+
+	// BurnReissueAsset is the burn required to reissue an asset.
+	BurnReissueAsset
+
+	// BurnIssueSubAsset is the burn required to issue a sub-asset.
+	BurnIssueSubAsset
+
+	// BurnIssueUniqueAsset is the burn required to issue a unique asset.
+	BurnIssueUniqueAsset
+
+	// BurnIssueMsgChannelAsset is the burn required to issue a message
+	// channel asset.
+	BurnIssueMsgChannelAsset
+
+	// BurnIssueQualifierAsset is the burn required to issue a qualifier
+	// asset.
+	BurnIssueQualifierAsset
+
+	// BurnIssueSubQualifierAsset is the burn required to issue a
+	// sub-qualifier asset.
+	BurnIssueSubQualifierAsset
+
+	// BurnIssueRestrictedAsset is the burn required to issue a restricted
+	// asset.
+	BurnIssueRestrictedAsset
+
+	// BurnAddNullQualifierTag is the burn required to tag an address with a
+	// qualifier.
+	BurnAddNullQualifierTag
+
+	// DefinedBurnKinds is the number of currently defined burn kinds.
+	// NOTE: DefinedBurnKinds must always come last since it is used to
+	// determine how many defined burn kinds there currently are.
+	DefinedBurnKinds
+)
+
+// Burn defines an address that a fixed amount must be paid to in order to
+// perform an asset operation.
+type Burn struct {
+	Address string
+
+	// Amount is the amount to burn in Satoshis.
+	Amount int64
+}
+
+// satoshisPerCoin is the number of Satoshis in one RVN.
+const satoshisPerCoin = 1e8
+
+// mainNetBurns are the burns of the main network.
+var mainNetBurns = [DefinedBurnKinds]Burn{
+	BurnIssueAsset:             {"RXissueAssetXXXXXXXXXXXXXXXXXhhZGt", 500 * satoshisPerCoin},
+	BurnReissueAsset:           {"RXReissueAssetXXXXXXXXXXXXXXVEFAWu", 100 * satoshisPerCoin},
+	BurnIssueSubAsset:          {"RXissueSubAssetXXXXXXXXXXXXXWcwhwL", 100 * satoshisPerCoin},
+	BurnIssueUniqueAsset:       {"RXissueUniqueAssetXXXXXXXXXXWEAe58", 5 * satoshisPerCoin},
+	BurnIssueMsgChannelAsset:   {"RXissueMsgChanneLAssetXXXXXXSjHvAY", 100 * satoshisPerCoin},
+	BurnIssueQualifierAsset:    {"RXissueQuaLifierXXXXXXXXXXXXUgEDbC", 1000 * satoshisPerCoin},
+	BurnIssueSubQualifierAsset: {"RXissueSubQuaLifierXXXXXXXXXVTzvv5", 100 * satoshisPerCoin},
+	BurnIssueRestrictedAsset:   {"RXissueRestrictedXXXXXXXXXXXXzJZ1q", 1500 * satoshisPerCoin},
+	BurnAddNullQualifierTag:    {"RXaddTagBurnXXXXXXXXXXXXXXXXZQm5ya", satoshisPerCoin / 10},
+}
+
+// testNet7Burns are the burns of the test network.
+var testNet7Burns = [DefinedBurnKinds]Burn{
+	BurnIssueAsset:             {"n1issueAssetXXXXXXXXXXXXXXXXWdnemQ", 500 * satoshisPerCoin},
+	BurnReissueAsset:           {"n1ReissueAssetXXXXXXXXXXXXXXWG9NLd", 100 * satoshisPerCoin},
+	BurnIssueSubAsset:          {"n1issueSubAssetXXXXXXXXXXXXXbNiH6v", 100 * satoshisPerCoin},
+	BurnIssueUniqueAsset:       {"n1issueUniqueAssetXXXXXXXXXXS4695i", 5 * satoshisPerCoin},
+	BurnIssueMsgChannelAsset:   {"n1issueMsgChanneLAssetXXXXXXT2PBdD", 100 * satoshisPerCoin},
+	BurnIssueQualifierAsset:    {"n1issueQuaLifierXXXXXXXXXXXXUysLTj", 1000 * satoshisPerCoin},
+	BurnIssueSubQualifierAsset: {"n1issueSubQuaLifierXXXXXXXXXYffPLh", 100 * satoshisPerCoin},
+	BurnIssueRestrictedAsset:   {"n1issueRestrictedXXXXXXXXXXXXZVT9V", 1500 * satoshisPerCoin},
+	BurnAddNullQualifierTag:    {"n1addTagBurnXXXXXXXXXXXXXXXXX5oLMH", satoshisPerCoin / 10},
+}
+
+// BurnAddress returns the burn address of the provided kind and the amount
+// (in Satoshis) that must be paid to it. An empty address is returned if the
+// kind is not defined.
+func (p *Params) BurnAddress(kind BurnKind) (string, int64) {
+	if kind < 0 || kind >= DefinedBurnKinds {
+		return "", 0
+	}
+
+	burn := p.Burns[kind]
+	return burn.Address, burn.Amount
+}
@@ -0,0 +1,63 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBurnAddress(t *testing.T) {
+	tests := map[string]struct {
+		params *Params
+		kind   BurnKind
+
+		address string
+		amount  int64
+	}{
+		"mainnet issue asset": {
+			params:  &MainNetParams,
+			kind:    BurnIssueAsset,
+			address: "RXissueAssetXXXXXXXXXXXXXXXXXhhZGt",
+			amount:  50000000000,
+		},
+		"mainnet add null qualifier tag": {
+			params:  &MainNetParams,
+			kind:    BurnAddNullQualifierTag,
+			address: "RXaddTagBurnXXXXXXXXXXXXXXXXZQm5ya",
+			amount:  10000000,
+		},
+		"testnet issue asset": {
+			params:  &TestNet7Params,
+			kind:    BurnIssueAsset,
+			address: "n1issueAssetXXXXXXXXXXXXXXXXWdnemQ",
+			amount:  50000000000,
+		},
+		"undefined kind": {
+			params: &MainNetParams,
+			kind:   DefinedBurnKinds,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			address, amount := test.params.BurnAddress(test.kind)
+			assert.Equal(t, test.address, address)
+			assert.Equal(t, test.amount, amount)
+		})
+	}
+
+	// Every burn address is a valid base58check address.
+	for _, params := range []*Params{&MainNetParams, &TestNet7Params} {
+		for kind := BurnKind(0); kind < DefinedBurnKinds; kind++ {
+			address, amount := params.BurnAddress(kind)
+			_, _, err := base58.CheckDecode(address)
+			assert.NoError(t, err, address)
+			assert.Greater(t, amount, int64(0))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+// CheckpointAt returns the checkpoint at the provided height and a boolean
+// indicating whether a checkpoint exists at that height.
+func (p *Params) CheckpointAt(height int32) (*Checkpoint, bool) {
+	for i := range p.Checkpoints {
+		if p.Checkpoints[i].Height == height {
+			return &p.Checkpoints[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// VerifyCheckpoint returns whether the passed block hash is allowed at the
+// provided height.  This is true when there is no checkpoint at the height or
+// when the hash matches the checkpoint.
+func (p *Params) VerifyCheckpoint(height int32, hash *chainhash.Hash) bool {
+	checkpoint, ok := p.CheckpointAt(height)
+	if !ok {
+		return true
+	}
+
+	return checkpoint.Hash.IsEqual(hash)
+}
+
+// LatestCheckpoint returns the checkpoint with the greatest height or nil if
+// there are no checkpoints.
+//
+// Checkpoints are documented as ordered from oldest to newest, but custom
+// networks may not respect this, so all checkpoints are scanned.
+func (p *Params) LatestCheckpoint() *Checkpoint {
+	var latest *Checkpoint
+	for i := range p.Checkpoints {
+		if latest == nil || p.Checkpoints[i].Height > latest.Height {
+			latest = &p.Checkpoints[i]
+		}
+	}
+
+	return latest
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyCheckpoint(t *testing.T) {
+	tests := map[string]struct {
+		height int32
+		hash   string
+
+		checkpoint bool
+		valid      bool
+	}{
+		"matching checkpoint": {
+			height:     740000,
+			hash:       "00000000000027d11bf1e7a3b57d3c89acc1722f39d6e08f23ac3a07e16e3172",
+			checkpoint: true,
+			valid:      true,
+		},
+		"mismatched checkpoint": {
+			height:     740000,
+			hash:       "000000000000694c9a363eff06518aa7399f00014ce667b9762f9a4e7a49f485",
+			checkpoint: true,
+			valid:      false,
+		},
+		"no checkpoint": {
+			height: 740001,
+			hash:   "000000000000694c9a363eff06518aa7399f00014ce667b9762f9a4e7a49f485",
+			valid:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkpoint, ok := MainNetParams.CheckpointAt(test.height)
+			assert.Equal(t, test.checkpoint, ok)
+			if test.checkpoint {
+				assert.Equal(t, test.height, checkpoint.Height)
+			} else {
+				assert.Nil(t, checkpoint)
+			}
+
+			hash := newHashFromStr(test.hash)
+			assert.Equal(t, test.valid, MainNetParams.VerifyCheckpoint(test.height, hash))
+		})
+	}
+}
+
+func TestLatestCheckpoint(t *testing.T) {
+	tests := map[string]struct {
+		params *Params
+
+		height int32
+		hash   string
+	}{
+		"mainnet": {
+			params: &MainNetParams,
+			height: 1186833,
+			hash:   "0000000000000d4840d4de1f7d943542c2aed532bd5d6527274fc0142fa1a410",
+		},
+		"unordered": {
+			params: &Params{
+				Checkpoints: []Checkpoint{
+					{200, newHashFromStr("00000193aa316faba95ed25accf8da2c1f3783881e7978ba8674bd4b0a409a05")},
+					{300, newHashFromStr("00000008b225feae765220a183eadc715e1ec0e4252b7ea4458585bb9b4ad7af")},
+					{100, newHashFromStr("000000bb2c1bc93f4d14ce74b0cb62d5e05cc08e50be417e628b7c87aa33f942")},
+				},
+			},
+			height: 300,
+			hash:   "00000008b225feae765220a183eadc715e1ec0e4252b7ea4458585bb9b4ad7af",
+		},
+		"no checkpoints": {
+			params: &Params{},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			checkpoint := test.params.LatestCheckpoint()
+			if len(test.hash) == 0 {
+				assert.Nil(t, checkpoint)
+				return
+			}
+
+			assert.Equal(t, test.height, checkpoint.Height)
+			assert.Equal(t, test.hash, checkpoint.Hash.String())
+		})
+	}
+}
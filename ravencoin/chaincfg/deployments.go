@@ -0,0 +1,62 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+// deploymentNames maps the human-readable name of each defined deployment to
+// its index in Params.Deployments.
+var deploymentNames = map[string]int{
+	"testdummy":            DeploymentTestDummy,
+	"assets":               DeploymentAssets,
+	"msg_rest_assets":      DeploymentMsgRestAssets,
+	"transfer_script_size": DeploymentTransferScriptSize,
+	"enforce_value":        DeploymentEnforceValue,
+	"coinbase_assets":      DeploymentCoinbaseAssets,
+}
+
+// DeploymentID returns the index in Params.Deployments of the deployment with
+// the provided name (for example "assets" or "coinbase_assets") and whether
+// the name is known.
+func DeploymentID(name string) (int, bool) {
+	id, ok := deploymentNames[name]
+	return id, ok
+}
+
+// Deployment returns the deployment with the provided name and whether the
+// name is known.
+func (p *Params) Deployment(name string) (*ConsensusDeployment, bool) {
+	id, ok := DeploymentID(name)
+	if !ok {
+		return nil, false
+	}
+
+	return &p.Deployments[id], true
+}
+
+// DeploymentStarted returns whether voting on the deployment with the provided
+// id has started at the provided median block time.  As in BIP0009, voting
+// starts once the median time reaches StartTime.  False is returned for an
+// unknown id.
+//
+// This does not track the threshold state of the deployment, so a started
+// deployment is not necessarily locked in or active.
+func (p *Params) DeploymentStarted(id int, medianTime uint64) bool {
+	if id < 0 || id >= len(p.Deployments) {
+		return false
+	}
+
+	return medianTime >= p.Deployments[id].StartTime
+}
+
+// DeploymentExpired returns whether the deployment with the provided id has
+// expired at the provided median block time.  As in BIP0009, a deployment
+// expires once the median time reaches ExpireTime.  False is returned for an
+// unknown id.
+func (p *Params) DeploymentExpired(id int, medianTime uint64) bool {
+	if id < 0 || id >= len(p.Deployments) {
+		return false
+	}
+
+	return medianTime >= p.Deployments[id].ExpireTime
+}
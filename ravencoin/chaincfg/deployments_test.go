@@ -0,0 +1,93 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployment(t *testing.T) {
+	tests := map[string]struct {
+		id    int
+		known bool
+	}{
+		"testdummy":            {id: DeploymentTestDummy, known: true},
+		"assets":               {id: DeploymentAssets, known: true},
+		"msg_rest_assets":      {id: DeploymentMsgRestAssets, known: true},
+		"transfer_script_size": {id: DeploymentTransferScriptSize, known: true},
+		"enforce_value":        {id: DeploymentEnforceValue, known: true},
+		"coinbase_assets":      {id: DeploymentCoinbaseAssets, known: true},
+		"segwit":               {},
+	}
+
+	// Every defined deployment has a name.
+	assert.Len(t, deploymentNames, DefinedDeployments)
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			id, ok := DeploymentID(name)
+			assert.Equal(t, test.known, ok)
+			assert.Equal(t, test.id, id)
+
+			deployment, ok := MainNetParams.Deployment(name)
+			assert.Equal(t, test.known, ok)
+			if test.known {
+				assert.Equal(t, &MainNetParams.Deployments[test.id], deployment)
+			} else {
+				assert.Nil(t, deployment)
+			}
+		})
+	}
+}
+
+func TestDeploymentWindow(t *testing.T) {
+	assets := MainNetParams.Deployments[DeploymentAssets]
+
+	tests := map[string]struct {
+		id         int
+		medianTime uint64
+
+		started bool
+		expired bool
+	}{
+		"before start": {
+			id:         DeploymentAssets,
+			medianTime: assets.StartTime - 1,
+		},
+		"at start": {
+			id:         DeploymentAssets,
+			medianTime: assets.StartTime,
+			started:    true,
+		},
+		"before expiry": {
+			id:         DeploymentAssets,
+			medianTime: assets.ExpireTime - 1,
+			started:    true,
+		},
+		"at expiry": {
+			id:         DeploymentAssets,
+			medianTime: assets.ExpireTime,
+			started:    true,
+			expired:    true,
+		},
+		"negative id": {
+			id:         -1,
+			medianTime: assets.ExpireTime,
+		},
+		"unknown id": {
+			id:         DefinedDeployments,
+			medianTime: assets.ExpireTime,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.started, MainNetParams.DeploymentStarted(test.id, test.medianTime))
+			assert.Equal(t, test.expired, MainNetParams.DeploymentExpired(test.id, test.medianTime))
+		})
+	}
+}
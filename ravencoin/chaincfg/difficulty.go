@@ -0,0 +1,75 @@
+// Copyright (c) 2013-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+)
+
+// CalcNextRequiredBits returns the difficulty (in compact form) required
+// for the first block of a retarget interval, given the difficulty of the
+// last block of the previous interval and the time it took to mine it.
+//
+// The target is scaled by actualTimespan / TargetTimespan, with the actual
+// timespan clamped so the target changes by at most RetargetAdjustmentFactor
+// in either direction, and it never exceeds PowLimit.
+//
+// This is the retarget algorithm before Dark Gravity Wave, which recomputes
+// the difficulty on every block and is not implemented here.
+func (p *Params) CalcNextRequiredBits(lastBits uint32, actualTimespan time.Duration) uint32 {
+	// Limit the amount of adjustment that can occur to the previous
+	// difficulty.  Block timestamps only have a resolution of seconds.
+	targetTimespan := int64(p.TargetTimespan / time.Second)
+	adjustedTimespan := int64(actualTimespan / time.Second)
+	minRetargetTimespan := targetTimespan / p.RetargetAdjustmentFactor
+	maxRetargetTimespan := targetTimespan * p.RetargetAdjustmentFactor
+	if adjustedTimespan < minRetargetTimespan {
+		adjustedTimespan = minRetargetTimespan
+	} else if adjustedTimespan > maxRetargetTimespan {
+		adjustedTimespan = maxRetargetTimespan
+	}
+
+	// Calculate new target difficulty as:
+	//  currentDifficulty * (adjustedTimespan / targetTimespan)
+	// The result uses integer division which means it will be slightly
+	// rounded down.
+	oldTarget := blockchain.CompactToBig(lastBits)
+	newTarget := new(big.Int).Mul(oldTarget, big.NewInt(adjustedTimespan))
+	newTarget.Div(newTarget, big.NewInt(targetTimespan))
+
+	// Limit new value to the proof of work limit.
+	if newTarget.Cmp(p.PowLimit) > 0 {
+		newTarget.Set(p.PowLimit)
+	}
+
+	return blockchain.BigToCompact(newTarget)
+}
+
+// DifficultyOneTarget is the target of difficulty 1 (0x1d00ffff in compact
+// form), relative to which ravend reports the difficulty of every network.
+var DifficultyOneTarget = blockchain.CompactToBig(0x1d00ffff)
+
+// CompactToDifficulty returns the difficulty of the target encoded in compact
+// form by bits, relative to the target of difficulty 1 given by powLimit.  A
+// difficulty of 0 is returned for a target that is not positive.
+//
+// ravend reports the difficulty of every network relative to 0x1d00ffff (as
+// bitcoind does), so DifficultyOneTarget should be passed to match it.
+func CompactToDifficulty(bits uint32, powLimit *big.Int) float64 {
+	target := blockchain.CompactToBig(bits)
+	if target.Sign() <= 0 || powLimit == nil {
+		return 0
+	}
+
+	difficulty, _ := new(big.Float).Quo(
+		new(big.Float).SetInt(powLimit),
+		new(big.Float).SetInt(target),
+	).Float64()
+
+	return difficulty
+}
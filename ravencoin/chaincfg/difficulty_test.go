@@ -0,0 +1,123 @@
+// Copyright (c) 2013-2017 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/blockchain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcNextRequiredBits(t *testing.T) {
+	// 1.4 days
+	targetTimespan := MainNetParams.TargetTimespan
+
+	tests := map[string]struct {
+		lastBits       uint32
+		actualTimespan time.Duration
+
+		bits uint32
+	}{
+		"on target": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan,
+			bits:           0x1b0404cb,
+		},
+		"twice as fast": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan / 2,
+			bits:           0x1b020265,
+		},
+		"twice as slow": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan * 2,
+			bits:           0x1b080996,
+		},
+		"minimum timespan": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan / 4,
+			bits:           0x1b010132,
+		},
+		"below minimum timespan": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan / 10,
+			bits:           0x1b010132,
+		},
+		"maximum timespan": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan * 4,
+			bits:           0x1b10132c,
+		},
+		"above maximum timespan": {
+			lastBits:       0x1b0404cb,
+			actualTimespan: targetTimespan * 10,
+			bits:           0x1b10132c,
+		},
+		"at pow limit": {
+			lastBits:       MainNetParams.PowLimitBits,
+			actualTimespan: targetTimespan * 2,
+			bits:           MainNetParams.PowLimitBits,
+		},
+		"exceeds pow limit": {
+			lastBits:       0x1c7fffff,
+			actualTimespan: targetTimespan * 4,
+			bits:           MainNetParams.PowLimitBits,
+		},
+		"below pow limit": {
+			lastBits:       0x1c7fffff,
+			actualTimespan: targetTimespan,
+			bits:           0x1c7fffff,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(
+				t,
+				test.bits,
+				MainNetParams.CalcNextRequiredBits(test.lastBits, test.actualTimespan),
+			)
+		})
+	}
+}
+
+func TestCompactToDifficulty(t *testing.T) {
+	diffOneLimit := blockchain.CompactToBig(0x1d00ffff)
+
+	tests := map[string]struct {
+		bits     uint32
+		powLimit *big.Int
+
+		difficulty float64
+	}{
+		"difficulty 1": {
+			bits:       0x1d00ffff,
+			powLimit:   diffOneLimit,
+			difficulty: 1,
+		},
+		"harder target": {
+			bits:       0x1b0404cb,
+			powLimit:   diffOneLimit,
+			difficulty: 16307.420938523983,
+		},
+		"zero target": {
+			bits:     0x1d000000,
+			powLimit: diffOneLimit,
+		},
+		"negative target": {
+			bits:     0x1d800001,
+			powLimit: diffOneLimit,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.InDelta(t, test.difficulty, CompactToDifficulty(test.bits, test.powLimit), 1e-9)
+		})
+	}
+}
@@ -5,11 +5,13 @@
 package chaincfg
 
 import (
+	"encoding/json"
 	"errors"
 	"math/big"
 	"strings"
 	"time"
 	"fmt"
+	"strconv"
 
 	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcd/chaincfg/chainhash" //this import is safe, just generic hash utils.
@@ -208,6 +210,14 @@ type Params struct {
 	// BIP44 coin type used in the hierarchical deterministic path for
 	// address generation.
 	HDCoinType uint32
+
+	// Burns define the addresses and amounts that must be paid to in
+	// order to perform asset operations, indexed by BurnKind.
+	Burns [DefinedBurnKinds]Burn
+
+	// GlobalBurnAddress is the address used to burn coins outside of asset
+	// operations.
+	GlobalBurnAddress string
 }
 
 
@@ -321,7 +331,7 @@ var MainNetParams = Params{
 	BIP0066Height:            363725, // 00000000000000000379eaa19dce8c9b722d46ae6a57c2f1a988119488b50931
 	CoinbaseMaturity:         100,
 	SubsidyReductionInterval: 2100000,
-	TargetTimespan:           2016 * 60,           // 1.4 days
+	TargetTimespan:           time.Minute * 2016,  // 1.4 days
 	TargetTimePerBlock:       time.Minute * 1,     // 10 minutes
 	RetargetAdjustmentFactor: 4,                   // 25% less, 400% more
 	ReduceMinDifficulty:      false,
@@ -426,7 +436,9 @@ var MainNetParams = Params{
 	// address generation.
 	HDCoinType: 175,
 
-	
+	// Asset burn addresses and amounts
+	Burns:             mainNetBurns,
+	GlobalBurnAddress: "RXBurnXXXXXXXXXXXXXXXXXXXXXXWUo9FV",
 }
 
 // TestNet7Params defines the network parameters for the test Ravencoin network
@@ -528,6 +540,10 @@ var TestNet7Params = Params{
 	// BIP44 coin type used in the hierarchical deterministic path for
 	// address generation.
 	HDCoinType: 1,
+
+	// Asset burn addresses and amounts
+	Burns:             testNet7Burns,
+	GlobalBurnAddress: "n1BurnXXXXXXXXXXXXXXXXXXXXXXU1qejP",
 }
 
 var (
@@ -544,6 +560,20 @@ var (
 	// ErrInvalidHDKeyID describes an error where the provided hierarchical
 	// deterministic version bytes, or hd key id, is malformed.
 	ErrInvalidHDKeyID = errors.New("invalid hd extended key version bytes")
+
+	// ErrAddressIDCollision describes an error where an address version
+	// byte override is already used by a default or registered network,
+	// which would make addresses ambiguous between networks.
+	ErrAddressIDCollision = errors.New("address version byte collides with a registered network")
+
+	// ErrInvalidNet describes an error where a RavencoinNet could not be
+	// decoded because it is neither a known network name nor a network
+	// magic.
+	ErrInvalidNet = errors.New("invalid Ravencoin network")
+
+	// ErrInvalidHash160 describes an error where a hash to be encoded
+	// in a base58 address is not 20 bytes long.
+	ErrInvalidHash160 = errors.New("hash160 must be 20 bytes")
 )
 
 var (
@@ -595,6 +625,51 @@ func mustRegister(params *Params) {
 	}
 }
 
+// OverrideAddressIDs returns a copy of params that encodes pay-to-pubkey-hash
+// and pay-to-script-hash addresses with the provided version bytes.  This
+// allows forks that only differ in their address prefixes to reuse the
+// parameters of an existing network without recompiling this package.
+//
+// An error wrapping ErrAddressIDCollision is returned if either version byte
+// is used by any default or registered network (other than for the same
+// purpose on params itself) or if both version bytes are the same.
+//
+// The returned parameters share the Net of params, so they are not
+// registered.
+func OverrideAddressIDs(params *Params, pubKeyHashAddrID byte, scriptHashAddrID byte) (*Params, error) {
+	if pubKeyHashAddrID == scriptHashAddrID {
+		return nil, fmt.Errorf(
+			"%w: pubkey hash and script hash ids are both %#x",
+			ErrAddressIDCollision,
+			pubKeyHashAddrID,
+		)
+	}
+
+	if pubKeyHashAddrID != params.PubKeyHashAddrID &&
+		(IsPubKeyHashAddrID(pubKeyHashAddrID) || IsScriptHashAddrID(pubKeyHashAddrID)) {
+		return nil, fmt.Errorf(
+			"%w: pubkey hash id %#x",
+			ErrAddressIDCollision,
+			pubKeyHashAddrID,
+		)
+	}
+
+	if scriptHashAddrID != params.ScriptHashAddrID &&
+		(IsPubKeyHashAddrID(scriptHashAddrID) || IsScriptHashAddrID(scriptHashAddrID)) {
+		return nil, fmt.Errorf(
+			"%w: script hash id %#x",
+			ErrAddressIDCollision,
+			scriptHashAddrID,
+		)
+	}
+
+	overridden := *params
+	overridden.PubKeyHashAddrID = pubKeyHashAddrID
+	overridden.ScriptHashAddrID = scriptHashAddrID
+
+	return &overridden, nil
+}
+
 // IsPubKeyHashAddrID returns whether the id is an identifier known to prefix a
 // pay-to-pubkey-hash address on any default or registered network.  This is
 // used when decoding an address string into a specific address type.  It is up
@@ -718,6 +793,50 @@ func (n RavencoinNet) String() string {
 	return fmt.Sprintf("Unknown RavencoinNet (%d)", uint32(n))
 }
 
+// MarshalJSON encodes the RavencoinNet as its human-readable name.  Networks
+// without a name are encoded as their numeric magic so they can be decoded
+// again.
+func (n RavencoinNet) MarshalJSON() ([]byte, error) {
+	if s, ok := bnStrings[n]; ok {
+		return json.Marshal(s)
+	}
+
+	return json.Marshal(uint32(n))
+}
+
+// UnmarshalJSON decodes a RavencoinNet from either its human-readable name
+// (such as "MainNet") or its numeric magic.  The magic may be provided as a
+// number or as a string in any base accepted by strconv.ParseUint (such as
+// "0x5241564e").  Magics of unknown networks are accepted and print as
+// unknown networks.
+func (n *RavencoinNet) UnmarshalJSON(data []byte) error {
+	var magic uint32
+	if err := json.Unmarshal(data, &magic); err == nil {
+		*n = RavencoinNet(magic)
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidNet, string(data))
+	}
+
+	for net, name := range bnStrings {
+		if strings.EqualFold(name, s) {
+			*n = net
+			return nil
+		}
+	}
+
+	parsed, err := strconv.ParseUint(s, 0, 32)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidNet, s)
+	}
+
+	*n = RavencoinNet(parsed)
+	return nil
+}
+
 func init() {
 	// Register all default networks when the package is initialized.
 	mustRegister(&MainNetParams)
@@ -0,0 +1,89 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRavencoinNetJSON(t *testing.T) {
+	tests := map[string]struct {
+		json string
+
+		net RavencoinNet
+		err bool
+	}{
+		"mainnet name": {
+			json: `"MainNet"`,
+			net:  MainNet,
+		},
+		"testnet name": {
+			json: `"testnet7"`,
+			net:  TestNet7,
+		},
+		"mainnet magic": {
+			json: `1380013646`,
+			net:  MainNet,
+		},
+		"testnet hex magic": {
+			json: `"0x0709110b"`,
+			net:  TestNet7,
+		},
+		"unknown magic": {
+			json: `42`,
+			net:  RavencoinNet(42),
+		},
+		"garbage name": {
+			json: `"ravencoin"`,
+			err:  true,
+		},
+		"negative magic": {
+			json: `-1`,
+			err:  true,
+		},
+		"magic overflow": {
+			json: `"0x100000000"`,
+			err:  true,
+		},
+		"wrong type": {
+			json: `true`,
+			err:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var net RavencoinNet
+			err := json.Unmarshal([]byte(test.json), &net)
+			if test.err {
+				assert.True(t, errors.Is(err, ErrInvalidNet))
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.net, net)
+		})
+	}
+
+	// Every default network round-trips through its name.
+	for _, params := range []*Params{&MainNetParams, &TestNet7Params} {
+		encoded, err := json.Marshal(params.Net)
+		assert.NoError(t, err)
+		assert.Equal(t, `"`+params.Net.String()+`"`, string(encoded))
+
+		var decoded RavencoinNet
+		assert.NoError(t, json.Unmarshal(encoded, &decoded))
+		assert.Equal(t, params.Net, decoded)
+	}
+
+	// Unknown networks round-trip through their magic.
+	encoded, err := json.Marshal(RavencoinNet(42))
+	assert.NoError(t, err)
+	assert.Equal(t, `42`, string(encoded))
+}
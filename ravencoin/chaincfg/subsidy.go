@@ -0,0 +1,42 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+const (
+	// satoshiPerRavencoin is the number of satoshis in one Ravencoin.
+	satoshiPerRavencoin = 100000000
+
+	// baseSubsidy is the starting subsidy amount (in satoshis) for mined
+	// blocks.  Unlike Bitcoin's 50 BTC, Ravencoin blocks start at
+	// 5000 RVN.  This value is halved every SubsidyReductionInterval
+	// blocks.
+	baseSubsidy = 5000 * satoshiPerRavencoin
+)
+
+// CalcBlockSubsidy returns the subsidy amount (in satoshis) a block at the
+// provided height should have.  This is mainly used for determining how much
+// the coinbase for newly generated blocks awards as well as validating the
+// coinbase for blocks has the expected value.
+//
+// The subsidy is halved every SubsidyReductionInterval blocks.  Mathematically
+// this is: baseSubsidy / 2^(height/SubsidyReductionInterval)
+//
+// At the target block generation rate for the main network, this is
+// approximately every 4 years.
+func (p *Params) CalcBlockSubsidy(height int32) int64 {
+	if p.SubsidyReductionInterval == 0 || height < 0 {
+		return baseSubsidy
+	}
+
+	// Once the subsidy has been halved 64 times it is zero
+	// (and shifting by 64 or more is undefined for int64).
+	halvings := uint(height / p.SubsidyReductionInterval)
+	if halvings >= 64 {
+		return 0
+	}
+
+	// Equivalent to: baseSubsidy / 2^halvings
+	return baseSubsidy >> halvings
+}
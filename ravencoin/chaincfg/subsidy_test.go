@@ -0,0 +1,62 @@
+// Copyright (c) 2014-2016 The btcsuite developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package chaincfg
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcBlockSubsidy(t *testing.T) {
+	tests := map[string]struct {
+		params *Params
+		height int32
+
+		subsidy int64
+	}{
+		"genesis": {
+			params:  &MainNetParams,
+			height:  0,
+			subsidy: 500000000000,
+		},
+		"before first halving": {
+			params:  &MainNetParams,
+			height:  2099999,
+			subsidy: 500000000000,
+		},
+		"first halving": {
+			params:  &MainNetParams,
+			height:  2100000,
+			subsidy: 250000000000,
+		},
+		"second halving": {
+			params:  &MainNetParams,
+			height:  4200000,
+			subsidy: 125000000000,
+		},
+		"last non-zero subsidy": {
+			params:  &MainNetParams,
+			height:  38 * 2100000,
+			subsidy: 1,
+		},
+		"zero subsidy": {
+			params:  &MainNetParams,
+			height:  39 * 2100000,
+			subsidy: 0,
+		},
+		"testnet first halving": {
+			params:  &TestNet7Params,
+			height:  210000,
+			subsidy: 250000000000,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.subsidy, test.params.CalcBlockSubsidy(test.height))
+		})
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math/big"
 	"net"
 	"net/http"
 	"strconv"
@@ -65,9 +66,15 @@ const (
 	// https://bitcoin.org/en/developer-reference#getblockchaininfo
 	requestMethodGetBlockchainInfo requestMethod = "getblockchaininfo"
 
+	// https://developer.bitcoin.org/reference/rpc/getbestblockhash.html
+	requestMethodGetBestBlockHash requestMethod = "getbestblockhash"
+
 	// https://developer.bitcoin.org/reference/rpc/getpeerinfo.html
 	requestMethodGetPeerInfo requestMethod = "getpeerinfo"
 
+	// https://developer.bitcoin.org/reference/rpc/getnetworkinfo.html
+	requestMethodGetNetworkInfo requestMethod = "getnetworkinfo"
+
 	// https://developer.bitcoin.org/reference/rpc/pruneblockchain.html
 	requestMethodPruneBlockchain requestMethod = "pruneblockchain"
 
@@ -80,8 +87,33 @@ const (
 	// https://developer.bitcoin.org/reference/rpc/getrawmempool.html
 	requestMethodRawMempool requestMethod = "getrawmempool"
 
+	// https://developer.bitcoin.org/reference/rpc/getmempoolentry.html
+	requestMethodGetMempoolEntry requestMethod = "getmempoolentry"
+
+	// https://developer.bitcoin.org/reference/rpc/getrawtransaction.html
+	requestMethodGetRawTransaction requestMethod = "getrawtransaction"
+
+	// https://developer.bitcoin.org/reference/rpc/getblockheader.html
+	requestMethodGetBlockHeader requestMethod = "getblockheader"
+
+	// https://ravencoin.org/assets/ (getassetdata)
+	requestMethodGetAssetData requestMethod = "getassetdata"
+
 	// blockNotFoundErrCode is the RPC error code when a block cannot be found
 	blockNotFoundErrCode = -5
+
+	// transactionNotInMempoolErrCode is the RPC error code when a
+	// transaction is not in the mempool
+	transactionNotInMempoolErrCode = -5
+
+	// transactionRejectedErrCode is the RPC error code when a
+	// transaction is rejected by the mempool (including when
+	// it is already in the mempool)
+	transactionRejectedErrCode = -26
+
+	// transactionAlreadyInChainErrCode is the RPC error code
+	// when a transaction is already in the block chain
+	transactionAlreadyInChainErrCode = -27
 )
 
 const (
@@ -107,6 +139,15 @@ var (
 
 	// ErrJSONRPCError is returned when receiving an error from a JSON-RPC response
 	ErrJSONRPCError = errors.New("JSON-RPC error")
+
+	// ErrTransactionNotInMempool is returned when the requested
+	// transaction is not in the mempool of the node
+	ErrTransactionNotInMempool = errors.New("transaction not in mempool")
+
+	// ErrTransactionAlreadyKnown is returned when a submitted
+	// transaction is already in the mempool or block chain
+	// of the node
+	ErrTransactionAlreadyKnown = errors.New("transaction already known")
 )
 
 // Client is used to fetch blocks from ravend and
@@ -163,14 +204,16 @@ func newHTTPClient(timeout time.Duration) *http.Client {
 // NetworkStatus returns the *types.NetworkStatusResponse for
 // ravend.
 func (b *Client) NetworkStatus(ctx context.Context) (*types.NetworkStatusResponse, error) {
-	rawBlock, err := b.getBlock(ctx, nil)
+	// Only the header of the current block is needed,
+	// so we avoid fetching its transactions.
+	hash, err := b.getBlockHash(ctx, nil)
 	if err != nil {
-		return nil, fmt.Errorf("%w: unable to get current block", err)
+		return nil, fmt.Errorf("%w: unable to get current block hash", err)
 	}
 
-	currentBlock, err := b.parseBlockData(rawBlock)
+	header, err := b.GetBlockHeader(ctx, hash)
 	if err != nil {
-		return nil, fmt.Errorf("%w: unable to parse current block", err)
+		return nil, fmt.Errorf("%w: unable to get current block header", err)
 	}
 
 	peers, err := b.GetPeers(ctx)
@@ -179,8 +222,11 @@ func (b *Client) NetworkStatus(ctx context.Context) (*types.NetworkStatusRespons
 	}
 
 	return &types.NetworkStatusResponse{
-		CurrentBlockIdentifier: currentBlock.BlockIdentifier,
-		CurrentBlockTimestamp:  currentBlock.Timestamp,
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Hash:  header.Hash,
+			Index: header.Height,
+		},
+		CurrentBlockTimestamp:  header.Time * timeMultiplier,
 		GenesisBlockIdentifier: b.genesisBlockIdentifier,
 		Peers:                  peers,
 	}, nil
@@ -281,9 +327,11 @@ func (b *Client) SendRawTransaction(
 	return response.Result, nil
 }
 
-// SuggestedFeeRate estimates the approximate fee per vKB needed
-// to get a transaction in a block within conf_target.
-func (b *Client) SuggestedFeeRate(
+// SuggestedFeeRatePerKB estimates the fee rate (in RVN per
+// 1000 vbytes) needed to get a transaction in a block within
+// conf_target. Use SuggestedFeeRatePerByte to calculate the
+// fee of a transaction from its vsize.
+func (b *Client) SuggestedFeeRatePerKB(
 	ctx context.Context,
 	confTarget int64,
 ) (float64, error) {
@@ -299,6 +347,21 @@ func (b *Client) SuggestedFeeRate(
 	return response.Result.FeeRate, nil
 }
 
+// SuggestedFeeRatePerByte estimates the fee rate (in Satoshis
+// per vbyte) needed to get a transaction in a block within
+// conf_target.
+func (b *Client) SuggestedFeeRatePerByte(
+	ctx context.Context,
+	confTarget int64,
+) (float64, error) {
+	feePerKB, err := b.SuggestedFeeRatePerKB(ctx, confTarget)
+	if err != nil {
+		return -1, err
+	}
+
+	return FeeRatePerByte(feePerKB), nil
+}
+
 // PruneBlockchain prunes up to the provided height.
 // https://ravencoincore.org/en/doc/0.20.0/rpc/blockchain/pruneblockchain
 func (b *Client) PruneBlockchain(
@@ -318,9 +381,9 @@ func (b *Client) PruneBlockchain(
 	return response.Result, nil
 }
 
-// RawMempool returns an array of all transaction
+// GetRawMempool returns an array of all transaction
 // hashes currently in the mempool.
-func (b *Client) RawMempool(
+func (b *Client) GetRawMempool(
 	ctx context.Context,
 ) ([]string, error) {
 	// Parameters:
@@ -335,6 +398,259 @@ func (b *Client) RawMempool(
 	return response.Result, nil
 }
 
+// GetMempoolEntry returns the *MempoolEntry of a
+// transaction in the mempool.
+func (b *Client) GetMempoolEntry(
+	ctx context.Context,
+	txHash string,
+) (*MempoolEntry, error) {
+	// Parameters:
+	//   1. txid
+	params := []interface{}{txHash}
+
+	response := &mempoolEntryResponse{}
+	if err := b.post(ctx, requestMethodGetMempoolEntry, params, response); err != nil {
+		return nil, fmt.Errorf("%w: error getting mempool entry %s", err, txHash)
+	}
+
+	return response.Result, nil
+}
+
+// GetCoin fetches the output identified by coinIdentifier
+// and the height of the block that created it. This is used
+// to hydrate inputs that spend outputs created before the
+// indexer began syncing, so ravend must be run with txindex.
+func (b *Client) GetCoin(
+	ctx context.Context,
+	coinIdentifier string,
+) (*types.AccountCoin, int64, error) {
+	txHash, vout, err := ParseCoinIdentifier(&types.CoinIdentifier{
+		Identifier: coinIdentifier,
+	})
+	if err != nil {
+		return nil, -1, fmt.Errorf("%w: unable to parse coin identifier", err)
+	}
+
+	tx, err := b.GetRawTransaction(ctx, txHash.String())
+	if err != nil {
+		return nil, -1, err
+	}
+
+	if len(tx.BlockHash) == 0 {
+		return nil, -1, fmt.Errorf("transaction %s is not in a block", tx.Hash)
+	}
+
+	coin, err := b.outputCoin(tx, int64(vout))
+	if err != nil {
+		return nil, -1, err
+	}
+
+	header, err := b.GetBlockHeader(ctx, tx.BlockHash)
+	if err != nil {
+		return nil, -1, fmt.Errorf("%w: unable to get block header %s", err, tx.BlockHash)
+	}
+
+	return coin, header.Height, nil
+}
+
+// outputCoin returns the *types.AccountCoin created by
+// output vout of tx.
+func (b *Client) outputCoin(tx *Transaction, vout int64) (*types.AccountCoin, error) {
+	if vout < 0 || int(vout) >= len(tx.Outputs) {
+		return nil, fmt.Errorf("transaction %s has no output %d", tx.Hash, vout)
+	}
+
+	coinIdentifier := CoinIdentifier(tx.Hash, vout)
+	op, err := b.parseOutputTransactionOperation(
+		tx.Outputs[vout],
+		tx.Hash,
+		vout,
+		vout,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse output %s", err, coinIdentifier)
+	}
+
+	return &types.AccountCoin{
+		Account: op.Account,
+		Coin: &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: coinIdentifier,
+			},
+			Amount: op.Amount,
+		},
+	}, nil
+}
+
+// GetMempoolTransaction returns the *types.Transaction of a
+// transaction in the mempool, with its inputs hydrated from
+// the outputs they spend (which may also be in the mempool).
+// If the transaction is not in the mempool,
+// ErrTransactionNotInMempool is returned.
+func (b *Client) GetMempoolTransaction(
+	ctx context.Context,
+	txHash string,
+) (*types.Transaction, error) {
+	if _, err := b.GetMempoolEntry(ctx, txHash); err != nil {
+		return nil, err
+	}
+
+	tx, err := b.GetRawTransaction(ctx, txHash)
+	if err != nil {
+		return nil, err
+	}
+
+	coins := map[string]*types.AccountCoin{}
+	prevTxs := map[string]*Transaction{}
+	for _, input := range tx.Inputs {
+		prevTx, ok := prevTxs[input.TxHash]
+		if !ok {
+			prevTx, err = b.GetRawTransaction(ctx, input.TxHash)
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to get previous transaction", err)
+			}
+
+			prevTxs[input.TxHash] = prevTx
+		}
+
+		coin, err := b.outputCoin(prevTx, input.Vout)
+		if err != nil {
+			return nil, err
+		}
+
+		coins[coin.Coin.CoinIdentifier.Identifier] = coin
+	}
+
+	// Transactions in the mempool are never coinbase
+	// transactions (which are only valid in a block).
+	txOps, err := b.parseTxOperations(tx, -1, coins)
+	if err != nil {
+		return nil, fmt.Errorf("%w: error parsing transaction operations", err)
+	}
+
+	metadata, err := tx.Metadata()
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get metadata for transaction", err)
+	}
+
+	flows, err := b.assetFlows(txOps)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to compute asset flows", err)
+	}
+
+	if flows != nil {
+		metadata[AssetFlowsMetadataKey] = flows
+	}
+
+	return &types.Transaction{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: tx.Hash,
+		},
+		Operations: txOps,
+		Metadata:   metadata,
+	}, nil
+}
+
+// GetBestBlock returns the height of the
+// current best block.
+func (b *Client) GetBestBlock(ctx context.Context) (int64, error) {
+	info, err := b.getBlockchainInfo(ctx)
+	if err != nil {
+		return -1, fmt.Errorf("%w: unable to get blockchain info", err)
+	}
+
+	return info.Blocks, nil
+}
+
+// GetBestBlockHash returns the hash of the
+// current best block.
+func (b *Client) GetBestBlockHash(ctx context.Context) (string, error) {
+	// Parameters:
+	//   None
+	// https://developer.bitcoin.org/reference/rpc/getbestblockhash.html
+	params := []interface{}{}
+
+	response := &blockHashResponse{}
+	if err := b.post(ctx, requestMethodGetBestBlockHash, params, response); err != nil {
+		return "", fmt.Errorf("%w: error fetching best block hash", err)
+	}
+
+	return response.Result, nil
+}
+
+// GetNodeInfo returns the version, height, and number
+// of connections of ravend.
+func (b *Client) GetNodeInfo(ctx context.Context) (*NodeInfo, error) {
+	network, err := b.getNetworkInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get network info", err)
+	}
+
+	info, err := b.getBlockchainInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to get blockchain info", err)
+	}
+
+	return &NodeInfo{
+		ProtocolVersion: network.ProtocolVersion,
+		SubVersion:      network.SubVersion,
+		Blocks:          info.Blocks,
+		Connections:     network.Connections,
+	}, nil
+}
+
+// getNetworkInfo performs the `getnetworkinfo` JSON-RPC request
+func (b *Client) getNetworkInfo(
+	ctx context.Context,
+) (*networkInfo, error) {
+	params := []interface{}{}
+	response := &networkInfoResponse{}
+	if err := b.post(ctx, requestMethodGetNetworkInfo, params, response); err != nil {
+		return nil, fmt.Errorf("%w: error posting to JSON-RPC", err)
+	}
+
+	return response.Result, nil
+}
+
+// GetBlockHeader returns the *BlockHeader of the
+// block with the provided hash, without fetching
+// its transactions.
+func (b *Client) GetBlockHeader(ctx context.Context, hash string) (*BlockHeader, error) {
+	// Parameters:
+	//   1. blockhash
+	//   2. verbose
+	params := []interface{}{hash, true}
+
+	response := &blockHeaderResponse{}
+	if err := b.post(ctx, requestMethodGetBlockHeader, params, response); err != nil {
+		return nil, fmt.Errorf("%w: error fetching block header", err)
+	}
+
+	return response.Result, nil
+}
+
+// GetBlockHeight returns the height of the
+// block with the provided hash.
+func (b *Client) GetBlockHeight(ctx context.Context, hash string) (int64, error) {
+	header, err := b.GetBlockHeader(ctx, hash)
+	if err != nil {
+		return -1, err
+	}
+
+	return header.Height, nil
+}
+
+// GetBlockHeaderByHeight returns the *BlockHeader
+// of the block at the provided height.
+func (b *Client) GetBlockHeaderByHeight(ctx context.Context, height int64) (*BlockHeader, error) {
+	hash, err := b.GetHashFromIndex(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.GetBlockHeader(ctx, hash)
+}
+
 // getPeerInfo performs the `getpeerinfo` JSON-RPC request
 func (b *Client) getPeerInfo(
 	ctx context.Context,
@@ -358,6 +674,13 @@ func (b *Client) getBlock(
 		return nil, fmt.Errorf("%w: error getting block hash by identifier", err)
 	}
 
+	return b.GetBlock(ctx, hash)
+}
+
+// GetBlock returns the Block with hash, including the
+// full data of each of its transactions. Outputs that
+// carry an asset have ScriptPubKey.Asset populated.
+func (b *Client) GetBlock(ctx context.Context, hash string) (*Block, error) {
 	// Parameters:
 	//   1. Block hash (string, required)
 	//   2. Verbosity (integer, optional, default=1)
@@ -372,6 +695,55 @@ func (b *Client) getBlock(
 	return response.Result, nil
 }
 
+// GetRawTransaction returns the Transaction with txHash.
+// Transactions that are not in the mempool can only be
+// fetched if ravend is run with txindex. Outputs that
+// carry an asset have ScriptPubKey.Asset populated.
+func (b *Client) GetRawTransaction(ctx context.Context, txHash string) (*Transaction, error) {
+	// Parameters:
+	//   1. txid
+	//   2. verbose
+	params := []interface{}{txHash, true}
+
+	response := &rawTransactionResponse{}
+	if err := b.post(ctx, requestMethodGetRawTransaction, params, response); err != nil {
+		return nil, fmt.Errorf("%w: error fetching transaction %s", err, txHash)
+	}
+
+	return response.Result, nil
+}
+
+// GetAssetData returns the *AssetInfo of the asset
+// with name, as currently known to ravend.
+func (b *Client) GetAssetData(ctx context.Context, name string) (*AssetInfo, error) {
+	// Parameters:
+	//   1. asset_name
+	params := []interface{}{name}
+
+	response := &assetDataResponse{}
+	if err := b.post(ctx, requestMethodGetAssetData, params, response); err != nil {
+		return nil, fmt.Errorf("%w: error fetching asset data %s", err, name)
+	}
+
+	data := response.Result
+	if data == nil {
+		return nil, fmt.Errorf("asset %s not found", name)
+	}
+
+	amount, err := b.parseAmount(data.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse amount of asset %s", err, name)
+	}
+
+	return &AssetInfo{
+		Name:       data.Name,
+		Amount:     int64(amount),
+		Units:      data.Units,
+		Reissuable: data.Reissuable == 1,
+		IPFSHash:   data.IPFSHash,
+	}, nil
+}
+
 // getBlockchainInfo performs the `getblockchaininfo` JSON-RPC request
 func (b *Client) getBlockchainInfo(
 	ctx context.Context,
@@ -407,7 +779,7 @@ func (b *Client) getBlockHash(
 		return *identifier.Hash, nil
 	}
 
-	return b.getHashFromIndex(ctx, *identifier.Index)
+	return b.GetHashFromIndex(ctx, *identifier.Index)
 }
 
 // parseBlock returns a *types.Block from a Block
@@ -445,10 +817,10 @@ func (b *Client) parseBlockData(block *Block) (*types.Block, error) {
 	}, nil
 }
 
-// getHashFromIndex performs the `getblockhash` JSON-RPC request for the specified
+// GetHashFromIndex performs the `getblockhash` JSON-RPC request for the specified
 // block index, and returns the hash.
 // https://bitcoin.org/en/developer-reference#getblockhash
-func (b *Client) getHashFromIndex(
+func (b *Client) GetHashFromIndex(
 	ctx context.Context,
 	index int64,
 ) (string, error) {
@@ -512,6 +884,15 @@ func (b *Client) parseTransactions(
 			return nil, fmt.Errorf("%w: unable to get metadata for transaction", err)
 		}
 
+		flows, err := b.assetFlows(txOps)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to compute asset flows", err)
+		}
+
+		if flows != nil {
+			metadata[AssetFlowsMetadataKey] = flows
+		}
+
 		tx := &types.Transaction{
 			TransactionIdentifier: &types.TransactionIdentifier{
 				Hash: transaction.Hash,
@@ -546,6 +927,65 @@ func (b *Client) parseTransactions(
 	return txs, nil
 }
 
+// assetFlows returns the net amount of each currency moved by
+// ops (keyed by currency symbol). The amount moved is the sum of
+// all outputs paid to accounts that did not fund the currency in
+// the transaction (so change is excluded). If ops do not involve
+// any assets, nil is returned.
+func (b *Client) assetFlows(ops []*types.Operation) (map[string]interface{}, error) {
+	hasAsset := false
+	funders := map[string]map[string]struct{}{}
+	totals := map[string]*big.Int{}
+	for _, op := range ops {
+		if op.Amount == nil {
+			continue
+		}
+
+		symbol := op.Amount.Currency.Symbol
+		if symbol != b.currency.Symbol {
+			hasAsset = true
+		}
+
+		if _, ok := totals[symbol]; !ok {
+			funders[symbol] = map[string]struct{}{}
+			totals[symbol] = big.NewInt(0)
+		}
+
+		if op.Type == InputOpType {
+			funders[symbol][op.Account.Address] = struct{}{}
+		}
+	}
+
+	if !hasAsset {
+		return nil, nil
+	}
+
+	for _, op := range ops {
+		if op.Type != OutputOpType {
+			continue
+		}
+
+		symbol := op.Amount.Currency.Symbol
+		if _, ok := funders[symbol][op.Account.Address]; ok {
+			continue
+		}
+
+		amount, err := types.BigInt(op.Amount.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse amount %s", err, op.Amount.Value)
+		}
+
+		totals[symbol].Add(totals[symbol], amount)
+	}
+
+	flows := make(map[string]interface{}, len(totals))
+	for symbol, total := range totals {
+		flows[symbol] = total.String()
+	}
+
+	return flows, nil
+}
+
 // parseTransactions returns the transaction operations for a specified transaction.
 // It uses a map of previous transactions to properly hydrate the input operations.
 func (b *Client) parseTxOperations(
@@ -631,6 +1071,30 @@ func (b *Client) parseOutputTransactionOperation(
 		)
 	}
 
+	// Outputs carrying an asset (including owner tokens) are
+	// tracked in the currency of the asset so that each asset
+	// is reported as its own holding.
+	currency := b.currency
+	if asset := output.ScriptPubKey.Asset; asset != nil {
+		amount, err = b.parseAmount(asset.Amount)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: error parsing asset amount, hash: %s, index: %d",
+				err,
+				txHash,
+				index,
+			)
+		}
+
+		currency = AssetCurrency(asset.Name, MaxAssetUnits)
+	} else if asset, err := ParseAssetScriptHex(output.ScriptPubKey.Hex); err == nil && asset != nil {
+		// ravend does not decode the asset of every script
+		// that carries one, so we fall back to parsing the
+		// OP_RVN_ASSET suffix ourselves.
+		amount = uint64(asset.Amount)
+		currency = AssetCurrency(asset.Name, MaxAssetUnits)
+	}
+
 	metadata, err := output.Metadata()
 	if err != nil {
 		return nil, fmt.Errorf("%w: unable to get output metadata", err)
@@ -670,7 +1134,7 @@ func (b *Client) parseOutputTransactionOperation(
 		Account: account,
 		Amount: &types.Amount{
 			Value:    strconv.FormatInt(int64(amount), 10),
-			Currency: b.currency,
+			Currency: currency,
 		},
 		CoinChange: coinChange,
 		Metadata:   metadata,
@@ -727,7 +1191,7 @@ func (b *Client) parseInputTransactionOperation(
 		Account: accountCoin.Account,
 		Amount: &types.Amount{
 			Value:    newValue,
-			Currency: b.currency,
+			Currency: accountCoin.Coin.Amount.Currency,
 		},
 		CoinChange: &types.CoinChange{
 			CoinIdentifier: &types.CoinIdentifier{
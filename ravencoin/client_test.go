@@ -86,6 +86,7 @@ var (
 						},
 					},
 				},
+				WitnessHash: "fe28050b93faea61fa88c4c630f0e1f0a1c24d0082dd0e10d369e13212128f33",
 			},
 			{
 				Hex:      "01000000081cefd96060ecb1c4fbe675ad8a4f8bdc61d634c52b3a1c4116dee23749fe80ff000000009300493046022100866859c21f306538152e83f115bcfbf59ab4bb34887a88c03483a5dff9895f96022100a6dfd83caa609bf0516debc2bf65c3df91813a4842650a1858b3f61cfa8af249014730440220296d4b818bb037d0f83f9f7111665f49532dfdcbec1e6b784526e9ac4046eaa602204acf3a5cb2695e8404d80bf49ab04828bcbe6fc31d25a2844ced7a8d24afbdff01ffffffff1cefd96060ecb1c4fbe675ad8a4f8bdc61d634c52b3a1c4116dee23749fe80ff020000009400483045022100e87899175991aa008176cb553c6f2badbb5b741f328c9845fcab89f8b18cae2302200acce689896dc82933015e7230e5230d5cff8a1ffe82d334d60162ac2c5b0c9601493046022100994ad29d1e7b03e41731a4316e5f4992f0d9b6e2efc40a1ccd2c949b461175c502210099b69fdc2db00fbba214f16e286f6a49e2d8a0d5ffc6409d87796add475478d601ffffffff1e4a6d2d280ea06680d6cf8788ac90344a9c67cca9b06005bbd6d3f6945c8272010000009500493046022100a27400ba52fd842ce07398a1de102f710a10c5599545e6c95798934352c2e4df022100f6383b0b14c9f64b6718139f55b6b9494374755b86bae7d63f5d3e583b57255a01493046022100fdf543292f34e1eeb1703b264965339ec4a450ec47585009c606b3edbc5b617b022100a5fbb1c8de8aaaa582988cdb23622838e38de90bebcaab3928d949aa502a65d401ffffffff1e4a6d2d280ea06680d6cf8788ac90344a9c67cca9b06005bbd6d3f6945c8272020000009400493046022100ac626ac3051f875145b4fe4cfe089ea895aac73f65ab837b1ac30f5d875874fa022100bc03e79fa4b7eb707fb735b95ff6613ca33adeaf3a0607cdcead4cfd3b51729801483045022100b720b04a5c5e2f61b7df0fcf334ab6fea167b7aaede5695d3f7c6973496adbf1022043328c4cc1cdc3e5db7bb895ccc37133e960b2fd3ece98350f774596badb387201ffffffff23a8733e349c97d6cd90f520fdd084ba15ce0a395aad03cd51370602bb9e5db3010000004a00483045022100e8556b72c5e9c0da7371913a45861a61c5df434dfd962de7b23848e1a28c86ca02205d41ceda00136267281be0974be132ac4cda1459fe2090ce455619d8b91045e901ffffffff6856d609b881e875a5ee141c235e2a82f6b039f2b9babe82333677a5570285a6000000006a473044022040a1c631554b8b210fbdf2a73f191b2851afb51d5171fb53502a3a040a38d2c0022040d11cf6e7b41fe1b66c3d08f6ada1aee07a047cb77f242b8ecc63812c832c9a012102bcfad931b502761e452962a5976c79158a0f6d307ad31b739611dac6a297c256ffffffff6856d609b881e875a5ee141c235e2a82f6b039f2b9babe82333677a5570285a601000000930048304502205b109df098f7e932fbf71a45869c3f80323974a826ee2770789eae178a21bfc8022100c0e75615e53ee4b6e32b9bb5faa36ac539e9c05fa2ae6b6de5d09c08455c8b9601483045022009fb7d27375c47bea23b24818634df6a54ecf72d52e0c1268fb2a2c84f1885de022100e0ed4f15d62e7f537da0d0f1863498f9c7c0c0a4e00e4679588c8d1a9eb20bb801ffffffffa563c3722b7b39481836d5edfc1461f97335d5d1e9a23ade13680d0e2c1c371f030000006c493046022100ecc38ae2b1565643dc3c0dad5e961a5f0ea09cab28d024f92fa05c922924157e022100ebc166edf6fbe4004c72bfe8cf40130263f98ddff728c8e67b113dbd621906a601210211a4ed241174708c07206601b44a4c1c29e5ad8b1f731c50ca7e1d4b2a06dc1fffffffff02d0223a00000000001976a91445db0b779c0b9fa207f12a8218c94fc77aff504588ac80f0fa02000000000000000000", // nolint
@@ -120,6 +121,7 @@ var (
 						},
 					},
 				},
+				WitnessHash: "4852fe372ff7534c16713b3146bbc1e86379c70bea4d5c02fb1fa0112980a081",
 			},
 		},
 	}
@@ -181,6 +183,7 @@ var (
 						},
 					},
 				},
+				WitnessHash: "8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87",
 			},
 			{
 				Hex:      "0100000001032e38e9c0a84c6046d687d10556dcacc41d275ec55fc00779ac88fdf357a187000000008c493046022100c352d3dd993a981beba4a63ad15c209275ca9470abfcd57da93b58e4eb5dce82022100840792bc1f456062819f15d33ee7055cf7b5ee1af1ebcc6028d9cdb1c3af7748014104f46db5e9d61a9dc27b8d64ad23e7383a4e6ca164593c2527c038c0857eb67ee8e825dca65046b82c9331586c82e0fd1f633f25f87c161bc6f8a630121df2b3d3ffffffff0200e32321000000001976a914c398efa9c392ba6013c5e04ee729755ef7f58b3288ac000fe208010000001976a914948c765a6914d43f2a7ac177da2c2f6b52de3d7c88ac00000000", // nolint
@@ -229,6 +232,7 @@ var (
 						},
 					},
 				},
+				WitnessHash: "fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4",
 			},
 			{
 				Hash:     "fake",
@@ -287,6 +291,7 @@ var (
 						},
 					},
 				},
+				WitnessHash: "fake",
 			},
 		},
 	}
@@ -308,7 +313,7 @@ func TestNetworkStatus(t *testing.T) {
 				},
 				{
 					status: http.StatusOK,
-					body:   loadFixture("get_block_response.json"),
+					body:   loadFixture("get_block_header_response.json"),
 					url:    url,
 				},
 				{
@@ -384,7 +389,7 @@ func TestNetworkStatus(t *testing.T) {
 				},
 				{
 					status: http.StatusOK,
-					body:   loadFixture("get_block_response.json"),
+					body:   loadFixture("get_block_header_response.json"),
 					url:    url,
 				},
 				{
@@ -533,6 +538,93 @@ func TestGetPeers(t *testing.T) {
 	}
 }
 
+func TestGetNodeInfo(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedNodeInfo *NodeInfo
+		expectedError    error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_network_info_response.json"),
+					url:    url,
+				},
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_blockchain_info_response.json"),
+					url:    url,
+				},
+			},
+			expectedNodeInfo: &NodeInfo{
+				ProtocolVersion: 70028,
+				SubVersion:      "/Ravencoin:4.3.2.1/",
+				Blocks:          1000,
+				Connections:     8,
+			},
+		},
+		"blockchain warming up error": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("rpc_in_warmup_response.json"),
+					url:    url,
+				},
+			},
+			expectedError: errors.New("rpc in warmup"),
+		},
+		"blockchain info error": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_network_info_response.json"),
+					url:    url,
+				},
+				{
+					status: http.StatusInternalServerError,
+					body:   "{}",
+					url:    url,
+				},
+			},
+			expectedError: errors.New("invalid response: 500 Internal Server Error"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+			)
+
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal("application/json", r.Header.Get("Content-Type"))
+				assert.Equal("POST", r.Method)
+				assert.Equal(response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			nodeInfo, err := client.GetNodeInfo(context.Background())
+			if test.expectedError != nil {
+				assert.Contains(err.Error(), test.expectedError.Error())
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expectedNodeInfo, nodeInfo)
+			}
+		})
+	}
+}
+
 func TestGetRawBlock(t *testing.T) {
 	tests := map[string]struct {
 		blockIdentifier *types.PartialBlockIdentifier
@@ -1273,7 +1365,90 @@ func TestParseBlock(t *testing.T) {
 	}
 }
 
-func TestSuggestedFeeRate(t *testing.T) {
+func TestParseBlock_AssetFlows(t *testing.T) {
+	sender := "RDjqTZN5a7WgWiG6gqsBUcqnSmPVpAqsMF"
+	recipient := "RKXSv1XwbDNSo1nYXXKwbPY1SsdYFjgKfs"
+	txHash := "a6ef59a54c2e7c0eb4fd1f7ee3e6ac51fd2cd47fd0b0e0c7e4ee4b35e1fcd1ee"
+	prevHash := "6fc5d5c14f70c7f5bcb2f0e5f3bb3f5b2f0c8e1f6d9bde4a2c3b1d5e0f8a7c6b"
+	output := func(index int64, address string, value float64, asset *ScriptPubKeyAsset) *Output {
+		scriptType := "pubkeyhash"
+		if asset != nil {
+			scriptType = TransferAssetType
+		}
+
+		return &Output{
+			Value: value,
+			Index: index,
+			ScriptPubKey: &ScriptPubKey{
+				Type:      scriptType,
+				Addresses: []string{address},
+				Asset:     asset,
+			},
+		}
+	}
+	coin := func(vout int64, value string, currency *types.Currency) *types.AccountCoin {
+		return &types.AccountCoin{
+			Account: &types.AccountIdentifier{Address: sender},
+			Coin: &types.Coin{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: CoinIdentifier(prevHash, vout),
+				},
+				Amount: &types.Amount{
+					Value:    value,
+					Currency: currency,
+				},
+			},
+		}
+	}
+
+	block := &Block{
+		Hash:              "0000000000000000000000000000000000000000000000000000000000001000",
+		Height:            1000,
+		PreviousBlockHash: "0000000000000000000000000000000000000000000000000000000000000999",
+		Txs: []*Transaction{
+			{
+				Hash: txHash,
+				Inputs: []*Input{
+					{TxHash: prevHash, Vout: 0},
+					{TxHash: prevHash, Vout: 1},
+					{TxHash: prevHash, Vout: 2},
+				},
+				Outputs: []*Output{
+					output(0, recipient, 0, &ScriptPubKeyAsset{Name: "ASSETA", Amount: 30}),
+					output(1, sender, 0, &ScriptPubKeyAsset{Name: "ASSETA", Amount: 70}),
+					output(2, recipient, 0, &ScriptPubKeyAsset{Name: "ASSETB", Amount: 5}),
+					output(3, sender, 0.5, nil),
+				},
+			},
+		},
+	}
+	coins := map[string]*types.AccountCoin{
+		CoinIdentifier(prevHash, 0): coin(0, "10000000000", AssetCurrency("ASSETA", MaxAssetUnits)),
+		CoinIdentifier(prevHash, 1): coin(1, "500000000", AssetCurrency("ASSETB", MaxAssetUnits)),
+		CoinIdentifier(prevHash, 2): coin(2, "100000000", MainnetCurrency),
+	}
+
+	client := NewClient("", MainnetGenesisBlockIdentifier, MainnetCurrency)
+	parsed, err := client.ParseBlock(context.Background(), block, coins)
+	assert.NoError(t, err)
+
+	tx := parsed.Transactions[0]
+	assert.Equal(t, map[string]interface{}{
+		"ASSETA": "3000000000",
+		"ASSETB": "500000000",
+		"RVN":    "0",
+	}, tx.Metadata[AssetFlowsMetadataKey])
+
+	// Inputs are reported in the currency of the spent coin
+	// and asset outputs in the currency of the asset.
+	assert.Equal(t, AssetCurrency("ASSETB", MaxAssetUnits), tx.Operations[1].Amount.Currency)
+	assert.Equal(t, &types.Amount{
+		Value:    "3000000000",
+		Currency: AssetCurrency("ASSETA", MaxAssetUnits),
+	}, tx.Operations[3].Amount)
+}
+
+func TestSuggestedFeeRatePerKB(t *testing.T) {
 	tests := map[string]struct {
 		responses []responseFixture
 
@@ -1334,7 +1509,7 @@ func TestSuggestedFeeRate(t *testing.T) {
 			}))
 
 			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
-			rate, err := client.SuggestedFeeRate(context.Background(), 1)
+			rate, err := client.SuggestedFeeRatePerKB(context.Background(), 1)
 			if test.expectedError != nil {
 				assert.Contains(err.Error(), test.expectedError.Error())
 			} else {
@@ -1345,7 +1520,102 @@ func TestSuggestedFeeRate(t *testing.T) {
 	}
 }
 
-func TestRawMempool(t *testing.T) {
+func TestSuggestedFeeRatePerByte(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, url, r.URL.RequestURI())
+
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintln(w, loadFixture("fee_rate.json"))
+	}))
+	defer ts.Close()
+
+	// 0.00001 RVN per kB is 1 Satoshi per vbyte.
+	client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+	rate, err := client.SuggestedFeeRatePerByte(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.InDelta(t, float64(1), rate, 1e-9)
+}
+
+func TestSendRawTransaction(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedHash  string
+		expectedError error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   `{"result":"6d87ad0e26025128f5a8357fa423b340cbcffb9703f79f432f5520fca59cd20b","error":null,"id":1}`, // nolint
+					url:    url,
+				},
+			},
+			expectedHash: "6d87ad0e26025128f5a8357fa423b340cbcffb9703f79f432f5520fca59cd20b",
+		},
+		"already in mempool": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   `{"result":null,"error":{"code":-26,"message":"txn-already-in-mempool"},"id":1}`,
+					url:    url,
+				},
+			},
+			expectedError: ErrTransactionAlreadyKnown,
+		},
+		"already in chain": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   `{"result":null,"error":{"code":-27,"message":"transaction already in block chain"},"id":1}`,
+					url:    url,
+				},
+			},
+			expectedError: ErrTransactionAlreadyKnown,
+		},
+		"rejected": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   `{"result":null,"error":{"code":-26,"message":"min relay fee not met"},"id":1}`,
+					url:    url,
+				},
+			},
+			expectedError: ErrJSONRPCError,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			hash, err := client.SendRawTransaction(context.Background(), "deadbeef")
+			if test.expectedError != nil {
+				assert.True(t, errors.Is(err, test.expectedError))
+				assert.Empty(t, hash)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, test.expectedHash, hash)
+			}
+		})
+	}
+}
+
+func TestGetRawMempool(t *testing.T) {
 	tests := map[string]struct {
 		responses []responseFixture
 
@@ -1400,7 +1670,7 @@ func TestRawMempool(t *testing.T) {
 			}))
 
 			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
-			txs, err := client.RawMempool(context.Background())
+			txs, err := client.GetRawMempool(context.Background())
 			if test.expectedError != nil {
 				assert.Contains(err.Error(), test.expectedError.Error())
 			} else {
@@ -1411,6 +1681,83 @@ func TestRawMempool(t *testing.T) {
 	}
 }
 
+func TestGetMempoolEntry(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedEntry *MempoolEntry
+		expectedError error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("mempool_entry.json"),
+					url:    url,
+				},
+			},
+			expectedEntry: &MempoolEntry{
+				Size:            225,
+				Fee:             0.0000226,
+				Time:            1604423617,
+				Height:          1456223,
+				AncestorCount:   2,
+				AncestorSize:    450,
+				DescendantCount: 1,
+				DescendantSize:  225,
+				Depends: []string{
+					"37b4fcc8e0b229412faeab8baad45d3eb8e4eec41840d6ac2103987163459e75",
+				},
+			},
+		},
+		"not in mempool": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("mempool_entry_not_found.json"),
+					url:    url,
+				},
+			},
+			expectedError: ErrTransactionNotInMempool,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+			)
+
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal("application/json", r.Header.Get("Content-Type"))
+				assert.Equal("POST", r.Method)
+				assert.Equal(response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			entry, err := client.GetMempoolEntry(
+				context.Background(),
+				"9cec12d170e97e21a876fa2789e6bfc25aa22b8a5e05f3f276650844da0c33ab",
+			)
+			if test.expectedError != nil {
+				assert.True(errors.Is(err, test.expectedError))
+			} else {
+				assert.NoError(err)
+				assert.Equal(test.expectedEntry, entry)
+			}
+		})
+	}
+}
+
 // loadFixture takes a file name and returns the response fixture.
 func loadFixture(fileName string) string {
 	content, err := ioutil.ReadFile(fmt.Sprintf("client_fixtures/%s", fileName))
@@ -1425,3 +1772,482 @@ type responseFixture struct {
 	body   string
 	url    string
 }
+
+func TestGetBlock(t *testing.T) {
+	responses := make(chan responseFixture, 1)
+	responses <- responseFixture{
+		status: http.StatusOK,
+		body:   loadFixture("get_block_transfer_response.json"),
+		url:    url,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := <-responses
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, response.url, r.URL.RequestURI())
+
+		w.WriteHeader(response.status)
+		fmt.Fprintln(w, response.body)
+	}))
+
+	client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+	block, err := client.GetBlock(
+		context.Background(),
+		"000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506", block.Hash)
+	assert.Equal(t, int64(100000), block.Height)
+	assert.Len(t, block.Txs, 2)
+
+	// The first transaction is the coinbase.
+	coinbase := block.Txs[0]
+	assert.Equal(t, "8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87", coinbase.Hash)
+	assert.Len(t, coinbase.Inputs, 1)
+	assert.Equal(t, "044c86041b020602", coinbase.Inputs[0].Coinbase)
+
+	// The second transaction is a standard transfer.
+	transfer := block.Txs[1]
+	assert.Equal(t, "fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4", transfer.Hash)
+	assert.Len(t, transfer.Inputs, 1)
+	assert.Empty(t, transfer.Inputs[0].Coinbase)
+	assert.Equal(t, "87a157f3fd88ac7907c05fc55e271dc4acdc5605d187d646604ca8c0e9382e03", transfer.Inputs[0].TxHash)
+	assert.Equal(t, []*Output{
+		{
+			Value: 5.56,
+			Index: 0,
+			ScriptPubKey: &ScriptPubKey{
+				ASM:          "OP_DUP OP_HASH160 c398efa9c392ba6013c5e04ee729755ef7f58b32 OP_EQUALVERIFY OP_CHECKSIG",
+				Hex:          "76a914c398efa9c392ba6013c5e04ee729755ef7f58b3288ac",
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses:    []string{"1JqDybm2nWTENrHvMyafbSXXtTk5Uv5QAn"},
+			},
+		},
+		{
+			Value: 44.44,
+			Index: 1,
+			ScriptPubKey: &ScriptPubKey{
+				ASM:          "OP_DUP OP_HASH160 948c765a6914d43f2a7ac177da2c2f6b52de3d7c OP_EQUALVERIFY OP_CHECKSIG",
+				Hex:          "76a914948c765a6914d43f2a7ac177da2c2f6b52de3d7c88ac",
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses:    []string{"1EYTGtG4LnFfiMvjJdsU7GMGCQvsRSjYhx"},
+			},
+		},
+	}, transfer.Outputs)
+}
+
+func TestGetBlockHeader(t *testing.T) {
+	header1000 := &BlockHeader{
+		Hash:              block1000.Hash,
+		Height:            block1000.Height,
+		PreviousBlockHash: block1000.PreviousBlockHash,
+		Time:              block1000.Time,
+		Nonce:             2595206198,
+		MerkleRoot:        block1000.MerkleRoot,
+		Version:           1,
+		Bits:              "1d00ffff",
+	}
+
+	tests := map[string]struct {
+		height    *int64
+		responses []responseFixture
+
+		expectedHeader *BlockHeader
+		expectedError  error
+	}{
+		"by hash": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_header_response.json"),
+					url:    url,
+				},
+			},
+			expectedHeader: header1000,
+		},
+		"by height": {
+			height: types.Int64(1000),
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_hash_response.json"),
+					url:    url,
+				},
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_header_response.json"),
+					url:    url,
+				},
+			},
+			expectedHeader: header1000,
+		},
+		"kawpow": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_header_kawpow_response.json"),
+					url:    url,
+				},
+			},
+			expectedHeader: &BlockHeader{
+				Hash:              "000000008e8f8bcc15c6ae5ac050801cd6dcfd428fb5f9e65c4e16e7807340fa",
+				Height:            1219736,
+				PreviousBlockHash: "00000000333ad79154348296204fa7f8c537a96e08983e5f73b3f5aca8e8edf7",
+				Time:              1588788000,
+				Nonce:             11529215046068588394,
+				MixHash:           "2f907a6de331cc77376c52e70ba55765a30be18cd9bc69587585fbb71b80de1d",
+				MerkleRoot:        "7975edd9e7393c229e744913fe0d0bb86fb4cf46906e2e51152137e20ad15590",
+				Version:           805306368,
+				Bits:              "1b01a4f3",
+			},
+		},
+		"not found": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_not_found_response.json"),
+					url:    url,
+				},
+			},
+			expectedError: errors.New("error fetching block header"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			var (
+				header *BlockHeader
+				err    error
+			)
+			if test.height != nil {
+				header, err = client.GetBlockHeaderByHeight(context.Background(), *test.height)
+			} else {
+				header, err = client.GetBlockHeader(context.Background(), block1000.Hash)
+			}
+			if test.expectedError != nil {
+				assert.Nil(t, header)
+				assert.Contains(t, err.Error(), test.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedHeader, header)
+		})
+	}
+}
+
+func TestGetBlockHeight(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedHeight int64
+		expectedError  error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_header_response.json"),
+					url:    url,
+				},
+			},
+			expectedHeight: block1000.Height,
+		},
+		"not found": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_block_not_found_response.json"),
+					url:    url,
+				},
+			},
+			expectedError: errors.New("error fetching block header"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			height, err := client.GetBlockHeight(context.Background(), block1000.Hash)
+			if test.expectedError != nil {
+				assert.Contains(t, err.Error(), test.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedHeight, height)
+		})
+	}
+}
+
+func TestGetBestBlockHash(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedHash  string
+		expectedError error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_best_block_hash_response.json"),
+					url:    url,
+				},
+			},
+			expectedHash: block1000.Hash,
+		},
+		"blockchain warming up error": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("rpc_in_warmup_response.json"),
+					url:    url,
+				},
+			},
+			expectedError: errors.New("error fetching best block hash"),
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.Equal(t, "POST", r.Method)
+				assert.Equal(t, response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			hash, err := client.GetBestBlockHash(context.Background())
+			if test.expectedError != nil {
+				assert.Contains(t, err.Error(), test.expectedError.Error())
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.expectedHash, hash)
+		})
+	}
+}
+
+func TestGetRawTransaction(t *testing.T) {
+	responses := make(chan responseFixture, 1)
+	responses <- responseFixture{
+		status: http.StatusOK,
+		body:   loadFixture("get_raw_transaction_asset_response.json"),
+		url:    url,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := <-responses
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, response.url, r.URL.RequestURI())
+
+		w.WriteHeader(response.status)
+		fmt.Fprintln(w, response.body)
+	}))
+
+	client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+	tx, err := client.GetRawTransaction(
+		context.Background(),
+		"b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f",
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506", tx.BlockHash)
+	assert.Len(t, tx.Outputs, 2)
+	assert.Nil(t, tx.Outputs[0].ScriptPubKey.Asset)
+
+	// The asset transfer output carries the asset.
+	assert.Equal(t, TransferAssetType, tx.Outputs[1].ScriptPubKey.Type)
+	assert.Equal(t, &ScriptPubKeyAsset{
+		Name:   "ROSETTA",
+		Amount: 1000,
+	}, tx.Outputs[1].ScriptPubKey.Asset)
+
+	asset, err := ParseAssetScriptHex(tx.Outputs[1].ScriptPubKey.Hex)
+	assert.NoError(t, err)
+	assert.Equal(t, "ROSETTA", asset.Name)
+	assert.Equal(t, int64(100000000000), asset.Amount)
+}
+
+func TestGetMempoolTransaction(t *testing.T) {
+	tests := map[string]struct {
+		responses []responseFixture
+
+		expectedError error
+	}{
+		"successful": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("mempool_entry.json"),
+					url:    url,
+				},
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_raw_transaction_mempool_response.json"),
+					url:    url,
+				},
+				{
+					status: http.StatusOK,
+					body:   loadFixture("get_raw_transaction_asset_response.json"),
+					url:    url,
+				},
+			},
+		},
+		"not in mempool": {
+			responses: []responseFixture{
+				{
+					status: http.StatusOK,
+					body:   loadFixture("mempool_entry_not_found.json"),
+					url:    url,
+				},
+			},
+			expectedError: ErrTransactionNotInMempool,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var (
+				assert = assert.New(t)
+			)
+
+			responses := make(chan responseFixture, len(test.responses))
+			for _, response := range test.responses {
+				responses <- response
+			}
+
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				response := <-responses
+				assert.Equal("application/json", r.Header.Get("Content-Type"))
+				assert.Equal("POST", r.Method)
+				assert.Equal(response.url, r.URL.RequestURI())
+
+				w.WriteHeader(response.status)
+				fmt.Fprintln(w, response.body)
+			}))
+
+			client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+			tx, err := client.GetMempoolTransaction(
+				context.Background(),
+				"5c1b7e0b3a9c2f4d6e8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e",
+			)
+			if test.expectedError != nil {
+				assert.Nil(tx)
+				assert.True(errors.Is(err, test.expectedError))
+				return
+			}
+
+			assert.NoError(err)
+			assert.Equal(
+				"5c1b7e0b3a9c2f4d6e8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e",
+				tx.TransactionIdentifier.Hash,
+			)
+			assert.Len(tx.Operations, 3)
+
+			// The input is hydrated from the output it spends.
+			input := tx.Operations[0]
+			assert.Equal(InputOpType, input.Type)
+			assert.Equal("RSnWdXtbdBKLr1HreRwoNfnu2qJ2bUdz1z", input.Account.Address)
+			assert.Equal(&types.Amount{Value: "-99000000", Currency: MainnetCurrency}, input.Amount)
+			assert.Equal(&types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:0",
+				},
+				CoinAction: types.CoinSpent,
+			}, input.CoinChange)
+
+			for i, output := range []struct {
+				address string
+				value   string
+			}{
+				{address: "REfmFw8Db3bPsDGzdU4AB6wPf4dKfuKcwJ", value: "50000000"},
+				{address: "RSnWdXtbdBKLr1HreRwoNfnu2qJ2bUdz1z", value: "48990000"},
+			} {
+				op := tx.Operations[i+1]
+				assert.Equal(OutputOpType, op.Type)
+				assert.Equal(output.address, op.Account.Address)
+				assert.Equal(&types.Amount{Value: output.value, Currency: MainnetCurrency}, op.Amount)
+				assert.Equal(
+					fmt.Sprintf("5c1b7e0b3a9c2f4d6e8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e:%d", i),
+					op.CoinChange.CoinIdentifier.Identifier,
+				)
+			}
+		})
+	}
+}
+
+func TestGetAssetData(t *testing.T) {
+	responses := make(chan responseFixture, 1)
+	responses <- responseFixture{
+		status: http.StatusOK,
+		body:   loadFixture("get_asset_data_response.json"),
+		url:    url,
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		response := <-responses
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, response.url, r.URL.RequestURI())
+
+		w.WriteHeader(response.status)
+		fmt.Fprintln(w, response.body)
+	}))
+
+	client := NewClient(ts.URL, MainnetGenesisBlockIdentifier, MainnetCurrency)
+	info, err := client.GetAssetData(context.Background(), "ROSETTA")
+	assert.NoError(t, err)
+	assert.Equal(t, &AssetInfo{
+		Name:       "ROSETTA",
+		Amount:     100000000000,
+		Units:      0,
+		Reissuable: true,
+		IPFSHash:   "QmTqu3Lk3gmTsQVtjU7rYYM37EAW4xNmbuEAp2Mjr4AV7E",
+	}, info)
+}
@@ -0,0 +1,209 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+)
+
+var (
+	// ErrMerkleRootMismatch is returned when the merkle root
+	// computed from a block's transactions does not match
+	// the merkle root in its header.
+	ErrMerkleRootMismatch = errors.New("merkle root mismatch")
+
+	// ErrWitnessCommitmentMismatch is returned when the witness
+	// commitment in a block's coinbase does not match the
+	// witness merkle root computed from its transactions.
+	ErrWitnessCommitmentMismatch = errors.New("witness commitment mismatch")
+
+	// witnessCommitmentHeader prefixes the coinbase output
+	// script committing to the witness merkle root (BIP141).
+	witnessCommitmentHeader = []byte{0x6a, 0x24, 0xaa, 0x21, 0xa9, 0xed}
+)
+
+// ComputeMerkleRoot returns the merkle root of txids. When a
+// level of the tree has an odd number of hashes, the last
+// hash is paired with itself.
+func ComputeMerkleRoot(txids []chainhash.Hash) chainhash.Hash {
+	if len(txids) == 0 {
+		return chainhash.Hash{}
+	}
+
+	level := make([]chainhash.Hash, len(txids))
+	copy(level, txids)
+
+	var pair [chainhash.HashSize * 2]byte
+	for len(level) > 1 {
+		if len(level)%2 == 1 {
+			level = append(level, level[len(level)-1])
+		}
+
+		next := make([]chainhash.Hash, len(level)/2)
+		for i := range next {
+			copy(pair[:chainhash.HashSize], level[2*i][:])
+			copy(pair[chainhash.HashSize:], level[2*i+1][:])
+			next[i] = chainhash.DoubleHashH(pair[:])
+		}
+
+		level = next
+	}
+
+	return level[0]
+}
+
+// VerifyMerkleRoot ensures the merkle root in the header of
+// block matches the merkle root computed from its transactions.
+// Blocks fetched without transactions are not verified.
+func VerifyMerkleRoot(block *Block) error {
+	if len(block.Txs) == 0 {
+		return nil
+	}
+
+	txids := make([]chainhash.Hash, len(block.Txs))
+	for i, tx := range block.Txs {
+		hash, err := chainhash.NewHashFromStr(tx.Hash)
+		if err != nil {
+			return fmt.Errorf("%w: unable to parse txid %s", err, tx.Hash)
+		}
+
+		txids[i] = *hash
+	}
+
+	computed := ComputeMerkleRoot(txids)
+	if computed.String() != block.MerkleRoot {
+		return fmt.Errorf(
+			"%w: block %s has merkle root %s but transactions hash to %s",
+			ErrMerkleRootMismatch,
+			block.Hash,
+			block.MerkleRoot,
+			computed.String(),
+		)
+	}
+
+	return nil
+}
+
+// ComputeWitnessMerkleRoot returns the witness merkle root of
+// wtxids. The wtxid of the coinbase (the first wtxid) is
+// always considered to be all zeros.
+func ComputeWitnessMerkleRoot(wtxids []chainhash.Hash) chainhash.Hash {
+	if len(wtxids) == 0 {
+		return chainhash.Hash{}
+	}
+
+	hashes := make([]chainhash.Hash, len(wtxids))
+	copy(hashes[1:], wtxids[1:])
+
+	return ComputeMerkleRoot(hashes)
+}
+
+// witnessCommitment returns the witness commitment in the
+// coinbase of block. If there are multiple commitments, the
+// last is used. If there is no commitment, nil is returned.
+func witnessCommitment(coinbase *Transaction) ([]byte, error) {
+	var commitment []byte
+	for _, output := range coinbase.Outputs {
+		if output.ScriptPubKey == nil {
+			continue
+		}
+
+		script, err := hex.DecodeString(output.ScriptPubKey.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to decode script %s", err, output.ScriptPubKey.Hex)
+		}
+
+		if len(script) >= len(witnessCommitmentHeader)+chainhash.HashSize &&
+			bytes.HasPrefix(script, witnessCommitmentHeader) {
+			commitment = script[len(witnessCommitmentHeader) : len(witnessCommitmentHeader)+chainhash.HashSize]
+		}
+	}
+
+	return commitment, nil
+}
+
+// VerifyWitnessCommitment ensures the witness commitment in the
+// coinbase of block matches the witness merkle root computed from
+// its transactions. Blocks without a witness commitment (or fetched
+// without transactions) are not verified.
+func VerifyWitnessCommitment(block *Block) error {
+	if len(block.Txs) == 0 {
+		return nil
+	}
+
+	coinbase := block.Txs[0]
+	commitment, err := witnessCommitment(coinbase)
+	if err != nil {
+		return fmt.Errorf("%w: unable to find witness commitment", err)
+	}
+
+	if commitment == nil {
+		return nil
+	}
+
+	// The witness reserved value is the only item in
+	// the coinbase witness.
+	if len(coinbase.Inputs) == 0 || len(coinbase.Inputs[0].TxInWitness) != 1 {
+		return fmt.Errorf(
+			"%w: block %s coinbase has no witness reserved value",
+			ErrWitnessCommitmentMismatch,
+			block.Hash,
+		)
+	}
+
+	reservedValue, err := hex.DecodeString(coinbase.Inputs[0].TxInWitness[0])
+	if err != nil || len(reservedValue) != chainhash.HashSize {
+		return fmt.Errorf(
+			"%w: block %s coinbase has invalid witness reserved value %s",
+			ErrWitnessCommitmentMismatch,
+			block.Hash,
+			coinbase.Inputs[0].TxInWitness[0],
+		)
+	}
+
+	wtxids := make([]chainhash.Hash, len(block.Txs))
+	for i, tx := range block.Txs {
+		wtxid := tx.WitnessHash
+		if len(wtxid) == 0 {
+			wtxid = tx.Hash
+		}
+
+		hash, err := chainhash.NewHashFromStr(wtxid)
+		if err != nil {
+			return fmt.Errorf("%w: unable to parse wtxid %s", err, wtxid)
+		}
+
+		wtxids[i] = *hash
+	}
+
+	root := ComputeWitnessMerkleRoot(wtxids)
+	computed := chainhash.DoubleHashB(append(root[:], reservedValue...))
+	if !bytes.Equal(computed, commitment) {
+		return fmt.Errorf(
+			"%w: block %s commits to %x but transactions hash to %x",
+			ErrWitnessCommitmentMismatch,
+			block.Hash,
+			commitment,
+			computed,
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyMerkleRoot(t *testing.T) {
+	tests := map[string]struct {
+		block *Block
+
+		err error
+	}{
+		"single transaction": {
+			block: &Block{
+				Hash:       "00000000839a8e6886ab5951d76f411475428afc90947ee320161bbf18eb6048",
+				MerkleRoot: "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098",
+				Txs: []*Transaction{
+					{Hash: "0e3e2357e806b6cdb1f70b54c3a3a17b6714ee1f0e68bebb44a74b1efd512098"},
+				},
+			},
+		},
+		"even transactions": {
+			block: &Block{
+				Hash:       "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506",
+				MerkleRoot: "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+				Txs: []*Transaction{
+					{Hash: "8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87"},
+					{Hash: "fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4"},
+					{Hash: "6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4"},
+					{Hash: "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d"},
+				},
+			},
+		},
+		"odd transactions": {
+			block: &Block{
+				MerkleRoot: "fa435470825de273081dcc706b25514c936fa6dc80ab965ce6970d68ddd0b553",
+				Txs: []*Transaction{
+					{Hash: "8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87"},
+					{Hash: "fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4"},
+					{Hash: "6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4"},
+				},
+			},
+		},
+		"tampered transaction": {
+			block: &Block{
+				Hash:       "000000000003ba27aa200b1cecaad478d2b00432346c3f1f3986da1afd33e506",
+				MerkleRoot: "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+				Txs: []*Transaction{
+					{Hash: "8c14f0db3df150123e6f3dbbf30f8b955a8249b62ac1d1ff16284aefa3d06d87"},
+					{Hash: "fff2525b8931402dd09222c50775608f75787bd2b87e56995a7bdd30f79702c4"},
+					{Hash: "6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec5"},
+					{Hash: "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d"},
+				},
+			},
+			err: ErrMerkleRootMismatch,
+		},
+		"no transactions": {
+			block: &Block{
+				MerkleRoot: "f3e94742aca4b5ef85488dc37c06c3282295ffec960994b2c0d5ac2a25a95766",
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyMerkleRoot(test.block)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestComputeMerkleRoot(t *testing.T) {
+	assert.Equal(t, chainhash.Hash{}, ComputeMerkleRoot(nil))
+
+	txids := []chainhash.Hash{{0x01}, {0x02}, {0x03}}
+	root := ComputeMerkleRoot(txids)
+	assert.Equal(t, []chainhash.Hash{{0x01}, {0x02}, {0x03}}, txids)
+	assert.NotEqual(t, chainhash.Hash{}, root)
+}
+
+func TestVerifyWitnessCommitment(t *testing.T) {
+	coinbase := func(witness []string, commitment string) *Transaction {
+		return &Transaction{
+			Hash:        "b1fea52486ce0c62bb442b530a3f0132b826c74e473d1f2c220bfa78111c5082",
+			WitnessHash: "0ad2ab6c3a4d2ecf6e8fbc5a5ec2ef6acd1a64dd6cf36d47a08e3cde11bc4d08",
+			Inputs: []*Input{
+				{
+					Coinbase:    "0320a107",
+					TxInWitness: witness,
+				},
+			},
+			Outputs: []*Output{
+				{
+					Value: 5000,
+					ScriptPubKey: &ScriptPubKey{
+						Hex: "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+					},
+				},
+				{
+					ScriptPubKey: &ScriptPubKey{
+						Hex: commitment,
+					},
+				},
+			},
+		}
+	}
+	reservedValue := []string{"0000000000000000000000000000000000000000000000000000000000000000"}
+	commitment := "6a24aa21a9eda21e9471a14a0789c83693e4ef6eebf2e513c73302d6f80803f2f5d9f8c6a59e"
+	txs := []*Transaction{
+		{
+			Hash:        "f4184fc596403b9d638783cf57adfe4c75c605f6356fbc91338530e9831e9e16",
+			WitnessHash: "6359f0868171b1d194cbee1af2f16ea598ae8fad666d9b012c8ed2b79a236ec4",
+		},
+		{
+			Hash:        "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+			WitnessHash: "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+		},
+	}
+
+	tests := map[string]struct {
+		block *Block
+
+		err error
+	}{
+		"segwit block": {
+			block: &Block{
+				Txs: append([]*Transaction{coinbase(reservedValue, commitment)}, txs...),
+			},
+		},
+		"no commitment": {
+			block: &Block{
+				Txs: append([]*Transaction{
+					coinbase(nil, "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac"),
+				}, txs...),
+			},
+		},
+		"missing reserved value": {
+			block: &Block{
+				Txs: append([]*Transaction{coinbase(nil, commitment)}, txs...),
+			},
+			err: ErrWitnessCommitmentMismatch,
+		},
+		"tampered witness": {
+			block: &Block{
+				Txs: append([]*Transaction{coinbase(reservedValue, commitment)}, txs[0], &Transaction{
+					Hash:        "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1d",
+					WitnessHash: "e9a66845e05d5abc0ad04ec80f774a7e585c6e8db975962d069a522137b80c1e",
+				}),
+			},
+			err: ErrWitnessCommitmentMismatch,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := VerifyWitnessCommitment(test.block)
+			if test.err != nil {
+				assert.True(t, errors.Is(err, test.err))
+				return
+			}
+
+			assert.NoError(t, err)
+		})
+	}
+}
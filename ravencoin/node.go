@@ -62,12 +62,25 @@ func logPipe(ctx context.Context, pipe io.ReadCloser, identifier string) error {
 }
 
 // StartRavend starts a ravend daemon in another goroutine
-// and logs the results to the console.
-func StartRavend(ctx context.Context, configPath string, g *errgroup.Group) error {
+// and logs the results to the console. If txIndex is true,
+// ravend maintains a transaction index (overriding the
+// configuration file), which cannot be combined with
+// pruning.
+func StartRavend(
+	ctx context.Context,
+	configPath string,
+	txIndex bool,
+	g *errgroup.Group,
+) error {
 	logger := utils.ExtractLogger(ctx, "ravend")
+	args := []string{fmt.Sprintf("--conf=%s", configPath)}
+	if txIndex {
+		args = append(args, "--txindex=1", "--prune=0")
+	}
+
 	cmd := exec.Command(
 		"/app/ravend",
-		fmt.Sprintf("--conf=%s", configPath),
+		args...,
 	) // #nosec G204
 
 	stdout, err := cmd.StdoutPipe()
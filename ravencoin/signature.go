@@ -0,0 +1,45 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"fmt"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// CalcSignatureHash returns the legacy (non-witness) signature
+// hash of input idx of tx, which spends an output locked by
+// subScript. This is the signing payload of P2PKH inputs. Scripts
+// carrying an asset must be provided in full (including the
+// asset data following OpRvnAsset).
+func CalcSignatureHash(
+	tx *wire.MsgTx,
+	idx int,
+	subScript []byte,
+	hashType txscript.SigHashType,
+) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, fmt.Errorf("input %d is out of range for %d inputs", idx, len(tx.TxIn))
+	}
+
+	hash, err := txscript.CalcSignatureHash(subScript, hashType, tx, idx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to calculate signature hash of input %d", err, idx)
+	}
+
+	return hash, nil
+}
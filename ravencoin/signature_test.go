@@ -0,0 +1,74 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalcSignatureHash(t *testing.T) {
+	// A legacy input spending
+	// b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1
+	// and paying 50 tRVN to tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7.
+	unsigned, _ := hex.DecodeString(
+		"01000000017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305c5a55741b10100000000ffffffff0100f2052a0100000016001488ce6925f8513a234c05c922ee933f221323052000000000", // nolint
+	)
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(unsigned)))
+
+	subScript, _ := hex.DecodeString("76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac")
+
+	tests := map[string]struct {
+		idx      int
+		hashType txscript.SigHashType
+
+		hash string
+		err  bool
+	}{
+		"sighash all": {
+			hashType: txscript.SigHashAll,
+			hash:     "5a3405ff79fb1386d48d0bbc81fcd8eaf1c4b056261553e0634397f87f0c37f5",
+		},
+		"sighash all anyone can pay": {
+			hashType: txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+			hash:     "2f812219c6cd7671e0b83d6e2704a193e73c4b221c9d96233d5ea88e7f6bc6a1",
+		},
+		"input out of range": {
+			idx:      1,
+			hashType: txscript.SigHashAll,
+			err:      true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			hash, err := CalcSignatureHash(&tx, test.idx, subScript, test.hashType)
+			if test.err {
+				assert.Nil(t, hash)
+				assert.Error(t, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, test.hash, hex.EncodeToString(hash))
+		})
+	}
+}
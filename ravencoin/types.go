@@ -16,10 +16,13 @@ package ravencoin
 
 import (
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
 
+	"github.com/btcsuite/btcd/wire"
 	"github.com/coinbase/rosetta-sdk-go/types"
 )
 
@@ -55,6 +58,21 @@ const (
 	// Coinbase.
 	CoinbaseOpType = "COINBASE"
 
+	// IssueAssetOpType is used to describe
+	// the issuance of a new root asset in
+	// construction.
+	IssueAssetOpType = "ISSUE_ASSET"
+
+	// ReissueAssetOpType is used to describe
+	// the reissuance of an existing asset in
+	// construction.
+	ReissueAssetOpType = "REISSUE_ASSET"
+
+	// BurnAssetOpType is used to describe
+	// the burn of all of an asset held by a
+	// coin in construction.
+	BurnAssetOpType = "BURN_ASSET"
+
 	// SuccessStatus is the status of all
 	// Ravencoin operations because anything
 	// on-chain is considered successful.
@@ -74,18 +92,85 @@ const (
 	// as the ScriptPubKey.Type for OP_RETURN
 	// locking scripts.
 	NullData = "nulldata"
+
+	// NewAssetType is returned by ravend as the
+	// ScriptPubKey.Type for asset issuance outputs
+	// (including owner tokens).
+	NewAssetType = "new_asset"
+
+	// TransferAssetType is returned by ravend as
+	// the ScriptPubKey.Type for asset transfer outputs.
+	TransferAssetType = "transfer_asset"
+
+	// ReissueAssetType is returned by ravend as the
+	// ScriptPubKey.Type for asset reissuance outputs.
+	ReissueAssetType = "reissue_asset"
+
+	// AssetFlowsMetadataKey is the key in transaction
+	// metadata of the net amount of each currency moved
+	// by a transaction involving assets.
+	AssetFlowsMetadataKey = "asset_flows"
+
+	// OwnerTokenSuffix is appended to the name of an
+	// asset to form the name of its owner token.
+	OwnerTokenSuffix = "!"
+
+	// KawpowProtocolVersion is the protocol version
+	// of ravend that introduced KAWPOW. Nodes with an
+	// older protocol version cannot follow the chain.
+	KawpowProtocolVersion = 70027
 )
 
 // Fee estimate constants
 // Source: https://bitcoinops.org/en/tools/calc-size/
 const (
-	MinFeeRate            = float64(0.00001) // nolint:gomnd
-	TransactionOverhead   = 12               // 4 version, 2 segwit flag, 1 vin, 1 vout, 4 lock time
-	InputSize             = 68               // 4 prev index, 32 prev hash, 4 sequence, 1 script size, ~27 script witness
-	OutputOverhead        = 9                // 8 value, 1 script size
-	P2PKHScriptPubkeySize = 25               // P2PKH size
+	MinFeeRate             = float64(0.00001) // nolint:gomnd
+	TransactionOverhead    = 12               // 4 version, 2 segwit flag, 1 vin, 1 vout, 4 lock time
+	InputSize              = 68               // 4 prev index, 32 prev hash, 4 sequence, 1 script size, ~27 script witness
+	LegacyInputSize        = 148              // 4 prev index, 32 prev hash, 4 sequence, 1 script size, ~107 script sig
+	NestedInputSize        = 91               // 4 prev index, 32 prev hash, 4 sequence, 1 script size, 23 script sig, ~27 script witness
+	OutputOverhead         = 9                // 8 value, 1 script size
+	P2PKHScriptPubkeySize  = 25               // P2PKH size
+	P2WPKHScriptPubkeySize = 22               // P2WPKH size
+
+	// DustRelayFeeRate is the fee rate (in RVN per kB) used to
+	// determine whether an output is dust. It is independent of
+	// MinFeeRate so that changing the minimum fee does not
+	// change which outputs are standard.
+	DustRelayFeeRate = float64(0.00003) // nolint:gomnd
+
+	// witnessScaleFactor is the weight of a byte
+	// of non-witness data (BIP141).
+	witnessScaleFactor = 4
 )
 
+// DustThreshold returns the smallest value (in Satoshis) of an
+// output of outputSize bytes considered standard when relaying
+// transactions. An output is dust if it is worth less than the
+// fee, at DustRelayFeeRate, of creating and spending it.
+func DustThreshold(outputSize int) int64 {
+	satoshisPerKB := int64(math.Round(DustRelayFeeRate * SatoshisInRavencoin))
+	return satoshisPerKB * int64(outputSize+LegacyInputSize) / 1000 // nolint:gomnd
+}
+
+// FeeRatePerByte converts feePerKB, a fee rate in RVN per
+// 1000 vbytes (the unit of MinFeeRate and of ravend fee
+// estimates), into Satoshis per vbyte. The fee of a
+// transaction is its vsize multiplied by this rate.
+func FeeRatePerByte(feePerKB float64) float64 {
+	return feePerKB * SatoshisInRavencoin / 1000 // nolint:gomnd
+}
+
+// TxVSize returns the virtual size (in vBytes) of tx: its
+// weight (3 times its size without witness data plus its
+// size with witness data) divided by 4, rounded up as
+// ravend does. The vsize of a transaction without witness
+// data is its size.
+func TxVSize(tx *wire.MsgTx) int {
+	weight := tx.SerializeSizeStripped()*(witnessScaleFactor-1) + tx.SerializeSize()
+	return (weight + witnessScaleFactor - 1) / witnessScaleFactor
+}
+
 var (
 	// MainnetGenesisBlockIdentifier is the genesis block for mainnet.
 	MainnetGenesisBlockIdentifier = &types.BlockIdentifier{
@@ -120,6 +205,9 @@ var (
 		InputOpType,
 		OutputOpType,
 		CoinbaseOpType,
+		IssueAssetOpType,
+		ReissueAssetOpType,
+		BurnAssetOpType,
 	}
 
 	// OperationStatuses are all supported operation.Status.
@@ -144,6 +232,105 @@ type ScriptPubKey struct {
 	RequiredSigs int64    `json:"reqSigs,omitempty"`
 	Type         string   `json:"type"`
 	Addresses    []string `json:"addresses,omitempty"`
+
+	// Asset is populated by ravend for outputs
+	// that carry an asset.
+	Asset *ScriptPubKeyAsset `json:"asset,omitempty"`
+}
+
+// ScriptPubKeyAsset is the asset carried by an output.
+type ScriptPubKeyAsset struct {
+	Name   string  `json:"name"`
+	Amount float64 `json:"amount"`
+}
+
+// AssetCurrency returns the *types.Currency used to
+// track balances of the asset name, divisible into units
+// decimal places. Owner tokens are tracked as their own
+// currency (ASSETNAME!), distinct from the underlying
+// asset. Nil is returned if units is not between 0 and
+// MaxAssetUnits.
+//
+// Transfer scripts do not carry the units of an asset,
+// so every operation on an asset (including its issuance)
+// uses units of MaxAssetUnits and amounts in Satoshis.
+// Only the metadata of issuances and reissuances carries
+// the real units of an asset.
+func AssetCurrency(name string, units int32) *types.Currency {
+	if units < 0 || units > MaxAssetUnits {
+		return nil
+	}
+
+	return &types.Currency{
+		Symbol:   name,
+		Decimals: units,
+	}
+}
+
+// SpendableCoin is a coin that may be selected to
+// fund a transaction. Address owns the coin, so it
+// determines the size of the input spending it.
+// Coinbase outputs are Immature until MaturesAtHeight,
+// the first height of a block that can include a
+// transaction spending them.
+type SpendableCoin struct {
+	*types.Coin
+
+	Address         string `json:"address"`
+	Immature        bool   `json:"immature,omitempty"`
+	MaturesAtHeight int32  `json:"matures_at_height,omitempty"`
+}
+
+// NewSpendableCoin returns a *SpendableCoin for coin,
+// owned by address and created at height. If coinbase
+// is true, the coin is immature unless it can be spent
+// in the block after currentHeight (so it has at least
+// maturity confirmations).
+func NewSpendableCoin(
+	coin *types.Coin,
+	address string,
+	coinbase bool,
+	height int64,
+	currentHeight int64,
+	maturity int64,
+) *SpendableCoin {
+	spendable := &SpendableCoin{Coin: coin, Address: address}
+	if !coinbase {
+		return spendable
+	}
+
+	spendable.MaturesAtHeight = int32(height + maturity)
+	spendable.Immature = currentHeight+1 < height+maturity
+
+	return spendable
+}
+
+// OwnerTokenName returns the name of the owner
+// token of the asset name.
+func OwnerTokenName(name string) string {
+	return name + OwnerTokenSuffix
+}
+
+// IsOwnerToken returns a boolean indicating if
+// name is the name of an owner token.
+func IsOwnerToken(name string) bool {
+	return len(name) > len(OwnerTokenSuffix) && strings.HasSuffix(name, OwnerTokenSuffix)
+}
+
+// UniqueAssetTag separates the name of a unique asset
+// from its parent asset name.
+const UniqueAssetTag = "#"
+
+// CanonicalAssetName returns the canonical casing of the
+// asset name. Asset names are uppercase, except for the
+// tag of a unique asset, which is case-sensitive.
+func CanonicalAssetName(name string) string {
+	tag := strings.Index(name, UniqueAssetTag)
+	if tag == -1 {
+		return strings.ToUpper(name)
+	}
+
+	return strings.ToUpper(name[:tag]) + name[tag:]
 }
 
 // ScriptSig is a script on the input operations of a
@@ -163,6 +350,48 @@ type BlockchainInfo struct {
 	BestBlockHash string `json:"bestblockhash"`
 }
 
+// networkInfo is information about the P2P networking
+// of ravend. This struct only contains the information
+// necessary for this implementation.
+type networkInfo struct {
+	Version         int64  `json:"version"`
+	SubVersion      string `json:"subversion"`
+	ProtocolVersion int64  `json:"protocolversion"`
+	Connections     int64  `json:"connections"`
+}
+
+// NodeInfo is information about the ravend node
+// backing this implementation.
+type NodeInfo struct {
+	ProtocolVersion int64  `json:"protocol_version"`
+	SubVersion      string `json:"subversion"`
+	Blocks          int64  `json:"blocks"`
+	Connections     int64  `json:"connections"`
+}
+
+// assetData is the metadata of an asset returned
+// by ravend. Booleans are encoded as 0 or 1.
+type assetData struct {
+	Name       string  `json:"name"`
+	Amount     float64 `json:"amount"`
+	Units      int32   `json:"units"`
+	Reissuable int64   `json:"reissuable"`
+	HasIPFS    int64   `json:"has_ipfs"`
+	IPFSHash   string  `json:"ipfs_hash,omitempty"`
+}
+
+// AssetInfo is the metadata of an asset. Amount is
+// the total supply of the asset (in Satoshis), which
+// (with Units, Reissuable and IPFSHash) only changes
+// when the asset is reissued.
+type AssetInfo struct {
+	Name       string `json:"name"`
+	Amount     int64  `json:"amount"`
+	Units      int32  `json:"units"`
+	Reissuable bool   `json:"reissuable"`
+	IPFSHash   string `json:"ipfs_hash,omitempty"`
+}
+
 // PeerInfo is a collection of relevant info about a particular peer.
 type PeerInfo struct {
 	Addr           string `json:"addr"`
@@ -177,6 +406,49 @@ type PeerInfo struct {
 	SyncedHeaders  int64  `json:"synced_headers"`
 }
 
+// MempoolEntry is a transaction in the mempool of
+// ravend. This struct only contains the information
+// necessary for this implementation.
+type MempoolEntry struct {
+	Size            int64    `json:"size"`
+	Fee             float64  `json:"fee"`
+	Time            int64    `json:"time"`
+	Height          int64    `json:"height"`
+	AncestorCount   int64    `json:"ancestorcount"`
+	AncestorSize    int64    `json:"ancestorsize"`
+	DescendantCount int64    `json:"descendantcount"`
+	DescendantSize  int64    `json:"descendantsize"`
+	Depends         []string `json:"depends"`
+}
+
+// BlockHeader is a raw Ravencoin block header. This
+// struct only contains the information necessary for
+// this implementation. Blocks mined with KAWPOW have a
+// 64-bit nonce and also commit to a mix hash, so the
+// header cannot be represented by a *wire.BlockHeader.
+type BlockHeader struct {
+	Hash              string `json:"hash"`
+	Height            int64  `json:"height"`
+	PreviousBlockHash string `json:"previousblockhash"`
+	Time              int64  `json:"time"`
+	Nonce             uint64 `json:"nonce"`
+	MixHash           string `json:"mixhash,omitempty"`
+	MerkleRoot        string `json:"merkleroot"`
+	Version           int32  `json:"version"`
+	Bits              string `json:"bits"`
+}
+
+// Difficulty returns the difficulty of the target of
+// the header, as reported by ravend.
+func (b BlockHeader) Difficulty() (float64, error) {
+	bits, err := strconv.ParseUint(b.Bits, 16, 32)
+	if err != nil {
+		return 0, fmt.Errorf("%w: unable to parse bits %s", err, b.Bits)
+	}
+
+	return chaincfg.CompactToDifficulty(uint32(bits), chaincfg.DifficultyOneTarget), nil
+}
+
 // Block is a raw Ravencoin block (with verbosity == 2).
 type Block struct {
 	Hash              string  `json:"hash"`
@@ -236,6 +508,14 @@ type Transaction struct {
 
 	Inputs  []*Input  `json:"vin"`
 	Outputs []*Output `json:"vout"`
+
+	// BlockHash is only populated by
+	// `getrawtransaction` requests.
+	BlockHash string `json:"blockhash,omitempty"`
+
+	// WitnessHash is the wtxid of the transaction
+	// (equal to the txid if it has no witness).
+	WitnessHash string `json:"hash,omitempty"`
 }
 
 // Metadata returns the metadata for a transaction.
@@ -400,6 +680,44 @@ func (b blockchainInfoResponse) Err() error {
 	)
 }
 
+// networkInfoResponse is the response body for `getnetworkinfo` requests.
+type networkInfoResponse struct {
+	Result *networkInfo   `json:"result"`
+	Error  *responseError `json:"error"`
+}
+
+func (n networkInfoResponse) Err() error {
+	if n.Error == nil {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: error JSON RPC response, code: %d, message: %s",
+		ErrJSONRPCError,
+		n.Error.Code,
+		n.Error.Message,
+	)
+}
+
+// assetDataResponse is the response body for `getassetdata` requests.
+type assetDataResponse struct {
+	Result *assetData     `json:"result"`
+	Error  *responseError `json:"error"`
+}
+
+func (a assetDataResponse) Err() error {
+	if a.Error == nil {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: error JSON RPC response, code: %d, message: %s",
+		ErrJSONRPCError,
+		a.Error.Code,
+		a.Error.Message,
+	)
+}
+
 type peerInfoResponse struct {
 	Result []*PeerInfo    `json:"result"`
 	Error  *responseError `json:"error"`
@@ -448,6 +766,11 @@ func (s sendRawTransactionResponse) Err() error {
 		return nil
 	}
 
+	if s.Error.Code == transactionAlreadyInChainErrCode ||
+		(s.Error.Code == transactionRejectedErrCode && alreadyKnownRejection(s.Error.Message)) {
+		return fmt.Errorf("%w: %s", ErrTransactionAlreadyKnown, s.Error.Message)
+	}
+
 	return fmt.Errorf(
 		"%w: error JSON RPC response, code: %d, message: %s",
 		ErrJSONRPCError,
@@ -456,6 +779,13 @@ func (s sendRawTransactionResponse) Err() error {
 	)
 }
 
+// alreadyKnownRejection returns true if message is the
+// reject reason of a transaction already in the mempool.
+func alreadyKnownRejection(message string) bool {
+	return strings.Contains(message, "txn-already-in-mempool") ||
+		strings.Contains(message, "txn-already-known")
+}
+
 type suggestedFeeRate struct {
 	FeeRate float64 `json:"feerate"`
 }
@@ -498,6 +828,71 @@ func (r rawMempoolResponse) Err() error {
 	)
 }
 
+// mempoolEntryResponse is the response body for `getmempoolentry` requests.
+type mempoolEntryResponse struct {
+	Result *MempoolEntry  `json:"result"`
+	Error  *responseError `json:"error"`
+}
+
+func (m mempoolEntryResponse) Err() error {
+	if m.Error == nil {
+		return nil
+	}
+
+	if m.Error.Code == transactionNotInMempoolErrCode {
+		return ErrTransactionNotInMempool
+	}
+
+	return fmt.Errorf(
+		"%w: error JSON RPC response, code: %d, message: %s",
+		ErrJSONRPCError,
+		m.Error.Code,
+		m.Error.Message,
+	)
+}
+
+// rawTransactionResponse is the response body for `getrawtransaction` requests.
+type rawTransactionResponse struct {
+	Result *Transaction   `json:"result"`
+	Error  *responseError `json:"error"`
+}
+
+func (r rawTransactionResponse) Err() error {
+	if r.Error == nil {
+		return nil
+	}
+
+	return fmt.Errorf(
+		"%w: error JSON RPC response, code: %d, message: %s",
+		ErrJSONRPCError,
+		r.Error.Code,
+		r.Error.Message,
+	)
+}
+
+// blockHeaderResponse is the response body for `getblockheader` requests.
+type blockHeaderResponse struct {
+	Result *BlockHeader   `json:"result"`
+	Error  *responseError `json:"error"`
+}
+
+func (b blockHeaderResponse) Err() error {
+	if b.Error == nil {
+		return nil
+	}
+
+	if b.Error.Code == blockNotFoundErrCode {
+		return ErrBlockNotFound
+	}
+
+	return fmt.Errorf(
+		"%w: error JSON RPC response, code: %d, message: %s",
+		ErrJSONRPCError,
+		b.Error.Code,
+		b.Error.Message,
+	)
+}
+
 // CoinIdentifier converts a tx hash and vout into
 // the canonical CoinIdentifier.Identifier used in
 // rosetta-ravencoin.
@@ -0,0 +1,286 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package ravencoin
+
+import (
+	"math"
+	"testing"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDustThreshold(t *testing.T) {
+	// (34 output + 148 input) * 3 Satoshis per byte
+	assert.Equal(t, int64(546), DustThreshold(OutputOverhead+P2PKHScriptPubkeySize))
+
+	// (31 output + 148 input) * 3 Satoshis per byte
+	assert.Equal(t, int64(537), DustThreshold(OutputOverhead+P2WPKHScriptPubkeySize))
+}
+
+func TestFeeRatePerByte(t *testing.T) {
+	assert.InDelta(t, float64(1), FeeRatePerByte(MinFeeRate), 1e-9)
+	assert.Equal(t, float64(20), FeeRatePerByte(0.0002))
+
+	// A 220 vbyte transaction at 0.0002 RVN per kB pays
+	// 20000 Satoshis per kB, or 20 Satoshis per vbyte.
+	assert.Equal(t, int64(4400), int64(FeeRatePerByte(0.0002)*220))
+}
+
+func TestTxVSize(t *testing.T) {
+	tests := map[string]struct {
+		signatureScript []byte
+		witness         wire.TxWitness
+		pkScript        []byte
+
+		size  int
+		vsize int
+	}{
+		"legacy": {
+			signatureScript: make([]byte, 106),
+			pkScript:        make([]byte, P2PKHScriptPubkeySize),
+			size:            191,
+			vsize:           191,
+		},
+		"segwit": {
+			witness:  wire.TxWitness{make([]byte, 72), make([]byte, 33)},
+			pkScript: make([]byte, P2WPKHScriptPubkeySize),
+			size:     192,
+			vsize:    110, // (82 * 3 + 192) / 4, rounded up
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			tx := wire.NewMsgTx(wire.TxVersion)
+			tx.AddTxIn(&wire.TxIn{
+				PreviousOutPoint: wire.OutPoint{Index: 1},
+				SignatureScript:  test.signatureScript,
+				Witness:          test.witness,
+				Sequence:         wire.MaxTxInSequenceNum,
+			})
+			tx.AddTxOut(wire.NewTxOut(1000, test.pkScript))
+
+			assert.Equal(t, test.size, tx.SerializeSize())
+			assert.Equal(t, test.vsize, TxVSize(tx))
+		})
+	}
+}
+
+func TestAssetCurrency(t *testing.T) {
+	tests := map[string]struct {
+		units int32
+
+		currency *types.Currency
+	}{
+		"0 units": {
+			units: 0,
+			currency: &types.Currency{
+				Symbol:   "ROSETTA",
+				Decimals: 0,
+			},
+		},
+		"4 units": {
+			units: 4,
+			currency: &types.Currency{
+				Symbol:   "ROSETTA",
+				Decimals: 4,
+			},
+		},
+		"max units": {
+			units: MaxAssetUnits,
+			currency: &types.Currency{
+				Symbol:   "ROSETTA",
+				Decimals: Decimals,
+			},
+		},
+		"9 units": {
+			units: 9,
+		},
+		"negative units": {
+			units: -1,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.currency, AssetCurrency("ROSETTA", test.units))
+		})
+	}
+}
+
+func TestAssetValue(t *testing.T) {
+	// 1,234.5678 ROSETTA
+	assert.Equal(t, int64(1234), AssetValue(123456780000, 0))
+	assert.Equal(t, int64(12345678), AssetValue(123456780000, 4))
+	assert.Equal(t, int64(123456780000), AssetValue(123456780000, MaxAssetUnits))
+
+	amount, err := AssetAmount(12345678, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(123456780000), amount)
+
+	_, err = AssetAmount(1, 9)
+	assert.Error(t, err)
+
+	_, err = AssetAmount(MaxAssetAmount, 0)
+	assert.Error(t, err)
+}
+
+func TestFormatAssetAmount(t *testing.T) {
+	tests := map[string]struct {
+		amount int64
+		units  int
+
+		formatted string
+	}{
+		"0 units": {
+			amount:    123456780000,
+			formatted: "1234",
+		},
+		"0 units (truncated)": {
+			amount:    99999999,
+			formatted: "0",
+		},
+		"2 units": {
+			amount:    123456780000,
+			units:     2,
+			formatted: "1234.56",
+		},
+		"2 units (padded)": {
+			amount:    150000000,
+			units:     2,
+			formatted: "1.50",
+		},
+		"2 units (fraction)": {
+			amount:    1000000,
+			units:     2,
+			formatted: "0.01",
+		},
+		"8 units": {
+			amount:    123456780000,
+			units:     MaxAssetUnits,
+			formatted: "1234.56780000",
+		},
+		"8 units (satoshi)": {
+			amount:    1,
+			units:     MaxAssetUnits,
+			formatted: "0.00000001",
+		},
+		"negative": {
+			amount:    -150000000,
+			units:     2,
+			formatted: "-1.50",
+		},
+		"min int64": {
+			amount:    math.MinInt64,
+			units:     MaxAssetUnits,
+			formatted: "-92233720368.54775808",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.formatted, FormatAssetAmount(test.amount, test.units))
+		})
+	}
+}
+
+func TestBlockHeaderDifficulty(t *testing.T) {
+	tests := map[string]struct {
+		bits string
+
+		difficulty float64
+		err        string
+	}{
+		"difficulty 1": {
+			bits:       "1d00ffff",
+			difficulty: 1,
+		},
+		"kawpow": {
+			bits:       "1b01a4f3",
+			difficulty: 39855.068622811166,
+		},
+		"invalid bits": {
+			bits: "zz",
+			err:  "unable to parse bits zz",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			difficulty, err := BlockHeader{Bits: test.bits}.Difficulty()
+			if len(test.err) > 0 {
+				assert.Contains(t, err.Error(), test.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.InDelta(t, test.difficulty, difficulty, 1e-9)
+		})
+	}
+}
+
+func TestNewSpendableCoin(t *testing.T) {
+	coin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{
+			Identifier: "coin",
+		},
+	}
+
+	tests := map[string]struct {
+		coinbase bool
+		height   int64
+
+		immature        bool
+		maturesAtHeight int32
+	}{
+		"standard": {
+			height: 999,
+		},
+		"coinbase 50 deep": {
+			coinbase:        true,
+			height:          950,
+			immature:        true,
+			maturesAtHeight: 1050,
+		},
+		"coinbase 99 confirmations": {
+			coinbase:        true,
+			height:          902,
+			immature:        true,
+			maturesAtHeight: 1002,
+		},
+		"coinbase 100 confirmations": {
+			coinbase:        true,
+			height:          901,
+			maturesAtHeight: 1001,
+		},
+		"coinbase 150 deep": {
+			coinbase:        true,
+			height:          850,
+			maturesAtHeight: 950,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			spendable := NewSpendableCoin(coin, "address", test.coinbase, test.height, 1000, 100)
+			assert.Equal(t, coin, spendable.Coin)
+			assert.Equal(t, "address", spendable.Address)
+			assert.Equal(t, test.immature, spendable.Immature)
+			assert.Equal(t, test.maturesAtHeight, spendable.MaturesAtHeight)
+		})
+	}
+}
@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
 
@@ -49,28 +50,153 @@ func (s *AccountAPIService) AccountBalance(
 		return nil, wrapErr(ErrUnavailableOffline, nil)
 	}
 
-	// TODO: filter balances by request currencies
+	// If a gap limit is provided, the address is
+	// an xpub to scan for used addresses.
+	var xpubMetadata xpubAccountMetadata
+	if err := types.UnmarshalMap(request.AccountIdentifier.Metadata, &xpubMetadata); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+	if xpubMetadata.GapLimit != 0 {
+		return s.xpubBalance(ctx, request, xpubMetadata.GapLimit)
+	}
+
+	// Assets (including owner tokens) are tracked as
+	// their own currencies, so we return a balance for each
+	// requested currency (defaulting to RVN).
+	currencies := request.Currencies
+	if len(currencies) == 0 {
+		currencies = []*types.Currency{s.config.Currency}
+	}
+
+	var block *types.BlockIdentifier
+	var immature *types.Amount
+	balances := make([]*types.Amount, len(currencies))
+	for i, currency := range currencies {
+		if currency.Symbol != s.config.Currency.Symbol {
+			// Asset balances are only available
+			// at the current block.
+			if request.BlockIdentifier != nil {
+				return nil, wrapErr(
+					ErrUnableToGetBalance,
+					fmt.Errorf("historical balance of asset %s is not supported", currency.Symbol),
+				)
+			}
+
+			amount, err := s.i.GetAssetBalance(ctx, request.AccountIdentifier, currency.Symbol)
+			if err != nil {
+				return nil, wrapErr(ErrUnableToGetBalance, err)
+			}
+
+			balances[i] = amount
+			continue
+		}
+
+		// If we are fetching a historical balance,
+		// use balance storage and don't return coins.
+		amount, amountBlock, err := s.i.GetBalance(
+			ctx,
+			request.AccountIdentifier,
+			currency,
+			request.BlockIdentifier,
+		)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToGetBalance, err)
+		}
+
+		// Immature coinbase outputs are only excluded
+		// from the current balance.
+		if s.config.ExcludeImmatureCoinbase && request.BlockIdentifier == nil {
+			_, immature, err = s.immatureCoinbase(ctx, request.AccountIdentifier)
+			if err != nil {
+				return nil, wrapErr(ErrUnableToGetBalance, err)
+			}
+
+			value, err := types.SubtractValues(amount.Value, immature.Value)
+			if err != nil {
+				return nil, wrapErr(ErrUnableToGetBalance, err)
+			}
+
+			amount = &types.Amount{
+				Value:    value,
+				Currency: amount.Currency,
+			}
+		}
+
+		balances[i] = amount
+		block = amountBlock
+	}
+
+	// If only assets were requested, we report
+	// balances at the current block.
+	if block == nil {
+		blockResponse, err := s.i.GetBlockLazy(ctx, nil)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToGetBalance, err)
+		}
+
+		block = blockResponse.Block.BlockIdentifier
+	}
 
-	// If we are fetching a historical balance,
-	// use balance storage and don't return coins.
-	amount, block, err := s.i.GetBalance(
-		ctx,
-		request.AccountIdentifier,
-		s.config.Currency,
-		request.BlockIdentifier,
-	)
+	metadata, err := s.accountMetadata(immature)
 	if err != nil {
-		return nil, wrapErr(ErrUnableToGetBalance, err)
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
 
 	return &types.AccountBalanceResponse{
 		BlockIdentifier: block,
-		Balances: []*types.Amount{
-			amount,
-		},
+		Balances:        balances,
+		Metadata:        metadata,
 	}, nil
 }
 
+// immatureCoinbase returns the immature coinbase coins of
+// an account and their total value.
+func (s *AccountAPIService) immatureCoinbase(
+	ctx context.Context,
+	account *types.AccountIdentifier,
+) ([]*types.Coin, *types.Amount, error) {
+	coins, err := s.i.GetImmatureCoinbaseCoins(ctx, account)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	total := "0"
+	for _, coin := range coins {
+		total, err = types.AddValues(total, coin.Amount.Value)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	return coins, &types.Amount{
+		Value:    total,
+		Currency: s.config.Currency,
+	}, nil
+}
+
+// accountMetadata returns the metadata to include in
+// /account/* responses. If there is nothing to report,
+// it returns nil.
+func (s *AccountAPIService) accountMetadata(
+	immature *types.Amount,
+) (map[string]interface{}, error) {
+	metadata := accountMetadata{
+		ImmatureCoinbase: immature,
+	}
+
+	// Balances and coins may be incomplete if the
+	// indexer did not sync from genesis.
+	if s.config.SyncStartHeight > 0 {
+		metadata.SyncStartHeight = s.config.SyncStartHeight
+	}
+
+	if metadata == (accountMetadata{}) {
+		return nil, nil
+	}
+
+	return types.MarshalMap(&metadata)
+}
+
 // AccountCoins implements /account/coins.
 func (s *AccountAPIService) AccountCoins(
 	ctx context.Context,
@@ -91,9 +217,37 @@ func (s *AccountAPIService) AccountCoins(
 		return nil, wrapErr(ErrUnableToGetCoins, err)
 	}
 
+	var immature *types.Amount
+	if s.config.ExcludeImmatureCoinbase {
+		var immatureCoins []*types.Coin
+		immatureCoins, immature, err = s.immatureCoinbase(ctx, request.AccountIdentifier)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToGetCoins, err)
+		}
+
+		excluded := map[string]struct{}{}
+		for _, coin := range immatureCoins {
+			excluded[coin.CoinIdentifier.Identifier] = struct{}{}
+		}
+
+		matureCoins := []*types.Coin{}
+		for _, coin := range coins {
+			if _, ok := excluded[coin.CoinIdentifier.Identifier]; !ok {
+				matureCoins = append(matureCoins, coin)
+			}
+		}
+		coins = matureCoins
+	}
+
+	metadata, err := s.accountMetadata(immature)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
 	result := &types.AccountCoinsResponse{
 		BlockIdentifier: block,
 		Coins:           coins,
+		Metadata:        metadata,
 	}
 
 	return result, nil
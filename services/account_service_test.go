@@ -131,6 +131,92 @@ func TestAccountBalance_Online_Historical(t *testing.T) {
 	mockIndexer.AssertExpectations(t)
 }
 
+func TestAccountBalance_Online_Asset(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Currency: ravencoin.MainnetCurrency,
+	}
+	mockIndexer := &mocks.Indexer{}
+	servicer := NewAccountAPIService(cfg, mockIndexer)
+	ctx := context.Background()
+	account := &types.AccountIdentifier{
+		Address: "hello",
+	}
+	block := &types.BlockIdentifier{
+		Index: 1000,
+		Hash:  "block 1000",
+	}
+	amount := &types.Amount{
+		Value:    "25",
+		Currency: ravencoin.MainnetCurrency,
+	}
+	assetAmount := &types.Amount{
+		Value:    "100000000000",
+		Currency: ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits),
+	}
+
+	mockIndexer.On(
+		"GetBalance",
+		ctx,
+		account,
+		ravencoin.MainnetCurrency,
+		(*types.PartialBlockIdentifier)(nil),
+	).Return(amount, block, nil).Once()
+	mockIndexer.On(
+		"GetAssetBalance",
+		ctx,
+		account,
+		"ROSETTA",
+	).Return(assetAmount, nil).Once()
+	bal, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		Currencies: []*types.Currency{
+			ravencoin.MainnetCurrency,
+			ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits),
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AccountBalanceResponse{
+		BlockIdentifier: block,
+		Balances: []*types.Amount{
+			amount,
+			assetAmount,
+		},
+	}, bal)
+
+	// Only an asset is requested
+	mockIndexer.On(
+		"GetAssetBalance",
+		ctx,
+		account,
+		"ROSETTA",
+	).Return(assetAmount, nil).Once()
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		(*types.PartialBlockIdentifier)(nil),
+	).Return(&types.BlockResponse{
+		Block: &types.Block{
+			BlockIdentifier: block,
+		},
+	}, nil).Once()
+	bal, err = servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+		Currencies: []*types.Currency{
+			ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits),
+		},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AccountBalanceResponse{
+		BlockIdentifier: block,
+		Balances: []*types.Amount{
+			assetAmount,
+		},
+	}, bal)
+
+	mockIndexer.AssertExpectations(t)
+}
+
 func TestAccountCoins_Online(t *testing.T) {
 	cfg := &configuration.Configuration{
 		Mode:     configuration.Online,
@@ -188,3 +274,90 @@ func TestAccountCoins_Online(t *testing.T) {
 
 	mockIndexer.AssertExpectations(t)
 }
+
+func TestAccount_Online_ExcludeImmatureCoinbase(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                    configuration.Online,
+		Currency:                ravencoin.MainnetCurrency,
+		ExcludeImmatureCoinbase: true,
+	}
+	mockIndexer := &mocks.Indexer{}
+	servicer := NewAccountAPIService(cfg, mockIndexer)
+	ctx := context.Background()
+
+	account := &types.AccountIdentifier{
+		Address: "hello",
+	}
+	block := &types.BlockIdentifier{
+		Index: 1000,
+		Hash:  "block 1000",
+	}
+	coins := []*types.Coin{
+		{
+			Amount: &types.Amount{
+				Value:    "10",
+				Currency: ravencoin.MainnetCurrency,
+			},
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "coin 1",
+			},
+		},
+		{
+			Amount: &types.Amount{
+				Value:    "500000000000",
+				Currency: ravencoin.MainnetCurrency,
+			},
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "coinbase",
+			},
+		},
+	}
+	immatureMetadata := map[string]interface{}{
+		"immature_coinbase": map[string]interface{}{
+			"value": "500000000000",
+			"currency": map[string]interface{}{
+				"symbol":   "RVN",
+				"decimals": float64(8),
+			},
+		},
+	}
+
+	mockIndexer.On(
+		"GetBalance",
+		ctx,
+		account,
+		ravencoin.MainnetCurrency,
+		(*types.PartialBlockIdentifier)(nil),
+	).Return(&types.Amount{
+		Value:    "500000000010",
+		Currency: ravencoin.MainnetCurrency,
+	}, block, nil).Once()
+	mockIndexer.On("GetImmatureCoinbaseCoins", ctx, account).Return(coins[1:], nil).Twice()
+	bal, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+		AccountIdentifier: account,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AccountBalanceResponse{
+		BlockIdentifier: block,
+		Balances: []*types.Amount{
+			{
+				Value:    "10",
+				Currency: ravencoin.MainnetCurrency,
+			},
+		},
+		Metadata: immatureMetadata,
+	}, bal)
+
+	mockIndexer.On("GetCoins", ctx, account).Return(coins, block, nil).Once()
+	accountCoins, err := servicer.AccountCoins(ctx, &types.AccountCoinsRequest{
+		AccountIdentifier: account,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.AccountCoinsResponse{
+		BlockIdentifier: block,
+		Coins:           coins[:1],
+		Metadata:        immatureMetadata,
+	}, accountCoins)
+
+	mockIndexer.AssertExpectations(t)
+}
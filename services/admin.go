@@ -0,0 +1,118 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+)
+
+const (
+	// adminBalancesEndpoint streams a balance export.
+	adminBalancesEndpoint = "/admin/balances"
+
+	// adminExportCSV and adminExportJSON are the
+	// export formats accepted by the indexer.
+	adminExportCSV  = "csv"
+	adminExportJSON = "json"
+)
+
+// BalanceExporter is implemented by the indexer.
+type BalanceExporter interface {
+	ExportBalances(ctx context.Context, w io.Writer, height int32, format string) error
+}
+
+// trackingWriter records if anything has been
+// written to the underlying io.Writer.
+type trackingWriter struct {
+	w       io.Writer
+	written bool
+}
+
+func (t *trackingWriter) Write(p []byte) (int, error) {
+	t.written = true
+	return t.w.Write(p)
+}
+
+// AdminMiddleware serves the admin endpoints for requests
+// bearing config.AdminToken and passes all other requests
+// to inner. If config.AdminToken is empty, inner is returned.
+//
+// GET /admin/balances?height=<height>&format=<csv|json> streams
+// the balances of all accounts at height. Exports that take longer than the
+// write timeout of the server are cut short.
+func AdminMiddleware(
+	config *configuration.Configuration,
+	exporter BalanceExporter,
+	inner http.Handler,
+) http.Handler {
+	if len(config.AdminToken) == 0 {
+		return inner
+	}
+
+	expected := []byte("Bearer " + config.AdminToken)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != adminBalancesEndpoint {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		authorization := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(authorization, expected) != 1 {
+			http.Error(w, "invalid admin token", http.StatusUnauthorized)
+			return
+		}
+
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		height, err := strconv.ParseInt(query.Get("height"), 10, 32)
+		if err != nil {
+			http.Error(w, "invalid height", http.StatusBadRequest)
+			return
+		}
+
+		format := query.Get("format")
+		switch format {
+		case "", adminExportCSV:
+			format = adminExportCSV
+			w.Header().Set("Content-Type", "text/csv; charset=UTF-8")
+		case adminExportJSON:
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		default:
+			http.Error(w, "format must be csv or json", http.StatusBadRequest)
+			return
+		}
+
+		body := &trackingWriter{w: w}
+		if err := exporter.ExportBalances(r.Context(), body, int32(height), format); err != nil {
+			if body.written {
+				// The status has already been sent, so
+				// the response can only be aborted.
+				panic(http.ErrAbortHandler)
+			}
+
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		}
+	})
+}
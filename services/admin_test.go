@@ -0,0 +1,152 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type stubExporter struct {
+	height int32
+	format string
+	err    error
+}
+
+func (s *stubExporter) ExportBalances(
+	ctx context.Context,
+	w io.Writer,
+	height int32,
+	format string,
+) error {
+	s.height = height
+	s.format = format
+	if s.err != nil {
+		return s.err
+	}
+
+	_, err := fmt.Fprintf(w, "account,currency,value\n")
+	return err
+}
+
+func TestAdminMiddleware(t *testing.T) {
+	inner := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	})
+
+	serve := func(
+		handler http.Handler,
+		token string,
+		path string,
+	) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodGet, path, nil)
+		if len(token) > 0 {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	t.Run("no admin token", func(t *testing.T) {
+		exporter := &stubExporter{}
+		handler := AdminMiddleware(&configuration.Configuration{}, exporter, inner)
+
+		recorder := serve(handler, "", "/admin/balances?height=1")
+		assert.Equal(t, http.StatusTeapot, recorder.Code)
+	})
+
+	cfg := &configuration.Configuration{AdminToken: "secret"}
+
+	t.Run("other endpoints", func(t *testing.T) {
+		handler := AdminMiddleware(cfg, &stubExporter{}, inner)
+
+		recorder := serve(handler, "", "/network/list")
+		assert.Equal(t, http.StatusTeapot, recorder.Code)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		exporter := &stubExporter{}
+		handler := AdminMiddleware(cfg, exporter, inner)
+
+		assert.Equal(
+			t,
+			http.StatusUnauthorized,
+			serve(handler, "", "/admin/balances?height=1").Code,
+		)
+		assert.Equal(
+			t,
+			http.StatusUnauthorized,
+			serve(handler, "wrong", "/admin/balances?height=1").Code,
+		)
+		assert.Empty(t, exporter.format)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		handler := AdminMiddleware(cfg, &stubExporter{}, inner)
+
+		assert.Equal(
+			t,
+			http.StatusBadRequest,
+			serve(handler, "secret", "/admin/balances").Code,
+		)
+		assert.Equal(
+			t,
+			http.StatusBadRequest,
+			serve(handler, "secret", "/admin/balances?height=1&format=xml").Code,
+		)
+	})
+
+	t.Run("csv export", func(t *testing.T) {
+		exporter := &stubExporter{}
+		handler := AdminMiddleware(cfg, exporter, inner)
+
+		recorder := serve(handler, "secret", "/admin/balances?height=10")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "text/csv; charset=UTF-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "account,currency,value\n", recorder.Body.String())
+		assert.Equal(t, int32(10), exporter.height)
+		assert.Equal(t, "csv", exporter.format)
+	})
+
+	t.Run("json export", func(t *testing.T) {
+		exporter := &stubExporter{}
+		handler := AdminMiddleware(cfg, exporter, inner)
+
+		recorder := serve(handler, "secret", "/admin/balances?height=10&format=json")
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		assert.Equal(t, "application/json; charset=UTF-8", recorder.Header().Get("Content-Type"))
+		assert.Equal(t, "json", exporter.format)
+	})
+
+	t.Run("export error", func(t *testing.T) {
+		exporter := &stubExporter{err: errors.New("height 10 has not been indexed")}
+		handler := AdminMiddleware(cfg, exporter, inner)
+
+		recorder := serve(handler, "secret", "/admin/balances?height=10")
+		assert.Equal(t, http.StatusBadRequest, recorder.Code)
+		assert.Contains(t, recorder.Body.String(), "height 10 has not been indexed")
+	})
+}
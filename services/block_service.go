@@ -16,6 +16,7 @@ package services
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
 
@@ -54,6 +55,10 @@ func (s *BlockAPIService) Block(
 		return nil, wrapErr(ErrBlockNotFound, err)
 	}
 
+	if err := s.checkServable(blockResponse.Block.BlockIdentifier.Index); err != nil {
+		return nil, wrapErr(ErrBlockNotFound, err)
+	}
+
 	// Direct client to fetch transactions individually if
 	// more than inlineFetchLimit.
 	if len(blockResponse.OtherTransactions) > inlineFetchLimit {
@@ -88,6 +93,10 @@ func (s *BlockAPIService) BlockTransaction(
 		return nil, wrapErr(ErrUnavailableOffline, nil)
 	}
 
+	if err := s.checkServable(request.BlockIdentifier.Index); err != nil {
+		return nil, wrapErr(ErrBlockNotFound, err)
+	}
+
 	transaction, err := s.i.GetBlockTransaction(
 		ctx,
 		request.BlockIdentifier,
@@ -101,3 +110,17 @@ func (s *BlockAPIService) BlockTransaction(
 		Transaction: transaction,
 	}, nil
 }
+
+// checkServable returns an error if the block at index
+// is above the configured MaxServableHeight.
+func (s *BlockAPIService) checkServable(index int64) error {
+	if s.config.MaxServableHeight > 0 && index > s.config.MaxServableHeight {
+		return fmt.Errorf(
+			"block %d is above the max servable height %d",
+			index,
+			s.config.MaxServableHeight,
+		)
+	}
+
+	return nil
+}
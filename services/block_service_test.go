@@ -211,3 +211,74 @@ func TestBlockService_Online_External(t *testing.T) {
 
 	mockIndexer.AssertExpectations(t)
 }
+
+func TestBlockService_MaxServableHeight(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:              configuration.Online,
+		MaxServableHeight: 500,
+	}
+	mockIndexer := &mocks.Indexer{}
+	servicer := NewBlockAPIService(cfg, mockIndexer)
+	ctx := context.Background()
+
+	pinnedIndex := int64(500)
+	pinnedBlock := &types.BlockResponse{
+		Block: &types.Block{
+			BlockIdentifier: &types.BlockIdentifier{
+				Index: 500,
+				Hash:  "block 500",
+			},
+		},
+	}
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Index: &pinnedIndex},
+	).Return(
+		pinnedBlock,
+		nil,
+	).Once()
+	block, err := servicer.Block(ctx, &types.BlockRequest{
+		BlockIdentifier: &types.PartialBlockIdentifier{Index: &pinnedIndex},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, pinnedBlock, block)
+
+	// The indexer synced to 1000 before the pin
+	// was configured.
+	aboveIndex := int64(501)
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Index: &aboveIndex},
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: 501,
+					Hash:  "block 501",
+				},
+			},
+		},
+		nil,
+	).Once()
+	block, err = servicer.Block(ctx, &types.BlockRequest{
+		BlockIdentifier: &types.PartialBlockIdentifier{Index: &aboveIndex},
+	})
+	assert.Nil(t, block)
+	assert.Equal(t, ErrBlockNotFound.Code, err.Code)
+
+	blockTransaction, err := servicer.BlockTransaction(ctx, &types.BlockTransactionRequest{
+		BlockIdentifier: &types.BlockIdentifier{
+			Index: 501,
+			Hash:  "block 501",
+		},
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: "tx 1",
+		},
+	})
+	assert.Nil(t, blockTransaction)
+	assert.Equal(t, ErrBlockNotFound.Code, err.Code)
+
+	mockIndexer.AssertExpectations(t)
+}
@@ -0,0 +1,208 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/btcsuite/btcd/wire"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// burnAssetDescription matches any number of BURN_ASSET
+// operations. Each operation spends a coin carrying an
+// asset and pays all of it to the global burn address.
+func (s *ConstructionAPIService) burnAssetDescription() *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: s.opTypes.BurnAsset,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		Amount: &parser.AmountDescription{
+			Exists: true,
+			Sign:   parser.NegativeAmountSign,
+		},
+		CoinAction:   types.CoinSpent,
+		AllowRepeats: true,
+		Optional:     true,
+	}
+}
+
+// parseBurnAmount returns the asset and quantity (in
+// Satoshis) burned by the BURN_ASSET operation op.
+func (s *ConstructionAPIService) parseBurnAmount(op *types.Operation) (string, int64, error) {
+	if op.Amount == nil || op.Amount.Currency == nil {
+		return "", 0, errors.New("burned amount cannot be nil")
+	}
+
+	name := op.Amount.Currency.Symbol
+	if name == s.config.Currency.Symbol {
+		return "", 0, fmt.Errorf("operation %d burns %s but only assets can be burned", op.OperationIdentifier.Index, name)
+	}
+
+	// Burns spend a coin, so their amounts are negative.
+	quantity, err := strconv.ParseInt(op.Amount.Value, 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: unable to parse burned amount %s", err, op.Amount.Value)
+	}
+
+	if quantity >= 0 {
+		return "", 0, fmt.Errorf("burned amount %d must be negative", quantity)
+	}
+
+	return name, -quantity, nil
+}
+
+// burnedCoin returns the coin spent by the BURN_ASSET
+// operation op.
+func (s *ConstructionAPIService) burnedCoin(op *types.Operation) (*types.Coin, error) {
+	if op.CoinChange == nil {
+		return nil, errors.New("CoinChange cannot be nil")
+	}
+
+	if _, _, err := s.parseBurnAmount(op); err != nil {
+		return nil, err
+	}
+
+	return &types.Coin{
+		CoinIdentifier: op.CoinChange.CoinIdentifier,
+		Amount:         op.Amount,
+	}, nil
+}
+
+// burnedCoins returns the coins spent by the BURN_ASSET
+// operations in operations, in operation order. If there
+// are none, nil is returned.
+func (s *ConstructionAPIService) burnedCoins(operations []*types.Operation) ([]*types.Coin, error) {
+	var coins []*types.Coin
+	for _, op := range operations {
+		if op.Type != s.opTypes.BurnAsset {
+			continue
+		}
+
+		coin, err := s.burnedCoin(op)
+		if err != nil {
+			return nil, err
+		}
+
+		coins = append(coins, coin)
+	}
+
+	return coins, nil
+}
+
+// assetBurnScript returns the locking script that
+// assets are burned to (that of the global burn
+// address).
+func (s *ConstructionAPIService) assetBurnScript() ([]byte, error) {
+	if len(s.config.Params.GlobalBurnAddress) == 0 {
+		return nil, fmt.Errorf("no global burn address for %s", s.config.Params.Name)
+	}
+
+	return s.burnScript(s.config.Params.GlobalBurnAddress)
+}
+
+// burnOutput returns the output that burns the asset
+// spent by the BURN_ASSET operation op. All of the asset
+// is transferred to the global burn address, which no
+// one can spend from.
+func (s *ConstructionAPIService) burnOutput(op *types.Operation) (*wire.TxOut, error) {
+	name, quantity, err := s.parseBurnAmount(op)
+	if err != nil {
+		return nil, err
+	}
+
+	burnScript, err := s.assetBurnScript()
+	if err != nil {
+		return nil, err
+	}
+
+	script, err := ravencoin.TransferAssetScript(burnScript, name, quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	return &wire.TxOut{Value: 0, PkScript: script}, nil
+}
+
+// parseBurns returns the BURN_ASSET operations represented
+// by the trailing outputs that transfer an asset to the
+// global burn address, as built by burnOutput. The coin
+// burned by each is spent by the corresponding trailing
+// input operation of inputs, so the last input spends the
+// coin burned by the last output. The operations do not
+// yet have an Index.
+func (s *ConstructionAPIService) parseBurns(
+	outputs []*wire.TxOut,
+	inputs []*types.Operation,
+) ([]*types.Operation, error) {
+	burnScript, err := s.assetBurnScript()
+	if err != nil {
+		// Networks without a global burn
+		// address cannot burn assets.
+		return nil, nil
+	}
+
+	first := len(outputs)
+	for first > 0 {
+		asset, err := ravencoin.ParseAssetScript(outputs[first-1].PkScript)
+		if err != nil {
+			return nil, err
+		}
+
+		if asset == nil || asset.Type != ravencoin.TransferAssetType ||
+			!bytes.Equal(ravencoin.AssetScriptBase(outputs[first-1].PkScript), burnScript) {
+			break
+		}
+
+		first--
+	}
+
+	count := len(outputs) - first
+	if count > len(inputs) {
+		return nil, fmt.Errorf("%d assets are burned but only %d coins are spent", count, len(inputs))
+	}
+
+	inputs = inputs[len(inputs)-count:]
+	burns := make([]*types.Operation, count)
+	for i, output := range outputs[first:] {
+		asset, err := ravencoin.ParseAssetScript(output.PkScript)
+		if err != nil {
+			return nil, err
+		}
+
+		networkIndex := int64(first + i)
+		burns[i] = &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				NetworkIndex: &networkIndex,
+			},
+			Type:    s.opTypes.BurnAsset,
+			Account: inputs[i].Account,
+			Amount: &types.Amount{
+				Value:    strconv.FormatInt(-asset.Amount, 10),
+				Currency: ravencoin.AssetCurrency(asset.Name, ravencoin.MaxAssetUnits),
+			},
+			CoinChange: inputs[i].CoinChange,
+		}
+	}
+
+	return burns, nil
+}
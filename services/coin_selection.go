@@ -0,0 +1,282 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// bnbMaxTries is the maximum number of branches
+	// explored by the branch-and-bound search before
+	// falling back to largest-first selection.
+	bnbMaxTries = 100000
+)
+
+var (
+	errInsufficientFunds = errors.New("available coins do not cover outputs and fee")
+)
+
+// insufficientFundsError is returned when the value of the coins
+// spent by a transaction does not cover its outputs and fee.
+type insufficientFundsError struct {
+	required  int64
+	available int64
+}
+
+func (e *insufficientFundsError) Error() string {
+	return fmt.Sprintf(
+		"%s: %d required but %d available",
+		errInsufficientFunds.Error(),
+		e.required,
+		e.available,
+	)
+}
+
+func (e *insufficientFundsError) Unwrap() error {
+	return errInsufficientFunds
+}
+
+// coinSelection is the result of selectCoins. inputSize
+// is the total size (in vBytes) of the inputs spending
+// coins.
+type coinSelection struct {
+	coins     []*types.Coin
+	inputSize int
+	fee       int64
+	change    int64
+}
+
+// availableCoin is a coin that may be selected and the
+// size (in vBytes) of the input spending it.
+type availableCoin struct {
+	coin      *types.Coin
+	inputSize int
+}
+
+// selectionCandidate is a coin with its parsed value and its
+// value net of the fee required to spend it.
+type selectionCandidate struct {
+	coin           *types.Coin
+	inputSize      int
+	value          int64
+	effectiveValue int64
+}
+
+// feeForSize returns the fee in satoshis to pay for size vBytes
+// at feeRate satoshis per vByte.
+func feeForSize(size int, feeRate float64) int64 {
+	return int64(math.Ceil(float64(size) * feeRate))
+}
+
+// selectCoins chooses a near-minimal set of coins from available
+// that pays target satoshis at feeRate (in satoshis per vByte).
+// baseSize is the size of the transaction without any inputs
+// or change and changeSize is the size of a change output. Each
+// coin is charged the fee of its own input, so legacy coins are
+// more expensive to spend than SegWit coins.
+//
+// We first attempt a branch-and-bound search for a set of coins
+// that does not require change. If none is found, we fall back
+// to largest-first selection. Any change below the dust threshold
+// is absorbed into the fee instead of creating an output.
+func selectCoins(
+	available []*availableCoin,
+	target int64,
+	feeRate float64,
+	baseSize int,
+	changeSize int,
+) (*coinSelection, error) {
+	candidates := make([]*selectionCandidate, 0, len(available))
+	for _, input := range available {
+		coin := input.coin
+		value, err := strconv.ParseInt(coin.Amount.Value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to parse value of coin %s",
+				err,
+				coin.CoinIdentifier.Identifier,
+			)
+		}
+
+		if value <= 0 {
+			return nil, fmt.Errorf(
+				"coin %s has non-positive value %d",
+				coin.CoinIdentifier.Identifier,
+				value,
+			)
+		}
+
+		candidates = append(candidates, &selectionCandidate{
+			coin:           coin,
+			inputSize:      input.inputSize,
+			value:          value,
+			effectiveValue: value - feeForSize(input.inputSize, feeRate),
+		})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].value > candidates[j].value
+	})
+
+	if selected := branchAndBound(
+		candidates,
+		target+feeForSize(baseSize, feeRate),
+		feeForSize(changeSize, feeRate)+feeForSize(ravencoin.InputSize, feeRate),
+	); selected != nil {
+		return newCoinSelection(selected, target, 0), nil
+	}
+
+	return largestFirst(candidates, target, feeRate, baseSize, changeSize)
+}
+
+// newCoinSelection creates a *coinSelection where any value
+// not paid to target or change is paid as fee.
+func newCoinSelection(
+	selected []*selectionCandidate,
+	target int64,
+	change int64,
+) *coinSelection {
+	total := int64(0)
+	inputSize := 0
+	coins := make([]*types.Coin, len(selected))
+	for i, candidate := range selected {
+		total += candidate.value
+		inputSize += candidate.inputSize
+		coins[i] = candidate.coin
+	}
+
+	return &coinSelection{
+		coins:     coins,
+		inputSize: inputSize,
+		fee:       total - target - change,
+		change:    change,
+	}
+}
+
+// branchAndBound searches for the set of candidates (sorted by
+// descending value) with an effective value in
+// [selectionTarget, selectionTarget+costOfChange] that wastes the
+// least value. It returns nil if no such set is found within
+// bnbMaxTries.
+func branchAndBound(
+	candidates []*selectionCandidate,
+	selectionTarget int64,
+	costOfChange int64,
+) []*selectionCandidate {
+	remaining := int64(0)
+	for _, candidate := range candidates {
+		if candidate.effectiveValue > 0 {
+			remaining += candidate.effectiveValue
+		}
+	}
+
+	if remaining < selectionTarget {
+		return nil
+	}
+
+	tries := 0
+	bestWaste := int64(math.MaxInt64)
+	var best []*selectionCandidate
+	current := []*selectionCandidate{}
+
+	var search func(depth int, value int64, remaining int64)
+	search = func(depth int, value int64, remaining int64) {
+		tries++
+		if tries > bnbMaxTries {
+			return
+		}
+
+		// Prune branches that can no longer reach the target
+		// or that already exceed the upper bound.
+		if value+remaining < selectionTarget || value > selectionTarget+costOfChange {
+			return
+		}
+
+		if value >= selectionTarget {
+			if waste := value - selectionTarget; waste < bestWaste {
+				bestWaste = waste
+				best = append([]*selectionCandidate{}, current...)
+			}
+
+			return
+		}
+
+		if depth == len(candidates) {
+			return
+		}
+
+		candidate := candidates[depth]
+		if candidate.effectiveValue <= 0 {
+			search(depth+1, value, remaining)
+			return
+		}
+
+		current = append(current, candidate)
+		search(depth+1, value+candidate.effectiveValue, remaining-candidate.effectiveValue)
+		current = current[:len(current)-1]
+
+		if bestWaste == 0 {
+			return
+		}
+
+		search(depth+1, value, remaining-candidate.effectiveValue)
+	}
+
+	search(0, 0, remaining)
+
+	return best
+}
+
+// largestFirst selects candidates (sorted by descending value)
+// until target and the fee are covered.
+func largestFirst(
+	candidates []*selectionCandidate,
+	target int64,
+	feeRate float64,
+	baseSize int,
+	changeSize int,
+) (*coinSelection, error) {
+	total := int64(0)
+	size := baseSize
+	for i, candidate := range candidates {
+		total += candidate.value
+		size += candidate.inputSize
+		if total < target+feeForSize(size, feeRate) {
+			continue
+		}
+
+		selected := candidates[:i+1]
+		change := total - target - feeForSize(size+changeSize, feeRate)
+		if change < ravencoin.DustThreshold(changeSize) {
+			return newCoinSelection(selected, target, 0), nil
+		}
+
+		return newCoinSelection(selected, target, change), nil
+	}
+
+	return nil, &insufficientFundsError{
+		required:  target + feeForSize(size, feeRate),
+		available: total,
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func testCoin(identifier string, value int64, inputSize int) *availableCoin {
+	return &availableCoin{
+		coin: &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: identifier,
+			},
+			Amount: &types.Amount{
+				Value:    strconv.FormatInt(value, 10),
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+		inputSize: inputSize,
+	}
+}
+
+func testCoins(inputSize int, values ...int64) []*availableCoin {
+	coins := make([]*availableCoin, len(values))
+	for i, value := range values {
+		coins[i] = testCoin(fmt.Sprintf("coin %d", i), value, inputSize)
+	}
+
+	return coins
+}
+
+func TestSelectCoins(t *testing.T) {
+	// 12 overhead + 31 P2WPKH output
+	baseSize := 43
+	changeSize := ravencoin.OutputOverhead + ravencoin.P2WPKHScriptPubkeySize
+
+	tests := map[string]struct {
+		available []*availableCoin
+		target    int64
+		feeRate   float64
+
+		selected []string
+		fee      int64
+		change   int64
+		err      error
+	}{
+		"exact match (no change)": {
+			available: testCoins(ravencoin.InputSize, 100000, 50000, 30000, 20000),
+			// 50000 + 30000 - 2 inputs * 68 - 43
+			target:   79821,
+			feeRate:  1,
+			selected: []string{"coin 1", "coin 2"},
+			fee:      179,
+		},
+		"largest first with change": {
+			available: testCoins(ravencoin.InputSize, 50000, 100000),
+			target:    120000,
+			feeRate:   1,
+			selected:  []string{"coin 1", "coin 0"},
+			fee:       210,
+			change:    29790,
+		},
+		"dust change absorbed into fee": {
+			available: testCoins(ravencoin.InputSize, 20500, 100000),
+			target:    120000,
+			feeRate:   1,
+			selected:  []string{"coin 1", "coin 0"},
+			fee:       500,
+		},
+		"higher fee rate": {
+			available: testCoins(ravencoin.InputSize, 10000, 200000, 60000),
+			target:    50000,
+			feeRate:   10,
+			selected:  []string{"coin 1"},
+			fee:       1420,
+			change:    148580,
+		},
+		"insufficient funds": {
+			available: testCoins(ravencoin.InputSize, 1000, 2000),
+			target:    5000,
+			feeRate:   1,
+			err: &insufficientFundsError{
+				required:  5179, // 5000 + (43 + 2 * 68) * 1
+				available: 3000,
+			},
+		},
+		"exact match of legacy coins": {
+			available: testCoins(ravencoin.LegacyInputSize, 50000, 30000, 20000),
+			// 50000 + 30000 - 2 inputs * 148 - 43
+			target:   79661,
+			feeRate:  1,
+			selected: []string{"coin 0", "coin 1"},
+			fee:      339,
+		},
+		"legacy and segwit coins with change": {
+			available: []*availableCoin{
+				testCoin("coin 0", 100000, ravencoin.LegacyInputSize),
+				testCoin("coin 1", 50000, ravencoin.InputSize),
+			},
+			target:   120000,
+			feeRate:  1,
+			selected: []string{"coin 0", "coin 1"},
+			// 43 + 148 + 68 + 31 change
+			fee:    290,
+			change: 29710,
+		},
+		"insufficient funds of legacy coins": {
+			available: testCoins(ravencoin.LegacyInputSize, 1000, 2000),
+			target:    5000,
+			feeRate:   1,
+			err: &insufficientFundsError{
+				required:  5339, // 5000 + (43 + 2 * 148) * 1
+				available: 3000,
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			selection, err := selectCoins(
+				test.available,
+				test.target,
+				test.feeRate,
+				baseSize,
+				changeSize,
+			)
+			if test.err != nil {
+				assert.Nil(t, selection)
+				assert.Equal(t, test.err, err)
+				return
+			}
+
+			assert.NoError(t, err)
+			selected := make([]string, len(selection.coins))
+			for i, coin := range selection.coins {
+				selected[i] = coin.CoinIdentifier.Identifier
+			}
+
+			assert.Equal(t, test.selected, selected)
+			assert.Equal(t, test.fee, selection.fee)
+			assert.Equal(t, test.change, selection.change)
+		})
+	}
+}
@@ -21,10 +21,13 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"math/rand"
 	"strconv"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
 
 	"github.com/btcsuite/btcd/btcec"
@@ -37,33 +40,104 @@ import (
 )
 
 const (
-	// bytesInKB is the number of bytes in a KB. In Ravencoin, this is
-	// considered to be 1000.
-	bytesInKb = float64(1000) // nolint:gomnd
-
 	// defaultConfirmationTarget is the number of blocks we would
 	// like our transaction to be included by.
 	defaultConfirmationTarget = int64(2) // nolint:gomnd
+
+	// replaceableSequence is the highest input sequence
+	// number that signals opt-in replace-by-fee (BIP125).
+	replaceableSequence = uint32(0xfffffffd) // nolint:gomnd
+
+	// lockTimeSequence is the input sequence number
+	// that enables locktime without signalling
+	// replace-by-fee.
+	lockTimeSequence = uint32(0xfffffffe) // nolint:gomnd
+
+	// pubKeyHashScriptType and scriptHashScriptType are
+	// the ScriptPubKey.Type ravend reports for P2PKH and
+	// P2SH scripts.
+	pubKeyHashScriptType = "pubkeyhash"
+	scriptHashScriptType = "scripthash"
+
+	// ecdsaSignatureLength is the length of an
+	// ECDSA signature in the form R || S.
+	ecdsaSignatureLength = 64
 )
 
+// sigHashTypes are the supported values of sighash_type
+// (named as in ravend's signrawtransaction).
+var sigHashTypes = map[string]txscript.SigHashType{
+	"ALL":                 txscript.SigHashAll,
+	"NONE":                txscript.SigHashNone,
+	"SINGLE":              txscript.SigHashSingle,
+	"ALL|ANYONECANPAY":    txscript.SigHashAll | txscript.SigHashAnyOneCanPay,
+	"NONE|ANYONECANPAY":   txscript.SigHashNone | txscript.SigHashAnyOneCanPay,
+	"SINGLE|ANYONECANPAY": txscript.SigHashSingle | txscript.SigHashAnyOneCanPay,
+}
+
 // ConstructionAPIService implements the server.ConstructionAPIServicer interface.
+//
+// Only /construction/metadata and /construction/submit require
+// access to ravend or the indexer. All other endpoints are
+// available in offline mode.
 type ConstructionAPIService struct {
 	config *configuration.Configuration
 	client Client
 	i      Indexer
+
+	// scripts is nil if ScriptPubKeys
+	// should not be cached.
+	scripts *scriptPubKeyCache
+
+	// opTypes are the operation type names
+	// accepted and returned by each endpoint.
+	opTypes *configuration.OperationTypes
+
+	// logger is nil if construction decisions
+	// should not be logged. Private keys and
+	// signatures are never logged.
+	logger Logger
 }
 
 // NewConstructionAPIService creates a new instance of a ConstructionAPIService.
+// If logger is not nil, the coins selected, fees computed, signature hashes
+// and submission results are logged to it at debug level.
 func NewConstructionAPIService(
 	config *configuration.Configuration,
 	client Client,
 	i Indexer,
+	logger Logger,
 ) server.ConstructionAPIServicer {
-	return &ConstructionAPIService{
-		config: config,
-		client: client,
-		i:      i,
+	s := &ConstructionAPIService{
+		config:  config,
+		client:  client,
+		i:       i,
+		opTypes: config.OperationTypes,
+		logger:  logger,
+	}
+
+	if config.ScriptPubKeyCache != nil {
+		s.scripts = newScriptPubKeyCache(config.ScriptPubKeyCache)
+	}
+
+	if s.opTypes == nil {
+		s.opTypes = configuration.DefaultOperationTypes()
+	}
+
+	return s
+}
+
+// RegisterHDVersionBytes registers the configured HDVersionBytes
+// so that extended keys using them can be resolved. It should be
+// called once at startup.
+func RegisterHDVersionBytes(config *configuration.Configuration) error {
+	for _, ids := range config.HDVersionBytes {
+		if err := chaincfg.RegisterHDKeyID(ids[0][:], ids[1][:]); err != nil {
+			return fmt.Errorf("%w: unable to register hd version bytes %x:%x", err, ids[0], ids[1])
+		}
 	}
+
+	return nil
 }
 
 // ConstructionDerive implements the /construction/derive endpoint.
@@ -71,9 +145,47 @@ func (s *ConstructionAPIService) ConstructionDerive(
 	ctx context.Context,
 	request *types.ConstructionDeriveRequest,
 ) (*types.ConstructionDeriveResponse, *types.Error) {
+	var metadata deriveMetadata
+	if err := types.UnmarshalMap(request.Metadata, &metadata); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	var publicKey []byte
+	if request.PublicKey != nil {
+		compressed, err := compressedPublicKey(request.PublicKey)
+		if err != nil {
+			return nil, wrapErr(ErrInvalidPublicKey, err)
+		}
+
+		publicKey = compressed
+	}
+
+	if len(metadata.WIF) > 0 {
+		wifPublicKey, err := s.wifPublicKey(metadata.WIF)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToDerive, err)
+		}
+
+		if len(publicKey) > 0 && !bytes.Equal(publicKey, wifPublicKey) {
+			return nil, wrapErr(
+				ErrUnableToDerive,
+				errors.New("public key does not match the provided wif"),
+			)
+		}
+
+		publicKey = wifPublicKey
+	}
+
+	if len(publicKey) == 0 {
+		return nil, wrapErr(
+			ErrInvalidPublicKey,
+			errors.New("a public key or wif must be provided"),
+		)
+	}
+
 	addr, err := btcutil.NewAddressWitnessPubKeyHash(
-		btcutil.Hash160(request.PublicKey.Bytes),
-		nil,//s.config.Params,
+		btcutil.Hash160(publicKey),
+		s.config.Params.BtcdParams(),
 	)
 	if err != nil {
 		return nil, wrapErr(ErrUnableToDerive, err)
@@ -86,16 +198,400 @@ func (s *ConstructionAPIService) ConstructionDerive(
 	}, nil
 }
 
+// compressedPublicKey returns the compressed serialization of
+// publicKey, which must be a compressed (33 byte) or uncompressed
+// (65 byte) secp256k1 public key. P2WPKH addresses are only
+// spendable with compressed public keys.
+func compressedPublicKey(publicKey *types.PublicKey) ([]byte, error) {
+	if publicKey.CurveType != types.Secp256k1 {
+		return nil, fmt.Errorf(
+			"curve type %s is not supported (must be %s)",
+			publicKey.CurveType,
+			types.Secp256k1,
+		)
+	}
+
+	if len(publicKey.Bytes) != btcec.PubKeyBytesLenCompressed &&
+		len(publicKey.Bytes) != btcec.PubKeyBytesLenUncompressed {
+		return nil, fmt.Errorf(
+			"public key is %d bytes (must be %d compressed or %d uncompressed)",
+			len(publicKey.Bytes),
+			btcec.PubKeyBytesLenCompressed,
+			btcec.PubKeyBytesLenUncompressed,
+		)
+	}
+
+	key, err := btcec.ParsePubKey(publicKey.Bytes, btcec.S256())
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse public key", err)
+	}
+
+	return key.SerializeCompressed(), nil
+}
+
+// wifPublicKey returns the compressed public key of
+// a WIF-encoded private key for the configured network.
+func (s *ConstructionAPIService) wifPublicKey(encoded string) ([]byte, error) {
+	wif, err := btcutil.DecodeWIF(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode wif", err)
+	}
+
+	if !wif.IsForNet(s.config.Params.BtcdParams()) {
+		return nil, fmt.Errorf("wif is not for %s", s.config.Params.Name)
+	}
+
+	return wif.PrivKey.PubKey().SerializeCompressed(), nil
+}
+
+// addressType returns the change_address_type
+// corresponding to an encoded address.
+func (s *ConstructionAPIService) addressType(address string) (string, error) {
+	addr, err := btcutil.DecodeAddress(address, s.config.Params.BtcdParams())
+	if err != nil {
+		return "", fmt.Errorf("%w unable to decode address %s", err, address)
+	}
+
+	switch addr.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return LegacyAddressType, nil
+	case *btcutil.AddressWitnessPubKeyHash:
+		return Bech32AddressType, nil
+	default:
+		return "", fmt.Errorf("address %s is not a P2PKH or P2WPKH address", address)
+	}
+}
+
+// validateChangeAddressType ensures the change output (the
+// last output operation) pays to an address of changeAddressType.
+func (s *ConstructionAPIService) validateChangeAddressType(
+	operations []*types.Operation,
+	changeAddressType string,
+) error {
+	if changeAddressType != LegacyAddressType && changeAddressType != Bech32AddressType {
+		return fmt.Errorf("%s is not a valid change_address_type", changeAddressType)
+	}
+
+	var change *types.Operation
+	for _, operation := range operations {
+		if operation.Type == s.opTypes.Output {
+			change = operation
+		}
+	}
+
+	if change == nil {
+		return errors.New("change_address_type provided without any outputs")
+	}
+
+	addressType, err := s.addressType(change.Account.Address)
+	if err != nil {
+		return err
+	}
+
+	if addressType != changeAddressType {
+		return fmt.Errorf(
+			"change address %s is %s but expected %s",
+			change.Account.Address,
+			addressType,
+			changeAddressType,
+		)
+	}
+
+	return nil
+}
+
+// validateCurrencies returns an error if the currency of
+// any operation does not exactly match the currency it
+// moves. Any operation in a currency other than the
+// configured currency moves the asset it names, so its
+// symbol must be a valid asset name. Assets are always
+// tracked in Satoshis (see ravencoin.AssetCurrency), so
+// ISSUE_ASSET operations only check the units in their
+// metadata.
+func (s *ConstructionAPIService) validateCurrencies(operations []*types.Operation) error {
+	for _, op := range operations {
+		if op.Amount == nil || op.Amount.Currency == nil {
+			continue
+		}
+
+		currency := op.Amount.Currency
+		expected := s.config.Currency
+		switch {
+		case op.Type == s.opTypes.IssueAsset:
+			var metadata issueAssetMetadata
+			if err := types.UnmarshalMap(op.Metadata, &metadata); err != nil {
+				return fmt.Errorf("%w: unable to parse issuance metadata", err)
+			}
+
+			if metadata.Units < 0 || metadata.Units > ravencoin.MaxAssetUnits {
+				return fmt.Errorf(
+					"operation %d issues %s with invalid units %d",
+					op.OperationIdentifier.Index,
+					currency.Symbol,
+					metadata.Units,
+				)
+			}
+
+			expected = ravencoin.AssetCurrency(currency.Symbol, ravencoin.MaxAssetUnits)
+		case currency.Symbol != expected.Symbol && s.movesAssets(op.Type):
+			// Casing is checked by validateAssetNames.
+			name := ravencoin.CanonicalAssetName(currency.Symbol)
+			if _, err := ravencoin.ValidateAssetName(name); err == nil {
+				expected = ravencoin.AssetCurrency(currency.Symbol, ravencoin.MaxAssetUnits)
+			}
+		}
+
+		if currency.Symbol != expected.Symbol || currency.Decimals != expected.Decimals {
+			return fmt.Errorf(
+				"operation %d has currency %s with %d decimals but expected %s with %d decimals",
+				op.OperationIdentifier.Index,
+				currency.Symbol,
+				currency.Decimals,
+				expected.Symbol,
+				expected.Decimals,
+			)
+		}
+	}
+
+	return nil
+}
+
+// movesAssets returns true if operations of opType
+// may move an asset instead of the configured currency.
+func (s *ConstructionAPIService) movesAssets(opType string) bool {
+	switch opType {
+	case s.opTypes.Input, s.opTypes.Output, s.opTypes.ReissueAsset, s.opTypes.BurnAsset:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateAssetBalances returns an error naming the first
+// asset (in operation order) whose quantity spent by the input
+// operations differs from the quantity paid by the output
+// operations. Fees are only paid in RVN, so any difference
+// would be rejected by ravend. Assets created by ISSUE_ASSET
+// and REISSUE_ASSET operations are not considered.
+func (s *ConstructionAPIService) validateAssetBalances(operations []*types.Operation) error {
+	names := []string{}
+	inputs := map[string]*big.Int{}
+	outputs := map[string]*big.Int{}
+	for _, op := range operations {
+		if op.Type != s.opTypes.Input && op.Type != s.opTypes.Output {
+			continue
+		}
+
+		if op.Amount == nil || op.Amount.Currency == nil ||
+			op.Amount.Currency.Symbol == s.config.Currency.Symbol {
+			continue
+		}
+
+		quantity, ok := new(big.Int).SetString(op.Amount.Value, 10)
+		if !ok {
+			return fmt.Errorf(
+				"unable to parse amount %s of operation %d",
+				op.Amount.Value,
+				op.OperationIdentifier.Index,
+			)
+		}
+
+		name := op.Amount.Currency.Symbol
+		if _, ok := inputs[name]; !ok {
+			names = append(names, name)
+			inputs[name] = big.NewInt(0)
+			outputs[name] = big.NewInt(0)
+		}
+
+		// Input amounts are negative.
+		if op.Type == s.opTypes.Input {
+			inputs[name].Sub(inputs[name], quantity)
+		} else {
+			outputs[name].Add(outputs[name], quantity)
+		}
+	}
+
+	for _, name := range names {
+		if inputs[name].Cmp(outputs[name]) != 0 {
+			return fmt.Errorf(
+				"asset %s is unbalanced: inputs of %s but outputs of %s",
+				name,
+				inputs[name].String(),
+				outputs[name].String(),
+			)
+		}
+	}
+
+	return nil
+}
+
+// validateFunds returns an *insufficientFundsError if the RVN
+// spent by the input operations does not cover the RVN outputs,
+// the burn of any issuance or reissuance, and the fee of a
+// transaction of estimatedSize vbytes at the minimum fee rate.
+func (s *ConstructionAPIService) validateFunds(
+	operations []*types.Operation,
+	estimatedSize float64,
+) error {
+	available := int64(0)
+	required := int64(ravencoin.FeeRatePerByte(ravencoin.MinFeeRate) * estimatedSize)
+	for _, op := range operations {
+		switch op.Type {
+		case s.opTypes.Input, s.opTypes.Output:
+			if op.Amount == nil || op.Amount.Currency == nil ||
+				op.Amount.Currency.Symbol != s.config.Currency.Symbol {
+				continue
+			}
+
+			value, err := strconv.ParseInt(op.Amount.Value, 10, 64)
+			if err != nil {
+				return fmt.Errorf(
+					"%w: unable to parse amount of operation %d",
+					err,
+					op.OperationIdentifier.Index,
+				)
+			}
+
+			if op.Type == s.opTypes.Input {
+				available -= value
+			} else {
+				required += value
+			}
+		case s.opTypes.IssueAsset:
+			_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnIssueAsset)
+			required += burnAmount
+		case s.opTypes.ReissueAsset:
+			_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnReissueAsset)
+			required += burnAmount
+		}
+	}
+
+	if available < required {
+		return &insufficientFundsError{
+			required:  required,
+			available: available,
+		}
+	}
+
+	return nil
+}
+
+// insufficientFundsErr returns ErrInsufficientFunds with
+// the required and available Satoshis of err added to its
+// details.
+func insufficientFundsErr(err *insufficientFundsError) *types.Error {
+	rErr := wrapErr(ErrInsufficientFunds, err)
+	rErr.Details["required"] = strconv.FormatInt(err.required, 10)
+	rErr.Details["available"] = strconv.FormatInt(err.available, 10)
+
+	return rErr
+}
+
+// coinIdentifiers returns the identifiers of coins.
+func coinIdentifiers(coins []*types.Coin) []string {
+	identifiers := make([]string, len(coins))
+	for i, coin := range coins {
+		identifiers[i] = coin.CoinIdentifier.Identifier
+	}
+
+	return identifiers
+}
+
+// validateAssetNames returns an error if any operation
+// references an asset name that is not in its canonical
+// casing, issues an asset that is not a valid root asset
+// or reissues an asset that is not a valid root or sub
+// asset. Asset names are case-sensitive, so a lowercase
+// variant refers to an asset that does not exist.
+func (s *ConstructionAPIService) validateAssetNames(operations []*types.Operation) error {
+	for _, op := range operations {
+		if op.Type != s.opTypes.ReissueAsset {
+			continue
+		}
+
+		metadata, _, err := parseReissueAssetMetadata(op)
+		if err != nil {
+			return err
+		}
+
+		nameType, err := ravencoin.ValidateAssetName(metadata.AssetName)
+		if err != nil {
+			return err
+		}
+
+		if nameType != ravencoin.AssetNameTypeRoot && nameType != ravencoin.AssetNameTypeSub {
+			return fmt.Errorf(
+				"%w: %s is a %s asset but only root and sub assets can be reissued",
+				ravencoin.ErrInvalidAssetName,
+				metadata.AssetName,
+				nameType,
+			)
+		}
+	}
+
+	for _, op := range operations {
+		if op.Type != s.opTypes.IssueAsset || op.Amount == nil || op.Amount.Currency == nil {
+			continue
+		}
+
+		symbol := op.Amount.Currency.Symbol
+		nameType, err := ravencoin.ValidateAssetName(symbol)
+		if err != nil {
+			return err
+		}
+
+		if nameType != ravencoin.AssetNameTypeRoot {
+			return fmt.Errorf(
+				"%w: %s is a %s asset but only root assets can be issued",
+				ravencoin.ErrInvalidAssetName,
+				symbol,
+				nameType,
+			)
+		}
+	}
+
+	if s.config.AllowNonCanonicalAssetNames {
+		return nil
+	}
+
+	for _, op := range operations {
+		if op.Amount == nil || op.Amount.Currency == nil {
+			continue
+		}
+
+		symbol := op.Amount.Currency.Symbol
+		if symbol == s.config.Currency.Symbol {
+			continue
+		}
+
+		if canonical := ravencoin.CanonicalAssetName(symbol); canonical != symbol {
+			return fmt.Errorf("asset name %s is not canonical (expected %s)", symbol, canonical)
+		}
+	}
+
+	return nil
+}
+
+// validateLockTime returns an error if lockTime
+// does not fit in a uint32.
+func validateLockTime(lockTime int64) error {
+	if lockTime < 0 || lockTime > math.MaxUint32 {
+		return fmt.Errorf("locktime %d does not fit in a uint32", lockTime)
+	}
+
+	return nil
+}
+
 // estimateSize returns the estimated size of a transaction in vBytes.
 func (s *ConstructionAPIService) estimateSize(operations []*types.Operation) float64 {
 	size := ravencoin.TransactionOverhead
 	for _, operation := range operations {
 		switch operation.Type {
-		case ravencoin.InputOpType:
-			size += ravencoin.InputSize
-		case ravencoin.OutputOpType:
+		case s.opTypes.Input:
+			size += s.addressInputVSize(operation.Account.Address)
+		case s.opTypes.Output:
 			size += ravencoin.OutputOverhead
-			addr, err := btcutil.DecodeAddress(operation.Account.Address, nil)//s.config.Params)
+			addr, err := btcutil.DecodeAddress(operation.Account.Address, s.config.Params.BtcdParams())
 			if err != nil {
 				size += ravencoin.P2PKHScriptPubkeySize
 				continue
@@ -108,22 +604,115 @@ func (s *ConstructionAPIService) estimateSize(operations []*types.Operation) flo
 			}
 
 			size += len(script)
+		case s.opTypes.IssueAsset:
+			outputs, err := s.issuanceOutputs(operation)
+			if err != nil {
+				continue
+			}
+
+			for _, output := range outputs {
+				size += output.SerializeSize()
+			}
+		case s.opTypes.ReissueAsset:
+			// The owner token is spent by an
+			// additional input.
+			size += s.addressInputVSize(operation.Account.Address)
+			outputs, err := s.reissuanceOutputs(operation)
+			if err != nil {
+				continue
+			}
+
+			for _, output := range outputs {
+				size += output.SerializeSize()
+			}
+		case s.opTypes.BurnAsset:
+			size += s.addressInputVSize(operation.Account.Address)
+			output, err := s.burnOutput(operation)
+			if err != nil {
+				continue
+			}
+
+			size += output.SerializeSize()
 		}
 	}
 
 	return float64(size)
 }
 
+// addressInputVSize returns the estimated size (in vBytes)
+// of an input spending a coin owned by address.
+func (s *ConstructionAPIService) addressInputVSize(address string) int {
+	// Legacy inputs carry their signature in the
+	// scriptSig instead of the witness, so they are
+	// much larger.
+	addressType, err := s.addressType(address)
+	if err == nil && addressType == LegacyAddressType {
+		return ravencoin.LegacyInputSize
+	}
+
+	return ravencoin.InputSize
+}
+
+// inputVSize returns the estimated size (in vBytes) of an
+// input spending script. P2SH scripts are assumed to be
+// P2SH-P2WPKH and scripts carrying an asset are assumed
+// to be P2PKH.
+func inputVSize(script *ravencoin.ScriptPubKey) int {
+	switch script.Type {
+	case pubKeyHashScriptType,
+		ravencoin.NewAssetType,
+		ravencoin.TransferAssetType,
+		ravencoin.ReissueAssetType:
+		return ravencoin.LegacyInputSize
+	case scriptHashScriptType:
+		return ravencoin.NestedInputSize
+	default:
+		return ravencoin.InputSize
+	}
+}
+
+// estimateVSize returns the estimated size (in vBytes) of a
+// transaction spending inputs to numOutputs P2PKH outputs.
+func estimateVSize(inputs []*ravencoin.ScriptPubKey, numOutputs int) int {
+	size := ravencoin.TransactionOverhead
+	for _, input := range inputs {
+		size += inputVSize(input)
+	}
+
+	return size + numOutputs*(ravencoin.OutputOverhead+ravencoin.P2PKHScriptPubkeySize)
+}
+
 // ConstructionPreprocess implements the /construction/preprocess
 // endpoint.
 func (s *ConstructionAPIService) ConstructionPreprocess(
 	ctx context.Context,
 	request *types.ConstructionPreprocessRequest,
 ) (*types.ConstructionPreprocessResponse, *types.Error) {
+	var metadata preprocessMetadata
+	if err := types.UnmarshalMap(request.Metadata, &metadata); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	if err := validateLockTime(metadata.LockTime); err != nil {
+		return nil, wrapErr(ErrInvalidLockTime, err)
+	}
+
+	if err := s.validateCurrencies(request.Operations); err != nil {
+		return nil, wrapErr(ErrInvalidCurrency, err)
+	}
+
+	if err := s.validateAssetNames(request.Operations); err != nil {
+		return nil, wrapErr(ErrInvalidAssetName, err)
+	}
+
+	if len(metadata.AvailableCoins) > 0 {
+		return s.preprocessCoinSelection(request, &metadata)
+	}
+
 	descriptions := &parser.Descriptions{
 		OperationDescriptions: []*parser.OperationDescription{
 			{
-				Type: ravencoin.InputOpType,
+				Type: s.opTypes.Input,
 				Account: &parser.AccountDescription{
 					Exists: true,
 				},
@@ -143,6 +732,15 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 		return nil, wrapErr(ErrUnclearIntent, err)
 	}
 
+	if len(metadata.ChangeAddressType) > 0 {
+		if err := s.validateChangeAddressType(
+			request.Operations,
+			metadata.ChangeAddressType,
+		); err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+	}
+
 	coins := make([]*types.Coin, len(matches[0].Operations))
 	for i, input := range matches[0].Operations {
 		if input.CoinChange == nil {
@@ -155,10 +753,56 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 		}
 	}
 
+	// The coins burned by any BURN_ASSET operations
+	// are spent by inputs following those of RVN.
+	burnedCoins, err := s.burnedCoins(request.Operations)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+	coins = append(coins, burnedCoins...)
+
+	// The owner token spent by a reissuance is
+	// the last input.
+	for _, op := range request.Operations {
+		if op.Type != s.opTypes.ReissueAsset {
+			continue
+		}
+
+		coin, err := reissuanceOwnerCoin(op)
+		if err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		coins = append(coins, coin)
+	}
+
+	if s.logger != nil {
+		s.logger.Debugw(
+			"coins provided",
+			"coins", coinIdentifiers(coins),
+		)
+	}
+
+	estimatedSize := s.estimateSize(request.Operations)
+	if err := s.validateFunds(request.Operations, estimatedSize); err != nil {
+		var fundsErr *insufficientFundsError
+		if errors.As(err, &fundsErr) {
+			return nil, insufficientFundsErr(fundsErr)
+		}
+
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+
 	options, err := types.MarshalMap(&preprocessOptions{
 		Coins:         coins,
-		EstimatedSize: s.estimateSize(request.Operations),
+		EstimatedSize: estimatedSize,
 		FeeMultiplier: request.SuggestedFeeMultiplier,
+		Replaceable:   metadata.Replaceable,
+		LockTime:      metadata.LockTime,
+
+		ShuffleOutputs: metadata.ShuffleOutputs,
+		ShuffleNonce:   metadata.ShuffleNonce,
+		BurnedCoins:    burnedCoins,
 	})
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
@@ -169,26 +813,340 @@ func (s *ConstructionAPIService) ConstructionPreprocess(
 	}, nil
 }
 
-// ConstructionMetadata implements the /construction/metadata endpoint.
-func (s *ConstructionAPIService) ConstructionMetadata(
-	ctx context.Context,
-	request *types.ConstructionMetadataRequest,
-) (*types.ConstructionMetadataResponse, *types.Error) {
-	if s.config.Mode != configuration.Online {
-		return nil, wrapErr(ErrUnavailableOffline, nil)
-	}
-
-	var options preprocessOptions
-	if err := types.UnmarshalMap(request.Options, &options); err != nil {
-		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
-	}
-
-	// Determine feePerKB and ensure it is not below the minimum fee
-	// relay rate.
-	feePerKB, err := s.client.SuggestedFeeRate(ctx, defaultConfirmationTarget)
-	if err != nil {
-		return nil, wrapErr(ErrCouldNotGetFeeRate, err)
-	}
+// preprocessCoinSelection handles /construction/preprocess requests
+// that provide available_coins instead of input operations by selecting
+// the coins to spend on behalf of the caller.
+func (s *ConstructionAPIService) preprocessCoinSelection(
+	request *types.ConstructionPreprocessRequest,
+	metadata *preprocessMetadata,
+) (*types.ConstructionPreprocessResponse, *types.Error) {
+	descriptions := &parser.Descriptions{
+		OperationDescriptions: []*parser.OperationDescription{
+			{
+				Type: s.opTypes.Output,
+				Account: &parser.AccountDescription{
+					Exists: true,
+				},
+				Amount: &parser.AmountDescription{
+					Exists:   true,
+					Sign:     parser.PositiveAmountSign,
+					Currency: s.config.Currency,
+				},
+				AllowRepeats: true,
+			},
+			s.issueAssetDescription(),
+			s.reissueAssetDescription(),
+			s.burnAssetDescription(),
+		},
+		ErrUnmatched: true,
+	}
+
+	matches, err := parser.MatchOperations(descriptions, request.Operations)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+
+	if matches[1] != nil && matches[2] != nil {
+		return nil, wrapErr(
+			ErrUnclearIntent,
+			errors.New("an asset cannot be issued and reissued in the same transaction"),
+		)
+	}
+
+	target := int64(0)
+	for _, amount := range matches[0].Amounts {
+		target += amount.Int64()
+	}
+
+	// Issuing or reissuing an asset requires paying
+	// the burn from the selected coins.
+	if matches[1] != nil {
+		_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnIssueAsset)
+		target += burnAmount
+	}
+	if matches[2] != nil {
+		_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnReissueAsset)
+		target += burnAmount
+	}
+
+	// Immature coinbase coins cannot be spent yet.
+	available := []*availableCoin{}
+	for _, coin := range metadata.AvailableCoins {
+		if coin.Coin == nil || coin.Amount == nil || coin.CoinIdentifier == nil {
+			return nil, wrapErr(ErrInvalidCoin, errors.New("available coin is missing its identifier or amount"))
+		}
+
+		// The address of a coin determines
+		// the size of the input spending it.
+		if _, err := btcutil.DecodeAddress(coin.Address, s.config.Params.BtcdParams()); err != nil {
+			return nil, wrapErr(ErrInvalidCoin, fmt.Errorf(
+				"%w: coin %s has invalid address %q",
+				err,
+				coin.CoinIdentifier.Identifier,
+				coin.Address,
+			))
+		}
+
+		if coin.Immature {
+			continue
+		}
+
+		if types.Hash(coin.Amount.Currency) != types.Hash(s.config.Currency) {
+			return nil, wrapErr(ErrInvalidCoin, fmt.Errorf(
+				"coin %s currency %s is not %s",
+				coin.CoinIdentifier.Identifier,
+				types.PrintStruct(coin.Amount.Currency),
+				types.PrintStruct(s.config.Currency),
+			))
+		}
+
+		available = append(available, &availableCoin{
+			coin:      coin.Coin,
+			inputSize: s.addressInputVSize(coin.Address),
+		})
+	}
+
+	changeSize := ravencoin.OutputOverhead + ravencoin.P2WPKHScriptPubkeySize
+	switch metadata.ChangeAddressType {
+	case "", Bech32AddressType:
+	case LegacyAddressType:
+		changeSize = ravencoin.OutputOverhead + ravencoin.P2PKHScriptPubkeySize
+	default:
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+			"%s is not a valid change_address_type",
+			metadata.ChangeAddressType,
+		))
+	}
+
+	feeRate := metadata.FeeRate
+	if feeRate < 0 {
+		return nil, wrapErr(ErrUnclearIntent, fmt.Errorf("fee_rate %f is negative", feeRate))
+	}
+	if feeRate == 0 {
+		feeRate = ravencoin.FeeRatePerByte(ravencoin.MinFeeRate)
+	}
+
+	baseSize := int(s.estimateSize(request.Operations))
+	selection, err := selectCoins(
+		available,
+		target,
+		feeRate,
+		baseSize,
+		changeSize,
+	)
+	if err != nil {
+		var fundsErr *insufficientFundsError
+		if errors.As(err, &fundsErr) {
+			return nil, insufficientFundsErr(fundsErr)
+		}
+
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+
+	// Coins are spent by input operations, so their
+	// amounts are negative (as in the input operations
+	// provided when coin selection is not used).
+	coins := make([]*types.Coin, len(selection.coins))
+	for i, coin := range selection.coins {
+		value, err := types.NegateValue(coin.Amount.Value)
+		if err != nil {
+			return nil, wrapErr(ErrInvalidCoin, err)
+		}
+
+		coins[i] = &types.Coin{
+			CoinIdentifier: coin.CoinIdentifier,
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: coin.Amount.Currency,
+			},
+		}
+	}
+
+	estimatedSize := baseSize + selection.inputSize
+
+	// The coins burned by any BURN_ASSET operations
+	// are spent by inputs following those selected.
+	burnedCoins, err := s.burnedCoins(request.Operations)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+	coins = append(coins, burnedCoins...)
+
+	// The owner token spent by a reissuance
+	// is the last input.
+	if matches[2] != nil {
+		coin, err := reissuanceOwnerCoin(matches[2].Operations[0])
+		if err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		coins = append(coins, coin)
+	}
+	var change *types.Amount
+	if selection.change > 0 {
+		estimatedSize += changeSize
+		change = &types.Amount{
+			Value:    strconv.FormatInt(selection.change, 10),
+			Currency: s.config.Currency,
+		}
+	}
+
+	if s.logger != nil {
+		s.logger.Debugw(
+			"coins selected",
+			"coins", coinIdentifiers(coins),
+			"target", target,
+			"change", selection.change,
+		)
+	}
+
+	options, err := types.MarshalMap(&preprocessOptions{
+		Coins:         coins,
+		EstimatedSize: float64(estimatedSize),
+		FeeMultiplier: request.SuggestedFeeMultiplier,
+		Replaceable:   metadata.Replaceable,
+		LockTime:      metadata.LockTime,
+
+		ShuffleOutputs: metadata.ShuffleOutputs,
+		ShuffleNonce:   metadata.ShuffleNonce,
+		Change:         change,
+		BurnedCoins:    burnedCoins,
+	})
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	return &types.ConstructionPreprocessResponse{
+		Options: options,
+	}, nil
+}
+
+// replayDepth returns the number of blocks below the best
+// block that the replay block is selected at. If no
+// ReplayProtectionDepth is configured, the ConfirmationDepth
+// is used.
+func (s *ConstructionAPIService) replayDepth() int64 {
+	if s.config.ReplayProtectionDepth > 0 {
+		return s.config.ReplayProtectionDepth
+	}
+
+	return s.config.ConfirmationDepth
+}
+
+// replayBlock returns the height and hash of the block depth
+// blocks below the node's tip. The tip is fetched by hash so
+// that, once the indexer has stored it, the replay block can
+// be read from the indexer instead of asking ravend for both
+// the tip height and the replay block hash.
+func (s *ConstructionAPIService) replayBlock(
+	ctx context.Context,
+	depth int64,
+) (int64, string, error) {
+	tipHash, err := s.client.GetBestBlockHash(ctx)
+	if err != nil {
+		return -1, "", err
+	}
+
+	var tipIndex int64
+	tip, err := s.i.GetBlockLazy(ctx, &types.PartialBlockIdentifier{Hash: &tipHash})
+	indexed := err == nil
+	if indexed {
+		tipIndex = tip.Block.BlockIdentifier.Index
+	} else {
+		// The indexer has not stored the tip yet, so its
+		// height is fetched by hash. Asking ravend for its
+		// best height could return a different tip.
+		tipIndex, err = s.client.GetBlockHeight(ctx, tipHash)
+		if err != nil {
+			return -1, "", err
+		}
+	}
+
+	height := tipIndex - depth
+	if height < 0 {
+		height = 0
+	}
+
+	// The indexer only stores blocks on its canonical chain,
+	// so when it has the node's tip it also has the node's
+	// replay block.
+	if indexed {
+		replay, err := s.i.GetBlockLazy(ctx, &types.PartialBlockIdentifier{Index: &height})
+		if err == nil {
+			return height, replay.Block.BlockIdentifier.Hash, nil
+		}
+	}
+
+	hash, err := s.client.GetHashFromIndex(ctx, height)
+	if err != nil {
+		return -1, "", err
+	}
+
+	return height, hash, nil
+}
+
+// ConstructionMetadata implements the /construction/metadata endpoint.
+func (s *ConstructionAPIService) ConstructionMetadata(
+	ctx context.Context,
+	request *types.ConstructionMetadataRequest,
+) (*types.ConstructionMetadataResponse, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, errUnavailableOffline("/construction/metadata")
+	}
+
+	var options preprocessOptions
+	if err := types.UnmarshalMap(request.Options, &options); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	var replayBlockHeight int64
+	var replayBlockHash string
+	replayDepth := s.replayDepth()
+	if replayDepth > 0 {
+		var err error
+		replayBlockHeight, replayBlockHash, err = s.replayBlock(ctx, replayDepth)
+		if err != nil {
+			return nil, wrapErr(ErrRavend, err)
+		}
+	}
+
+	// The cache also returns the indexed amount of each coin,
+	// so that a cached coin is checked like an uncached one.
+	var scripts []*ravencoin.ScriptPubKey
+	var amounts []*types.Amount
+	var err error
+	if s.scripts != nil {
+		scripts, amounts, err = s.scripts.GetScriptPubKeys(ctx, s.i, options.Coins)
+	} else {
+		scripts, err = s.i.GetScriptPubKeys(ctx, options.Coins)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrScriptPubKeysMissing, err)
+	}
+
+	// An understated input would hide the fee, so the amount
+	// of each coin is checked unless explicitly allowed.
+	if !s.config.AllowUnverifiedInputAmounts {
+		if err := s.validateCoinAmounts(ctx, options.Coins, amounts); err != nil {
+			return nil, wrapErr(ErrInvalidCoin, err)
+		}
+	}
+
+	// A coin must be burned in full, or ravend would
+	// reject the transaction as unbalanced.
+	if len(options.BurnedCoins) > 0 {
+		if err := s.validateCoinAmounts(ctx, options.BurnedCoins, nil); err != nil {
+			return nil, wrapErr(ErrInvalidCoin, err)
+		}
+	}
+
+	// Determine feePerKB and ensure it is not below the minimum fee
+	// relay rate.
+	nodeFeePerKB, err := s.client.SuggestedFeeRatePerKB(ctx, defaultConfirmationTarget)
+	if err != nil {
+		return nil, wrapErr(ErrCouldNotGetFeeRate, err)
+	}
+	feePerKB := nodeFeePerKB
 	if options.FeeMultiplier != nil {
 		feePerKB *= *options.FeeMultiplier
 	}
@@ -196,20 +1154,89 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 		feePerKB = ravencoin.MinFeeRate
 	}
 
-	// Calculated the estimated fee in Satoshis
-	satoshisPerB := (feePerKB * float64(ravencoin.SatoshisInRavencoin)) / bytesInKb
-	estimatedFee := satoshisPerB * options.EstimatedSize
+	// Preprocess estimated the size of each input from its
+	// address. Now that the scripts being spent are known,
+	// re-estimate each input from its script type.
+	estimatedSize := options.EstimatedSize
+	for _, script := range scripts {
+		estimatedSize += float64(inputVSize(script))
+		if len(script.Addresses) == 1 {
+			estimatedSize -= float64(s.addressInputVSize(script.Addresses[0]))
+		} else {
+			estimatedSize -= float64(ravencoin.InputSize)
+		}
+	}
+
+	// Calculated the estimated fee in Satoshis. The fee is
+	// always paid in RVN (the configured currency), even
+	// when some of the coins spent carry an asset.
+	estimatedFee := ravencoin.FeeRatePerByte(feePerKB) * estimatedSize
+
+	// Refuse to suggest a fee above the configured cap so that
+	// a runaway fee multiplier (or a misreporting node) cannot
+	// drain the wallet paying it.
+	if s.config.MaxFeeAmount > 0 && int64(estimatedFee) > s.config.MaxFeeAmount {
+		return nil, wrapErr(
+			ErrFeeTooHigh,
+			fmt.Errorf(
+				"suggested fee %d exceeds max fee amount %d",
+				int64(estimatedFee),
+				s.config.MaxFeeAmount,
+			),
+		)
+	}
+
 	suggestedFee := &types.Amount{
 		Value:    fmt.Sprintf("%d", int64(estimatedFee)),
 		Currency: s.config.Currency,
 	}
 
-	scripts, err := s.i.GetScriptPubKeys(ctx, options.Coins)
-	if err != nil {
-		return nil, wrapErr(ErrScriptPubKeysMissing, err)
+	if s.logger != nil {
+		s.logger.Debugw(
+			"fee computed",
+			"fee", suggestedFee.Value,
+			"fee_rate", satoshisPerKB(feePerKB),
+			"node_fee_rate", satoshisPerKB(nodeFeePerKB),
+			"estimated_size", estimatedSize,
+		)
+	}
+
+	// Ensure the replay block was not reorged out while
+	// we were fetching metadata.
+	if replayDepth > 0 {
+		hash, err := s.client.GetHashFromIndex(ctx, replayBlockHeight)
+		if err != nil {
+			return nil, wrapErr(ErrRavend, err)
+		}
+
+		if hash != replayBlockHash {
+			return nil, wrapErr(ErrReplayBlockChanged, fmt.Errorf(
+				"replay block %d changed from %s to %s",
+				replayBlockHeight,
+				replayBlockHash,
+				hash,
+			))
+		}
 	}
 
-	metadata, err := types.MarshalMap(&constructionMetadata{ScriptPubKeys: scripts})
+	metadata, err := types.MarshalMap(&constructionMetadata{
+		ScriptPubKeys: scripts,
+		Replaceable:   options.Replaceable,
+		LockTime:      options.LockTime,
+
+		ShuffleOutputs: options.ShuffleOutputs,
+		ShuffleNonce:   options.ShuffleNonce,
+
+		ReplayBlockHeight: replayBlockHeight,
+		ReplayBlockHash:   replayBlockHash,
+
+		Fee: &feeBreakdown{
+			FeeRate:          satoshisPerKB(feePerKB),
+			NodeFeeRate:      satoshisPerKB(nodeFeePerKB),
+			EstimatedSize:    estimatedSize,
+			EffectiveFeeRate: effectiveFeeRate(int64(estimatedFee), estimatedSize),
+		},
+	})
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
@@ -220,38 +1247,109 @@ func (s *ConstructionAPIService) ConstructionMetadata(
 	}, nil
 }
 
+// validateCoinAmounts returns an error if the amount of any
+// of coins (spent by an input operation, so negative) does
+// not match the indexed value of the output that created it.
+// If amounts is nil, the indexed values are fetched.
+func (s *ConstructionAPIService) validateCoinAmounts(
+	ctx context.Context,
+	coins []*types.Coin,
+	amounts []*types.Amount,
+) error {
+	if amounts == nil {
+		var err error
+		amounts, err = s.i.GetCoinAmounts(ctx, coins)
+		if err != nil {
+			return err
+		}
+	}
+
+	for j, coin := range coins {
+		difference, err := types.AddValues(amounts[j].Value, coin.Amount.Value)
+		if err != nil {
+			return fmt.Errorf("%w: unable to compare amount of coin %s", err, coin.CoinIdentifier.Identifier)
+		}
+
+		if difference != "0" {
+			return fmt.Errorf(
+				"coin %s is worth %s but was provided with amount %s",
+				coin.CoinIdentifier.Identifier,
+				amounts[j].Value,
+				coin.Amount.Value,
+			)
+		}
+	}
+
+	return nil
+}
+
+// satoshisPerKB converts a fee rate in RVN
+// per kB to Satoshis per kB.
+func satoshisPerKB(feePerKB float64) int64 {
+	return int64(math.Round(feePerKB * float64(ravencoin.SatoshisInRavencoin)))
+}
+
+// effectiveFeeRate returns fee (in Satoshis) divided by
+// estimatedSize (in vbytes), rounded to two decimals.
+// Because fee is the fee actually suggested (after the
+// minimum fee rate is applied), this is the rate a
+// transaction paying it will have.
+func effectiveFeeRate(fee int64, estimatedSize float64) float64 {
+	if estimatedSize <= 0 {
+		return 0
+	}
+
+	return math.Round(float64(fee)/estimatedSize*100) / 100 // nolint:gomnd
+}
+
 // ConstructionPayloads implements the /construction/payloads endpoint.
 func (s *ConstructionAPIService) ConstructionPayloads(
 	ctx context.Context,
 	request *types.ConstructionPayloadsRequest,
 ) (*types.ConstructionPayloadsResponse, *types.Error) {
+	if err := s.validateCurrencies(request.Operations); err != nil {
+		return nil, wrapErr(ErrInvalidCurrency, err)
+	}
+
+	if err := s.validateAssetNames(request.Operations); err != nil {
+		return nil, wrapErr(ErrInvalidAssetName, err)
+	}
+
+	if err := s.validateAssetBalances(request.Operations); err != nil {
+		return nil, wrapErr(ErrUnbalancedTransaction, err)
+	}
+
 	descriptions := &parser.Descriptions{
 		OperationDescriptions: []*parser.OperationDescription{
+			// Inputs and outputs may transfer an asset
+			// (see validateCurrencies and
+			// validateAssetBalances).
 			{
-				Type: ravencoin.InputOpType,
+				Type: s.opTypes.Input,
 				Account: &parser.AccountDescription{
 					Exists: true,
 				},
 				Amount: &parser.AmountDescription{
-					Exists:   true,
-					Sign:     parser.NegativeAmountSign,
-					Currency: s.config.Currency,
+					Exists: true,
+					Sign:   parser.NegativeAmountSign,
 				},
 				AllowRepeats: true,
 				CoinAction:   types.CoinSpent,
 			},
 			{
-				Type: ravencoin.OutputOpType,
+				Type: s.opTypes.Output,
 				Account: &parser.AccountDescription{
 					Exists: true,
 				},
 				Amount: &parser.AmountDescription{
-					Exists:   true,
-					Sign:     parser.PositiveAmountSign,
-					Currency: s.config.Currency,
+					Exists: true,
+					Sign:   parser.PositiveAmountSign,
 				},
 				AllowRepeats: true,
 			},
+			s.issueAssetDescription(),
+			s.reissueAssetDescription(),
+			s.burnAssetDescription(),
 		},
 		ErrUnmatched: true,
 	}
@@ -261,8 +1359,61 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 		return nil, wrapErr(ErrUnclearIntent, err)
 	}
 
+	if matches[2] != nil && matches[3] != nil {
+		return nil, wrapErr(
+			ErrUnclearIntent,
+			errors.New("an asset cannot be issued and reissued in the same transaction"),
+		)
+	}
+
+	// Coins carrying an asset are worth no RVN.
+	inputRVN := s.rvnAmounts(matches[0].Operations, matches[0].Amounts)
+	outputRVN := s.rvnAmounts(matches[1].Operations, matches[1].Amounts)
+
+	// The coins burned follow the RVN inputs and
+	// the owner token spent by a reissuance is the
+	// last input. They carry no RVN.
+	inputs := matches[0].Operations
+	inputAmountValues := inputRVN
+	if matches[4] != nil {
+		inputs = append(append([]*types.Operation{}, inputs...), matches[4].Operations...)
+		inputAmountValues = append([]*big.Int{}, inputAmountValues...)
+		for range matches[4].Operations {
+			inputAmountValues = append(inputAmountValues, big.NewInt(0))
+		}
+	}
+	if matches[3] != nil {
+		inputs = append(append([]*types.Operation{}, inputs...), matches[3].Operations[0])
+		inputAmountValues = append(append([]*big.Int{}, inputAmountValues...), big.NewInt(0))
+	}
+
+	var metadata constructionMetadata
+	if err := types.UnmarshalMap(request.Metadata, &metadata); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	if err := validateLockTime(metadata.LockTime); err != nil {
+		return nil, wrapErr(ErrInvalidLockTime, err)
+	}
+
+	hashType, err := parseSigHashType(metadata.SigHashType)
+	if err != nil {
+		return nil, wrapErr(ErrUnclearIntent, err)
+	}
+
+	// Locktime is only enforced if at least one input
+	// has a non-final sequence number.
+	sequence := wire.MaxTxInSequenceNum
+	if metadata.LockTime != 0 {
+		sequence = lockTimeSequence
+	}
+	if metadata.Replaceable {
+		sequence = replaceableSequence
+	}
+
 	tx := wire.NewMsgTx(wire.TxVersion)
-	for _, input := range matches[0].Operations {
+	tx.LockTime = uint32(metadata.LockTime)
+	for _, input := range inputs {
 		if input.CoinChange == nil {
 			return nil, wrapErr(ErrUnclearIntent, errors.New("CoinChange cannot be nil"))
 		}
@@ -278,12 +1429,13 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 				Index: index,
 			},
 			SignatureScript: nil,
-			Sequence:        wire.MaxTxInSequenceNum,
+			Sequence:        sequence,
 		})
 	}
 
+	outputs := make([]*wire.TxOut, len(matches[1].Operations))
 	for i, output := range matches[1].Operations {
-		addr, err := btcutil.DecodeAddress(output.Account.Address, nil)//s.config.Params)
+		addr, err := btcutil.DecodeAddress(output.Account.Address, s.config.Params.BtcdParams())
 		if err != nil {
 			return nil, wrapErr(ErrUnableToDecodeAddress, fmt.Errorf(
 				"%w unable to decode address %s",
@@ -301,68 +1453,152 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 			)
 		}
 
-		tx.AddTxOut(&wire.TxOut{
-			Value:    matches[1].Amounts[i].Int64(),
+		var transfer transferAssetMetadata
+		if err := types.UnmarshalMap(output.Metadata, &transfer); err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+
+		value := matches[1].Amounts[i].Int64()
+		if s.transfersAsset(output) {
+			symbol := output.Amount.Currency.Symbol
+			pkScript, err = ravencoin.TransferAssetMessageScript(
+				pkScript,
+				symbol,
+				value,
+				transfer.Message,
+				transfer.ExpireTime,
+			)
+			if err != nil {
+				return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+					"%w: unable to transfer %s in operation %d",
+					err,
+					symbol,
+					output.OperationIdentifier.Index,
+				))
+			}
+
+			value = 0
+		} else if len(transfer.Message) > 0 {
+			return nil, wrapErr(ErrUnclearIntent, fmt.Errorf(
+				"operation %d attaches a message but does not transfer an asset",
+				output.OperationIdentifier.Index,
+			))
+		}
+
+		outputs[i] = &wire.TxOut{
+			Value:    value,
 			PkScript: pkScript,
-		})
+		}
 	}
 
-	// Create Signing Payloads (must be done after entire tx is constructed
-	// or hash will not be correct).
-	inputAmounts := make([]string, len(tx.TxIn))
-	inputAddresses := make([]string, len(tx.TxIn))
-	payloads := make([]*types.SigningPayload, len(tx.TxIn))
-	var metadata constructionMetadata
-	if err := types.UnmarshalMap(request.Metadata, &metadata); err != nil {
-		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	if metadata.ShuffleOutputs {
+		shuffleOutputs(outputs, metadata.ShuffleNonce)
 	}
 
-	for i := range tx.TxIn {
-		address := matches[0].Operations[i].Account.Address
-		script, err := hex.DecodeString(metadata.ScriptPubKeys[i].Hex)
-		if err != nil {
-			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
-		}
+	for _, output := range outputs {
+		tx.AddTxOut(output)
+	}
 
-		class, _, err := ravencoin.ParseSingleAddress(nil /*s.config.Params*/, script)
+	// The burns follow all RVN outputs (including
+	// change), in the order of their inputs.
+	if matches[4] != nil {
+		for _, op := range matches[4].Operations {
+			output, err := s.burnOutput(op)
+			if err != nil {
+				return nil, wrapErr(ErrUnclearIntent, err)
+			}
+
+			tx.AddTxOut(output)
+		}
+	}
+
+	// The issuance outputs follow all RVN outputs
+	// (including change) and burns.
+	if matches[2] != nil {
+		_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnIssueAsset)
+		if err := validateIssuanceFunding(inputRVN, outputRVN, burnAmount); err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		outputs, err := s.issuanceOutputs(matches[2].Operations[0])
 		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToDecodeAddress,
-				fmt.Errorf("%w unable to parse address for utxo %d", err, i),
-			)
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		for _, output := range outputs {
+			tx.AddTxOut(output)
+		}
+	}
+
+	// The reissuance outputs follow all RVN outputs
+	// (including change) and burns.
+	if matches[3] != nil {
+		_, burnAmount := s.config.Params.BurnAddress(chaincfg.BurnReissueAsset)
+		if err := validateIssuanceFunding(inputRVN, outputRVN, burnAmount); err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		outputs, err := s.reissuanceOutputs(matches[3].Operations[0])
+		if err != nil {
+			return nil, wrapErr(ErrUnclearIntent, err)
+		}
+
+		for _, output := range outputs {
+			tx.AddTxOut(output)
+		}
+	}
+
+	// Create Signing Payloads (must be done after entire tx is constructed
+	// or hash will not be correct).
+	inputAmounts := make([]string, len(tx.TxIn))
+	inputAddresses := make([]string, len(tx.TxIn))
+	payloads := make([]*types.SigningPayload, len(tx.TxIn))
+
+	for i := range tx.TxIn {
+		address := inputs[i].Account.Address
+		script, err := hex.DecodeString(metadata.ScriptPubKeys[i].Hex)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
 		}
 
 		inputAddresses[i] = address
-		inputAmounts[i] = matches[0].Amounts[i].String()
-		absAmount := new(big.Int).Abs(matches[0].Amounts[i]).Int64()
+		inputAmounts[i] = inputAmountValues[i].String()
+		absAmount := new(big.Int).Abs(inputAmountValues[i]).Int64()
 
-		switch class {
-		case txscript.WitnessV0PubKeyHashTy:
-			hash, err := txscript.CalcWitnessSigHash(
-				script,
-				txscript.NewTxSigHashes(tx),
-				txscript.SigHashAll,
-				tx,
-				i,
-				absAmount,
-			)
+		// P2SH outputs are spent as P2SH-P2WPKH, so the
+		// payload commits to the nested witness program.
+		if txscript.GetScriptClass(script) == txscript.ScriptHashTy {
+			script, err = nestedWitnessScript(script, request.PublicKeys)
 			if err != nil {
-				return nil, wrapErr(ErrUnableToCalculateSignatureHash, err)
+				return nil, wrapErr(
+					ErrUnableToCalculateSignatureHash,
+					fmt.Errorf("%w: unable to find redeem script of input %d", err, i),
+				)
 			}
+		}
 
-			payloads[i] = &types.SigningPayload{
-				AccountIdentifier: &types.AccountIdentifier{
-					Address: address,
-				},
-				Bytes:         hash,
-				SignatureType: types.Ecdsa,
-			}
-		default:
-			return nil, wrapErr(
-				ErrUnsupportedScriptType,
-				fmt.Errorf("unupported script type: %s", class),
+		hash, hashErr := s.signatureHash(tx, i, script, absAmount, hashType)
+		if hashErr != nil {
+			return nil, hashErr
+		}
+
+		if s.logger != nil {
+			s.logger.Debugw(
+				"signature hash computed",
+				"input", i,
+				"address", address,
+				"sighash_type", hashType,
+				"hash", hex.EncodeToString(hash),
 			)
 		}
+
+		payloads[i] = &types.SigningPayload{
+			AccountIdentifier: &types.AccountIdentifier{
+				Address: address,
+			},
+			Bytes:         hash,
+			SignatureType: types.Ecdsa,
+		}
 	}
 
 	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
@@ -375,6 +1611,8 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 		ScriptPubKeys:  metadata.ScriptPubKeys,
 		InputAmounts:   inputAmounts,
 		InputAddresses: inputAddresses,
+		Fee:            metadata.Fee,
+		SigHashType:    metadata.SigHashType,
 	})
 	if err != nil {
 		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
@@ -386,13 +1624,194 @@ func (s *ConstructionAPIService) ConstructionPayloads(
 	}, nil
 }
 
-func normalizeSignature(signature []byte) []byte {
+// transfersAsset returns true if op moves an asset
+// instead of the configured currency.
+func (s *ConstructionAPIService) transfersAsset(op *types.Operation) bool {
+	return op.Amount != nil && op.Amount.Currency != nil &&
+		op.Amount.Currency.Symbol != s.config.Currency.Symbol
+}
+
+// rvnAmounts returns amounts (those of operations) with
+// the amounts of operations moving an asset replaced by
+// zero, as outputs and coins carrying an asset are worth
+// no RVN.
+func (s *ConstructionAPIService) rvnAmounts(
+	operations []*types.Operation,
+	amounts []*big.Int,
+) []*big.Int {
+	rvn := make([]*big.Int, len(amounts))
+	for i, amount := range amounts {
+		rvn[i] = amount
+		if s.transfersAsset(operations[i]) {
+			rvn[i] = big.NewInt(0)
+		}
+	}
+
+	return rvn
+}
+
+// shuffleOutputs reorders outputs in place so the position
+// of change does not reveal which output it is. The order
+// only depends on nonce, so payloads are reproducible.
+func shuffleOutputs(outputs []*wire.TxOut, nonce int64) {
+	rand.New(rand.NewSource(nonce)).Shuffle(len(outputs), func(i, j int) { // #nosec G404
+		outputs[i], outputs[j] = outputs[j], outputs[i]
+	})
+}
+
+// parseSigHashType returns the txscript.SigHashType
+// named by sigHashType, defaulting to SIGHASH_ALL.
+func parseSigHashType(sigHashType string) (txscript.SigHashType, error) {
+	if len(sigHashType) == 0 {
+		return txscript.SigHashAll, nil
+	}
+
+	hashType, ok := sigHashTypes[sigHashType]
+	if !ok {
+		return 0, fmt.Errorf("%s is not a valid sighash_type", sigHashType)
+	}
+
+	return hashType, nil
+}
+
+// signatureHash returns the hash that must be signed (with
+// hashType) to spend input i of tx, which spends amount
+// Satoshis locked by script.
+func (s *ConstructionAPIService) signatureHash(
+	tx *wire.MsgTx,
+	i int,
+	script []byte,
+	amount int64,
+	hashType txscript.SigHashType,
+) ([]byte, *types.Error) {
+	// Scripts carrying an asset are signed in full
+	// but spent like the script preceding the asset.
+	class, _, err := ravencoin.ParseSingleAddress(
+		s.config.Params.BtcdParams(),
+		ravencoin.AssetScriptBase(script),
+	)
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToDecodeAddress,
+			fmt.Errorf("%w unable to parse address for utxo %d", err, i),
+		)
+	}
+
+	var hash []byte
+	switch class {
+	case txscript.WitnessV0PubKeyHashTy:
+		hash, err = txscript.CalcWitnessSigHash(
+			script,
+			txscript.NewTxSigHashes(tx),
+			hashType,
+			tx,
+			i,
+			amount,
+		)
+	case txscript.PubKeyHashTy, txscript.PubKeyTy:
+		hash, err = ravencoin.CalcSignatureHash(tx, i, script, hashType)
+	default:
+		return nil, wrapErr(
+			ErrUnsupportedScriptType,
+			fmt.Errorf("unupported script type: %s", class),
+		)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrUnableToCalculateSignatureHash, err)
+	}
+
+	return hash, nil
+}
+
+// nestedWitnessScript returns the P2WPKH witness program
+// of the first of publicKeys that script (a P2SH script)
+// pays to when nested in P2SH.
+func nestedWitnessScript(script []byte, publicKeys []*types.PublicKey) ([]byte, error) {
+	for _, publicKey := range publicKeys {
+		if publicKey == nil {
+			continue
+		}
+
+		witnessScript, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_0).
+			AddData(btcutil.Hash160(publicKey.Bytes)).
+			Script()
+		if err != nil {
+			return nil, err
+		}
+
+		scriptHash, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_HASH160).
+			AddData(btcutil.Hash160(witnessScript)).
+			AddOp(txscript.OP_EQUAL).
+			Script()
+		if err != nil {
+			return nil, err
+		}
+
+		if bytes.Equal(scriptHash, script) {
+			return witnessScript, nil
+		}
+	}
+
+	return nil, errors.New("no public key matches the p2sh-p2wpkh script")
+}
+
+// validateSignature returns an error if signature is not an
+// ECDSA signature by a secp256k1 public key. It must be called
+// before the public key of signature is used.
+func validateSignature(i int, signature *types.Signature) error {
+	if signature.SignatureType != types.Ecdsa {
+		return fmt.Errorf(
+			"signature %d has type %s but only %s is supported",
+			i,
+			signature.SignatureType,
+			types.Ecdsa,
+		)
+	}
+
+	if len(signature.Bytes) != ecdsaSignatureLength {
+		return fmt.Errorf(
+			"signature %d is %d bytes but must be %d bytes",
+			i,
+			len(signature.Bytes),
+			ecdsaSignatureLength,
+		)
+	}
+
+	if signature.PublicKey == nil {
+		return fmt.Errorf("signature %d is missing its public key", i)
+	}
+
+	if signature.PublicKey.CurveType != types.Secp256k1 {
+		return fmt.Errorf(
+			"public key of signature %d is on curve %s but only %s is supported",
+			i,
+			signature.PublicKey.CurveType,
+			types.Secp256k1,
+		)
+	}
+
+	return nil
+}
+
+// validateSignaturePayload returns an error if signature
+// does not sign hash, the payload of the input it signs.
+func validateSignaturePayload(i int, signature *types.Signature, hash []byte) error {
+	if signature.SigningPayload == nil || !bytes.Equal(signature.SigningPayload.Bytes, hash) {
+		return fmt.Errorf("signature %d does not sign the payload of input %d", i, i)
+	}
+
+	return nil
+}
+
+func normalizeSignature(signature []byte, hashType txscript.SigHashType) []byte {
 	sig := btcec.Signature{ // signature is in form of R || S
 		R: new(big.Int).SetBytes(signature[:32]),
 		S: new(big.Int).SetBytes(signature[32:64]),
 	}
 
-	return append(sig.Serialize(), byte(txscript.SigHashAll))
+	return append(sig.Serialize(), byte(hashType))
 }
 
 // ConstructionCombine implements the /construction/combine
@@ -417,166 +1836,616 @@ func (s *ConstructionAPIService) ConstructionCombine(
 		)
 	}
 
-	decodedCoreTx, err := hex.DecodeString(unsigned.Transaction)
+	decodedCoreTx, err := hex.DecodeString(unsigned.Transaction)
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w transaction cannot be decoded", err),
+		)
+	}
+
+	var tx wire.MsgTx
+	if err := tx.Deserialize(bytes.NewReader(decodedCoreTx)); err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w unable to deserialize tx", err),
+		)
+	}
+
+	hashType, err := parseSigHashType(unsigned.SigHashType)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	if len(request.Signatures) != len(tx.TxIn) {
+		return nil, wrapErr(ErrInvalidSignature, fmt.Errorf(
+			"received %d signatures for %d inputs",
+			len(request.Signatures),
+			len(tx.TxIn),
+		))
+	}
+
+	for i := range tx.TxIn {
+		decodedScript, err := hex.DecodeString(unsigned.ScriptPubKeys[i].Hex)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
+		}
+
+		class, _, err := ravencoin.ParseSingleAddress(
+			s.config.Params.BtcdParams(),
+			ravencoin.AssetScriptBase(decodedScript),
+		)
+		if err != nil {
+			return nil, wrapErr(
+				ErrUnableToDecodeAddress,
+				fmt.Errorf("%w unable to parse address for script", err),
+			)
+		}
+
+		amount, ok := new(big.Int).SetString(unsigned.InputAmounts[i], 10)
+		if !ok {
+			return nil, wrapErr(
+				ErrUnableToParseIntermediateResult,
+				fmt.Errorf("unable to parse input amount %s", unsigned.InputAmounts[i]),
+			)
+		}
+
+		if err := validateSignature(i, request.Signatures[i]); err != nil {
+			return nil, wrapErr(ErrInvalidSignature, err)
+		}
+
+		pkData := request.Signatures[i].PublicKey.Bytes
+		signedScript := decodedScript
+		if class == txscript.ScriptHashTy {
+			signedScript, err = nestedWitnessScript(
+				decodedScript,
+				[]*types.PublicKey{request.Signatures[i].PublicKey},
+			)
+			if err != nil {
+				return nil, wrapErr(
+					ErrInvalidSignature,
+					fmt.Errorf("%w: public key of signature %d does not match input %d", err, i, i),
+				)
+			}
+		}
+
+		hash, hashErr := s.signatureHash(
+			&tx,
+			i,
+			signedScript,
+			amount.Abs(amount).Int64(),
+			hashType,
+		)
+		if hashErr != nil {
+			return nil, hashErr
+		}
+
+		if err := validateSignaturePayload(i, request.Signatures[i], hash); err != nil {
+			return nil, wrapErr(ErrInvalidSignature, err)
+		}
+
+		fullsig := normalizeSignature(request.Signatures[i].Bytes, hashType)
+
+		switch class {
+		case txscript.WitnessV0PubKeyHashTy:
+			tx.TxIn[i].Witness = wire.TxWitness{fullsig, pkData}
+		case txscript.ScriptHashTy:
+			sigScript, err := txscript.NewScriptBuilder().
+				AddData(signedScript).
+				Script()
+			if err != nil {
+				return nil, wrapErr(
+					ErrUnableToParseIntermediateResult,
+					fmt.Errorf("%w unable to build signature script", err),
+				)
+			}
+
+			tx.TxIn[i].SignatureScript = sigScript
+			tx.TxIn[i].Witness = wire.TxWitness{fullsig, pkData}
+		case txscript.PubKeyHashTy:
+			sigScript, err := txscript.NewScriptBuilder().
+				AddData(fullsig).
+				AddData(pkData).
+				Script()
+			if err != nil {
+				return nil, wrapErr(
+					ErrUnableToParseIntermediateResult,
+					fmt.Errorf("%w unable to build signature script", err),
+				)
+			}
+
+			tx.TxIn[i].SignatureScript = sigScript
+		case txscript.PubKeyTy:
+			// The public key is in the output being
+			// spent, so only the signature is pushed.
+			sigScript, err := txscript.NewScriptBuilder().
+				AddData(fullsig).
+				Script()
+			if err != nil {
+				return nil, wrapErr(
+					ErrUnableToParseIntermediateResult,
+					fmt.Errorf("%w unable to build signature script", err),
+				)
+			}
+
+			tx.TxIn[i].SignatureScript = sigScript
+		default:
+			return nil, wrapErr(
+				ErrUnsupportedScriptType,
+				fmt.Errorf("unupported script type: %s", class),
+			)
+		}
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+	if err := tx.Serialize(buf); err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, fmt.Errorf("%w serialize tx", err))
+	}
+
+	rawTx, err := json.Marshal(&signedTransaction{
+		Transaction:    hex.EncodeToString(buf.Bytes()),
+		InputAmounts:   unsigned.InputAmounts,
+		InputAddresses: unsigned.InputAddresses,
+		ScriptPubKeys:  unsigned.ScriptPubKeys,
+		Fee:            unsigned.Fee,
+	})
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w unable to serialize signed tx", err),
+		)
+	}
+
+	return &types.ConstructionCombineResponse{
+		SignedTransaction: hex.EncodeToString(rawTx),
+	}, nil
+}
+
+// ConstructionHash implements the /construction/hash endpoint.
+func (s *ConstructionAPIService) ConstructionHash(
+	ctx context.Context,
+	request *types.ConstructionHashRequest,
+) (*types.TransactionIdentifierResponse, *types.Error) {
+	decodedTx, err := hex.DecodeString(request.SignedTransaction)
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w signed transaction cannot be decoded", err),
+		)
+	}
+
+	var signed signedTransaction
+	if err := json.Unmarshal(decodedTx, &signed); err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w unable to unmarshal signed ravencoin transaction", err),
+		)
+	}
+
+	bytesTx, err := hex.DecodeString(signed.Transaction)
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w unable to decode hex transaction", err),
+		)
+	}
+
+	tx, err := btcutil.NewTxFromBytes(bytesTx)
+	if err != nil {
+		return nil, wrapErr(
+			ErrUnableToParseIntermediateResult,
+			fmt.Errorf("%w unable to parse transaction", err),
+		)
+	}
+
+	return &types.TransactionIdentifierResponse{
+		TransactionIdentifier: &types.TransactionIdentifier{
+			Hash: tx.Hash().String(),
+		},
+	}, nil
+}
+
+// parseMetadata returns the metadata to include in
+// the /construction/parse response for tx and the fee
+// breakdown it was constructed with (if any). If tx has
+// only default properties, no metadata is returned.
+func (s *ConstructionAPIService) parseMetadata(
+	tx *wire.MsgTx,
+	fee *feeBreakdown,
+) (map[string]interface{}, error) {
+	metadata := parseMetadata{
+		Fee: fee,
+	}
+	for _, input := range tx.TxIn {
+		if input.Sequence <= replaceableSequence {
+			metadata.Replaceable = true
+		}
+	}
+
+	metadata.LockTime = tx.LockTime
+
+	if metadata == (parseMetadata{}) {
+		return nil, nil
+	}
+
+	return types.MarshalMap(&metadata)
+}
+
+// parseOutputs appends the operations of the outputs
+// of tx to ops (the operations of the inputs of tx).
+// The outputs issuing an asset are represented by a
+// single ISSUE_ASSET operation, the outputs (and
+// owner token input) reissuing an asset by a single
+// REISSUE_ASSET operation and each output (and input)
+// burning an asset by a BURN_ASSET operation.
+func (s *ConstructionAPIService) parseOutputs(
+	tx *wire.MsgTx,
+	ops []*types.Operation,
+) ([]*types.Operation, *types.Error) {
+	issuance, err := s.parseIssuance(tx)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	outputs := tx.TxOut
+	if issuance != nil {
+		outputs = outputs[:len(outputs)-issuanceOutputCount]
+	} else {
+		issuance, err = s.parseReissuance(tx)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+
+		if issuance != nil {
+			outputs = outputs[:len(outputs)-reissuanceOutputCount]
+
+			if len(ops) == 0 {
+				return nil, wrapErr(
+					ErrUnableToParseIntermediateResult,
+					errors.New("asset reissuance does not spend an owner token"),
+				)
+			}
+
+			ownerInput := ops[len(ops)-1]
+			ops = ops[:len(ops)-1]
+			issuance.CoinChange = ownerInput.CoinChange
+		}
+	}
+
+	burns, err := s.parseBurns(outputs, ops)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+	outputs = outputs[:len(outputs)-len(burns)]
+	ops = ops[:len(ops)-len(burns)]
+
+	for i, output := range outputs {
+		networkIndex := int64(i)
+		_, addr, err := ravencoin.ParseSingleAddress(
+			s.config.Params.BtcdParams(),
+			ravencoin.AssetScriptBase(output.PkScript),
+		)
+		if err != nil {
+			return nil, wrapErr(
+				ErrUnableToDecodeAddress,
+				fmt.Errorf("%w unable to parse output address", err),
+			)
+		}
+
+		asset, err := ravencoin.ParseAssetScript(output.PkScript)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+
+		metadata, err := transferMetadata(asset)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+		}
+
+		ops = append(ops, &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index:        int64(len(ops)),
+				NetworkIndex: &networkIndex,
+			},
+			Type: s.opTypes.Output,
+			Account: &types.AccountIdentifier{
+				Address: addr.String(),
+			},
+			Amount:   outputAmount(output.Value, asset, s.config.Currency),
+			Metadata: metadata,
+		})
+	}
+
+	for _, burn := range burns {
+		burn.OperationIdentifier.Index = int64(len(ops))
+		ops = append(ops, burn)
+	}
+
+	if issuance != nil {
+		issuance.OperationIdentifier.Index = int64(len(ops))
+		ops = append(ops, issuance)
+	}
+
+	return ops, nil
+}
+
+// outputAmount returns the amount of an output worth
+// value that carries asset. Outputs carrying an asset
+// are worth the quantity of the asset instead.
+func outputAmount(value int64, asset *ravencoin.AssetScript, currency *types.Currency) *types.Amount {
+	if asset == nil {
+		return &types.Amount{
+			Value:    strconv.FormatInt(value, 10),
+			Currency: currency,
+		}
+	}
+
+	return &types.Amount{
+		Value:    strconv.FormatInt(asset.Amount, 10),
+		Currency: ravencoin.AssetCurrency(asset.Name, ravencoin.MaxAssetUnits),
+	}
+}
+
+// transferMetadata returns the metadata of an OUTPUT
+// operation transferring asset. If the transfer carries
+// no RIP5 message, nil is returned.
+func transferMetadata(asset *ravencoin.AssetScript) (map[string]interface{}, error) {
+	if asset == nil || len(asset.Message) == 0 {
+		return nil, nil
+	}
+
+	return types.MarshalMap(&transferAssetMetadata{
+		Message:    asset.Message,
+		ExpireTime: asset.ExpireTime,
+	})
+}
+
+// inputAmount returns the amount of input i, if
+// it is included in amounts. If the script of the
+// output spent by input i is included in scripts and
+// carries an asset, the (negative) quantity of the
+// asset is returned instead, as amounts only include
+// RVN.
+func (s *ConstructionAPIService) inputAmount(
+	amounts []string,
+	scripts [][]byte,
+	i int,
+) (*types.Amount, error) {
+	if i >= len(amounts) {
+		return nil, nil
+	}
+
+	if i < len(scripts) {
+		asset, err := ravencoin.ParseAssetScript(scripts[i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse asset spent by input %d", err, i)
+		}
+
+		if asset != nil {
+			return &types.Amount{
+				Value:    strconv.FormatInt(-asset.Amount, 10),
+				Currency: ravencoin.AssetCurrency(asset.Name, ravencoin.MaxAssetUnits),
+			}, nil
+		}
+	}
+
+	return &types.Amount{
+		Value:    amounts[i],
+		Currency: s.config.Currency,
+	}, nil
+}
+
+// validateBalance returns an error if the outputs of tx
+// are worth more than its inputs (i.e. the fee would be
+// negative). If inputAmounts are not provided, the
+// balance of tx cannot be checked.
+func validateBalance(tx *wire.MsgTx, inputAmounts []string) error {
+	if len(inputAmounts) == 0 {
+		return nil
+	}
+
+	if len(inputAmounts) != len(tx.TxIn) {
+		return fmt.Errorf(
+			"received %d input amounts for %d inputs",
+			len(inputAmounts),
+			len(tx.TxIn),
+		)
+	}
+
+	// Input amounts are negative.
+	inputs := big.NewInt(0)
+	for _, inputAmount := range inputAmounts {
+		amount, ok := new(big.Int).SetString(inputAmount, 10)
+		if !ok {
+			return fmt.Errorf("unable to parse input amount %s", inputAmount)
+		}
+
+		inputs.Sub(inputs, amount)
+	}
+
+	outputs := big.NewInt(0)
+	for _, output := range tx.TxOut {
+		outputs.Add(outputs, big.NewInt(output.Value))
+	}
+
+	if outputs.Cmp(inputs) > 0 {
+		return fmt.Errorf(
+			"outputs of %s exceed inputs of %s by %s",
+			outputs.String(),
+			inputs.String(),
+			new(big.Int).Sub(outputs, inputs).String(),
+		)
+	}
+
+	return nil
+}
+
+// decodeMsgTx decodes a hex-encoded serialized transaction.
+func decodeMsgTx(raw string) (*wire.MsgTx, error) {
+	serialized, err := hex.DecodeString(raw)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w transaction cannot be decoded", err),
-		)
+		return nil, fmt.Errorf("%w: transaction is not valid hex", err)
 	}
 
 	var tx wire.MsgTx
-	if err := tx.Deserialize(bytes.NewReader(decodedCoreTx)); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to deserialize tx", err),
-		)
+	if err := tx.Deserialize(bytes.NewReader(serialized)); err != nil {
+		return nil, fmt.Errorf("%w: unable to deserialize transaction", err)
 	}
 
-	for i := range tx.TxIn {
-		decodedScript, err := hex.DecodeString(unsigned.ScriptPubKeys[i].Hex)
-		if err != nil {
-			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
-		}
+	return &tx, nil
+}
 
-		class, _, err := ravencoin.ParseSingleAddress(nil /*s.config.Params*/, decodedScript)
-		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToDecodeAddress,
-				fmt.Errorf("%w unable to parse address for script", err),
-			)
-		}
+// decodeUnsignedTransaction decodes the hex-encoded unsignedTransaction
+// envelope returned by /construction/payloads and the transaction it
+// wraps. The returned error describes the first malformed field.
+func decodeUnsignedTransaction(raw string) (*unsignedTransaction, *wire.MsgTx, error) {
+	decoded, err := hex.DecodeString(raw)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unsigned transaction is not valid hex", err)
+	}
 
-		pkData := request.Signatures[i].PublicKey.Bytes
-		fullsig := normalizeSignature(request.Signatures[i].Bytes)
+	var unsigned unsignedTransaction
+	if err := json.Unmarshal(decoded, &unsigned); err != nil {
+		return nil, nil, fmt.Errorf("%w: unsigned transaction is not a valid envelope", err)
+	}
 
-		switch class {
-		case txscript.WitnessV0PubKeyHashTy:
-			tx.TxIn[i].Witness = wire.TxWitness{fullsig, pkData}
-		default:
-			return nil, wrapErr(
-				ErrUnsupportedScriptType,
-				fmt.Errorf("unupported script type: %s", class),
-			)
-		}
+	if len(unsigned.Transaction) == 0 {
+		return nil, nil, errors.New("unsigned transaction envelope is missing transaction")
 	}
 
-	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
-	if err := tx.Serialize(buf); err != nil {
-		return nil, wrapErr(ErrUnableToParseIntermediateResult, fmt.Errorf("%w serialize tx", err))
+	if unsigned.ScriptPubKeys == nil {
+		return nil, nil, errors.New("unsigned transaction envelope is missing scriptPubKeys")
 	}
 
-	rawTx, err := json.Marshal(&signedTransaction{
-		Transaction:  hex.EncodeToString(buf.Bytes()),
-		InputAmounts: unsigned.InputAmounts,
-	})
+	tx, err := decodeMsgTx(unsigned.Transaction)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to serialize signed tx", err),
+		return nil, nil, err
+	}
+
+	if len(unsigned.ScriptPubKeys) != len(tx.TxIn) {
+		return nil, nil, fmt.Errorf(
+			"unsigned transaction envelope has %d scriptPubKeys for %d inputs",
+			len(unsigned.ScriptPubKeys),
+			len(tx.TxIn),
 		)
 	}
 
-	return &types.ConstructionCombineResponse{
-		SignedTransaction: hex.EncodeToString(rawTx),
-	}, nil
+	if len(unsigned.InputAddresses) != len(tx.TxIn) {
+		return nil, nil, fmt.Errorf(
+			"unsigned transaction envelope has %d input_addresses for %d inputs",
+			len(unsigned.InputAddresses),
+			len(tx.TxIn),
+		)
+	}
+
+	return &unsigned, tx, nil
 }
 
-// ConstructionHash implements the /construction/hash endpoint.
-func (s *ConstructionAPIService) ConstructionHash(
-	ctx context.Context,
-	request *types.ConstructionHashRequest,
-) (*types.TransactionIdentifierResponse, *types.Error) {
-	decodedTx, err := hex.DecodeString(request.SignedTransaction)
+// decodeSignedTransaction decodes the hex-encoded signedTransaction
+// envelope returned by /construction/combine and the transaction it
+// wraps. The returned error describes the first malformed field.
+func decodeSignedTransaction(raw string) (*signedTransaction, *wire.MsgTx, error) {
+	decoded, err := hex.DecodeString(raw)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w signed transaction cannot be decoded", err),
-		)
+		return nil, nil, fmt.Errorf("%w: signed transaction is not valid hex", err)
 	}
 
 	var signed signedTransaction
-	if err := json.Unmarshal(decodedTx, &signed); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to unmarshal signed ravencoin transaction", err),
-		)
+	if err := json.Unmarshal(decoded, &signed); err != nil {
+		return nil, nil, fmt.Errorf("%w: signed transaction is not a valid envelope", err)
 	}
 
-	bytesTx, err := hex.DecodeString(signed.Transaction)
+	if len(signed.Transaction) == 0 {
+		return nil, nil, errors.New("signed transaction envelope is missing transaction")
+	}
+
+	tx, err := decodeMsgTx(signed.Transaction)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to decode hex transaction", err),
-		)
+		return nil, nil, err
 	}
 
-	tx, err := btcutil.NewTxFromBytes(bytesTx)
+	return &signed, tx, nil
+}
+
+// decodeRawTransaction returns the *wire.MsgTx encoded by raw
+// and true if raw is a plain hex-encoded transaction (i.e. from
+// getrawtransaction) instead of an envelope.
+func decodeRawTransaction(raw string) (*wire.MsgTx, bool) {
+	decoded, err := hex.DecodeString(raw)
+	if err != nil || json.Valid(decoded) {
+		return nil, false
+	}
+
+	tx, err := decodeMsgTx(raw)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to parse transaction", err),
-		)
+		return nil, false
 	}
 
-	return &types.TransactionIdentifierResponse{
-		TransactionIdentifier: &types.TransactionIdentifier{
-			Hash: tx.Hash().String(),
-		},
-	}, nil
+	return tx, true
 }
 
 func (s *ConstructionAPIService) parseUnsignedTransaction(
 	request *types.ConstructionParseRequest,
 ) (*types.ConstructionParseResponse, *types.Error) {
-	decodedTx, err := hex.DecodeString(request.Transaction)
-	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w transaction cannot be decoded", err),
-		)
+	// The addresses and amounts of the inputs of a plain
+	// transaction are not known.
+	if tx, ok := decodeRawTransaction(request.Transaction); ok {
+		return s.parseUnsignedMsgTx(tx, nil, nil, nil, nil)
 	}
 
-	var unsigned unsignedTransaction
-	if err := json.Unmarshal(decodedTx, &unsigned); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to unmarshal ravencoin transaction", err),
-		)
-	}
-
-	decodedCoreTx, err := hex.DecodeString(unsigned.Transaction)
+	unsigned, tx, err := decodeUnsignedTransaction(request.Transaction)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w transaction cannot be decoded", err),
-		)
+		return nil, wrapErr(ErrUnparseableTransaction, err)
 	}
 
-	var tx wire.MsgTx
-	if err := tx.Deserialize(bytes.NewReader(decodedCoreTx)); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to deserialize tx", err),
-		)
+	return s.parseUnsignedMsgTx(
+		tx,
+		unsigned.InputAddresses,
+		unsigned.InputAmounts,
+		unsigned.ScriptPubKeys,
+		unsigned.Fee,
+	)
+}
+
+// parseUnsignedMsgTx returns the operations of tx. If
+// inputAddresses or inputAmounts are not provided, the
+// input operations do not have accounts or amounts. The
+// assets spent by inputs are read from scriptPubKeys.
+func (s *ConstructionAPIService) parseUnsignedMsgTx(
+	tx *wire.MsgTx,
+	inputAddresses []string,
+	inputAmounts []string,
+	scriptPubKeys []*ravencoin.ScriptPubKey,
+	fee *feeBreakdown,
+) (*types.ConstructionParseResponse, *types.Error) {
+	scripts, err := fundingScripts(tx, scriptPubKeys)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
 	}
 
 	ops := []*types.Operation{}
 	for i, input := range tx.TxIn {
+		var account *types.AccountIdentifier
+		if i < len(inputAddresses) {
+			account = &types.AccountIdentifier{
+				Address: inputAddresses[i],
+			}
+		}
+
+		amount, err := s.inputAmount(inputAmounts, scripts, i)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
+		}
+
 		networkIndex := int64(i)
 		ops = append(ops, &types.Operation{
 			OperationIdentifier: &types.OperationIdentifier{
 				Index:        int64(len(ops)),
 				NetworkIndex: &networkIndex,
 			},
-			Type: ravencoin.InputOpType,
-			Account: &types.AccountIdentifier{
-				Address: unsigned.InputAddresses[i],
-			},
-			Amount: &types.Amount{
-				Value:    unsigned.InputAmounts[i],
-				Currency: s.config.Currency,
-			},
+			Type:    s.opTypes.Input,
+			Account: account,
+			Amount:  amount,
 			CoinChange: &types.CoinChange{
 				CoinAction: types.CoinSpent,
 				CoinIdentifier: &types.CoinIdentifier{
@@ -590,109 +2459,219 @@ func (s *ConstructionAPIService) parseUnsignedTransaction(
 		})
 	}
 
-	for i, output := range tx.TxOut {
-		networkIndex := int64(i)
-		_, addr, err := ravencoin.ParseSingleAddress(nil /*s.config.Params*/, output.PkScript)
-		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToDecodeAddress,
-				fmt.Errorf("%w unable to parse output address", err),
-			)
-		}
+	ops, parseErr := s.parseOutputs(tx, ops)
+	if parseErr != nil {
+		return nil, parseErr
+	}
 
-		ops = append(ops, &types.Operation{
-			OperationIdentifier: &types.OperationIdentifier{
-				Index:        int64(len(ops)),
-				NetworkIndex: &networkIndex,
-			},
-			Type: ravencoin.OutputOpType,
-			Account: &types.AccountIdentifier{
-				Address: addr.String(),
-			},
-			Amount: &types.Amount{
-				Value:    strconv.FormatInt(output.Value, 10),
-				Currency: s.config.Currency,
-			},
-		})
+	if err := validateBalance(tx, inputAmounts); err != nil {
+		return nil, wrapErr(ErrUnbalancedTransaction, err)
+	}
+
+	metadata, err := s.parseMetadata(tx, fee)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
 
 	return &types.ConstructionParseResponse{
 		Operations:               ops,
 		AccountIdentifierSigners: []*types.AccountIdentifier{},
+		Metadata:                 metadata,
 	}, nil
 }
 
 func (s *ConstructionAPIService) parseSignedTransaction(
 	request *types.ConstructionParseRequest,
 ) (*types.ConstructionParseResponse, *types.Error) {
-	decodedTx, err := hex.DecodeString(request.Transaction)
+	// The amounts of the inputs of a plain transaction
+	// are not known.
+	if tx, ok := decodeRawTransaction(request.Transaction); ok {
+		return s.parseSignedMsgTx(tx, nil, nil, nil, nil)
+	}
+
+	signed, tx, err := decodeSignedTransaction(request.Transaction)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w signed transaction cannot be decoded", err),
+		return nil, wrapErr(ErrUnparseableTransaction, err)
+	}
+
+	return s.parseSignedMsgTx(
+		tx,
+		signed.InputAmounts,
+		signed.InputAddresses,
+		signed.ScriptPubKeys,
+		signed.Fee,
+	)
+}
+
+// fundingScripts decodes scriptPubKeys, the locking scripts
+// of the outputs spent by the inputs of tx. If none are
+// provided, nil is returned.
+func fundingScripts(tx *wire.MsgTx, scriptPubKeys []*ravencoin.ScriptPubKey) ([][]byte, error) {
+	if len(scriptPubKeys) == 0 {
+		return nil, nil
+	}
+
+	if len(scriptPubKeys) != len(tx.TxIn) {
+		return nil, fmt.Errorf(
+			"%d scriptPubKeys provided for %d inputs",
+			len(scriptPubKeys),
+			len(tx.TxIn),
 		)
 	}
 
-	var signed signedTransaction
-	if err := json.Unmarshal(decodedTx, &signed); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to unmarshal signed ravencoin transaction", err),
+	scripts := make([][]byte, len(scriptPubKeys))
+	for i, scriptPubKey := range scriptPubKeys {
+		if scriptPubKey == nil {
+			return nil, fmt.Errorf("scriptPubKey of input %d cannot be nil", i)
+		}
+
+		script, err := hex.DecodeString(scriptPubKey.Hex)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to decode scriptPubKey of input %d", err, i)
+		}
+
+		scripts[i] = script
+	}
+
+	return scripts, nil
+}
+
+// signedInputAddress returns the address of the output spent
+// by input i. If fundingScript, the locking script of that
+// output, is provided, the address is read from it.
+// Otherwise, it is inferred from the input.
+func (s *ConstructionAPIService) signedInputAddress(
+	input *wire.TxIn,
+	inputAddresses []string,
+	fundingScript []byte,
+	i int,
+) (string, *types.Error) {
+	if fundingScript != nil {
+		_, addr, err := ravencoin.ParseSingleAddress(
+			s.config.Params.BtcdParams(),
+			ravencoin.AssetScriptBase(fundingScript),
 		)
+		if err != nil {
+			return "", wrapErr(
+				ErrUnableToDecodeAddress,
+				fmt.Errorf("%w: unable to decode address of input %d", err, i),
+			)
+		}
+
+		return addr.EncodeAddress(), nil
+	}
+
+	if isPubKeySpend(input) {
+		if i >= len(inputAddresses) {
+			return "", wrapErr(
+				ErrUnableToDecodeAddress,
+				fmt.Errorf("input %d spends a pay-to-pubkey output of unknown address", i),
+			)
+		}
+
+		return inputAddresses[i], nil
 	}
 
-	serializedTx, err := hex.DecodeString(signed.Transaction)
+	pkScript, err := txscript.ComputePkScript(input.SignatureScript, input.Witness)
 	if err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to decode hex transaction", err),
+		return "", wrapErr(
+			ErrUnableToComputePkScript,
+			fmt.Errorf("%w: unable to compute pk script", err),
 		)
 	}
 
-	var tx wire.MsgTx
-	if err := tx.Deserialize(bytes.NewReader(serializedTx)); err != nil {
-		return nil, wrapErr(
-			ErrUnableToParseIntermediateResult,
-			fmt.Errorf("%w unable to decode msgTx", err),
+	_, addr, err := ravencoin.ParseSingleAddress(s.config.Params.BtcdParams(), pkScript.Script())
+	if err != nil {
+		return "", wrapErr(
+			ErrUnableToDecodeAddress,
+			fmt.Errorf("%w unable to decode address", err),
 		)
 	}
 
+	return addr.EncodeAddress(), nil
+}
+
+// isPubKeySpend returns true if input spends a P2PK output,
+// in which case its signature script pushes only a signature.
+func isPubKeySpend(input *wire.TxIn) bool {
+	if len(input.Witness) > 0 {
+		return false
+	}
+
+	pushes, err := txscript.PushedData(input.SignatureScript)
+	if err != nil || len(pushes) != 1 || len(pushes[0]) == 0 {
+		return false
+	}
+
+	// The last byte of the signature is its sighash type.
+	sig := pushes[0]
+	_, err = btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+	return err == nil
+}
+
+// parseSignedMsgTx returns the operations and signers of
+// tx. If inputAmounts are not provided, the input operations
+// do not have amounts. The addresses of inputs spending P2PK
+// outputs cannot be recovered from tx, so they are read from
+// inputAddresses.
+//
+// If scriptPubKeys are provided, the spent outputs are read
+// from them instead of being inferred from the inputs. This
+// is required to classify some P2SH inputs (i.e. 1-of-1
+// multisig) that are indistinguishable from P2PKH inputs.
+func (s *ConstructionAPIService) parseSignedMsgTx(
+	tx *wire.MsgTx,
+	inputAmounts []string,
+	inputAddresses []string,
+	scriptPubKeys []*ravencoin.ScriptPubKey,
+	fee *feeBreakdown,
+) (*types.ConstructionParseResponse, *types.Error) {
+	scripts, err := fundingScripts(tx, scriptPubKeys)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
+	}
+
 	ops := []*types.Operation{}
 	signers := []*types.AccountIdentifier{}
 	for i, input := range tx.TxIn {
-		pkScript, err := txscript.ComputePkScript(input.SignatureScript, input.Witness)
+		var fundingScript []byte
+		if scripts != nil {
+			fundingScript = scripts[i]
+		}
+
+		addr, addrErr := s.signedInputAddress(input, inputAddresses, fundingScript, i)
+		if addrErr != nil {
+			return nil, addrErr
+		}
+
+		multisigSigners, err := s.multisigSigners(tx, i, fundingScript)
 		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToComputePkScript,
-				fmt.Errorf("%w: unable to compute pk script", err),
-			)
+			return nil, wrapErr(ErrInvalidSignature, err)
 		}
 
-		_, addr, err := ravencoin.ParseSingleAddress(nil /*s.config.Params*/, pkScript.Script())
+		amount, err := s.inputAmount(inputAmounts, scripts, i)
 		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToDecodeAddress,
-				fmt.Errorf("%w unable to decode address", err),
-			)
+			return nil, wrapErr(ErrUnableToDecodeScriptPubKey, err)
 		}
 
 		networkIndex := int64(i)
-		signers = append(signers, &types.AccountIdentifier{
-			Address: addr.EncodeAddress(),
-		})
+		if multisigSigners != nil {
+			signers = append(signers, multisigSigners...)
+		} else {
+			signers = append(signers, &types.AccountIdentifier{
+				Address: addr,
+			})
+		}
 		ops = append(ops, &types.Operation{
 			OperationIdentifier: &types.OperationIdentifier{
 				Index:        int64(len(ops)),
 				NetworkIndex: &networkIndex,
 			},
-			Type: ravencoin.InputOpType,
+			Type: s.opTypes.Input,
 			Account: &types.AccountIdentifier{
-				Address: addr.EncodeAddress(),
-			},
-			Amount: &types.Amount{
-				Value:    signed.InputAmounts[i],
-				Currency: s.config.Currency,
+				Address: addr,
 			},
+			Amount: amount,
 			CoinChange: &types.CoinChange{
 				CoinAction: types.CoinSpent,
 				CoinIdentifier: &types.CoinIdentifier{
@@ -706,35 +2685,24 @@ func (s *ConstructionAPIService) parseSignedTransaction(
 		})
 	}
 
-	for i, output := range tx.TxOut {
-		networkIndex := int64(i)
-		_, addr, err := ravencoin.ParseSingleAddress(nil /*s.config.Params*/, output.PkScript)
-		if err != nil {
-			return nil, wrapErr(
-				ErrUnableToDecodeAddress,
-				fmt.Errorf("%w unable to parse output address", err),
-			)
-		}
+	ops, parseErr := s.parseOutputs(tx, ops)
+	if parseErr != nil {
+		return nil, parseErr
+	}
 
-		ops = append(ops, &types.Operation{
-			OperationIdentifier: &types.OperationIdentifier{
-				Index:        int64(len(ops)),
-				NetworkIndex: &networkIndex,
-			},
-			Type: ravencoin.OutputOpType,
-			Account: &types.AccountIdentifier{
-				Address: addr.String(),
-			},
-			Amount: &types.Amount{
-				Value:    strconv.FormatInt(output.Value, 10),
-				Currency: s.config.Currency,
-			},
-		})
+	if err := validateBalance(tx, inputAmounts); err != nil {
+		return nil, wrapErr(ErrUnbalancedTransaction, err)
+	}
+
+	metadata, err := s.parseMetadata(tx, fee)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
 	}
 
 	return &types.ConstructionParseResponse{
 		Operations:               ops,
 		AccountIdentifierSigners: signers,
+		Metadata:                 metadata,
 	}, nil
 }
 
@@ -756,7 +2724,7 @@ func (s *ConstructionAPIService) ConstructionSubmit(
 	request *types.ConstructionSubmitRequest,
 ) (*types.TransactionIdentifierResponse, *types.Error) {
 	if s.config.Mode != configuration.Online {
-		return nil, wrapErr(ErrUnavailableOffline, nil)
+		return nil, errUnavailableOffline("/construction/submit")
 	}
 
 	decodedTx, err := hex.DecodeString(request.SignedTransaction)
@@ -776,10 +2744,29 @@ func (s *ConstructionAPIService) ConstructionSubmit(
 	}
 
 	txHash, err := s.client.SendRawTransaction(ctx, signed.Transaction)
+	if errors.Is(err, ravencoin.ErrTransactionAlreadyKnown) {
+		// Resubmitting a transaction ravend already has (e.g. when
+		// retrying after a timeout) is not a failure, so we return
+		// its hash as if it were accepted.
+		tx, decodeErr := decodeMsgTx(signed.Transaction)
+		if decodeErr != nil {
+			return nil, wrapErr(ErrUnableToParseIntermediateResult, decodeErr)
+		}
+
+		txHash, err = tx.TxHash().String(), nil
+	}
 	if err != nil {
+		if s.logger != nil {
+			s.logger.Debugw("transaction rejected", "error", err)
+		}
+
 		return nil, wrapErr(ErrRavend, fmt.Errorf("%w unable to submit transaction", err))
 	}
 
+	if s.logger != nil {
+		s.logger.Debugw("transaction submitted", "hash", txHash)
+	}
+
 	return &types.TransactionIdentifierResponse{
 		TransactionIdentifier: &types.TransactionIdentifier{
 			Hash: txHash,
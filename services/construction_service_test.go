@@ -15,14 +15,27 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
 	"testing"
+	"time"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
 	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
 
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/assert"
 )
@@ -36,6 +49,21 @@ func forceHexDecode(t *testing.T, s string) []byte {
 	return b
 }
 
+// forceSign returns the signature of hash by privKey
+// in the R || S form accepted by /construction/combine.
+func forceSign(t *testing.T, privKey *btcec.PrivateKey, hash []byte) []byte {
+	sig, err := privKey.Sign(hash)
+	if err != nil {
+		t.Fatalf("could not sign %x", hash)
+	}
+
+	b := make([]byte, ecdsaSignatureLength)
+	r, s := sig.R.Bytes(), sig.S.Bytes()
+	copy(b[32-len(r):32], r)
+	copy(b[64-len(s):], s)
+	return b
+}
+
 func forceMarshalMap(t *testing.T, i interface{}) map[string]interface{} {
 	m, err := types.MarshalMap(i)
 	if err != nil {
@@ -45,6 +73,36 @@ func forceMarshalMap(t *testing.T, i interface{}) map[string]interface{} {
 	return m
 }
 
+// minFee returns the fee breakdown of a transaction of
+// estimatedSize vbytes at ravencoin.MinFeeRate.
+func minFee(estimatedSize float64) *feeBreakdown {
+	return &feeBreakdown{
+		FeeRate:          1000,
+		NodeFeeRate:      1000,
+		EstimatedSize:    estimatedSize,
+		EffectiveFeeRate: 1,
+	}
+}
+
+// indexedAmounts returns the amounts the indexer
+// records for the outputs spent by coins.
+func indexedAmounts(t *testing.T, coins []*types.Coin) []*types.Amount {
+	amounts := make([]*types.Amount, len(coins))
+	for i, coin := range coins {
+		value, err := types.NegateValue(coin.Amount.Value)
+		if err != nil {
+			t.Fatalf("could not negate amount %s", coin.Amount.Value)
+		}
+
+		amounts[i] = &types.Amount{
+			Value:    value,
+			Currency: coin.Amount.Currency,
+		}
+	}
+
+	return amounts
+}
+
 func TestConstructionService(t *testing.T) {
 	networkIdentifier = &types.NetworkIdentifier{
 		Network:    ravencoin.TestnetNetwork,
@@ -60,15 +118,12 @@ func TestConstructionService(t *testing.T) {
 
 	mockIndexer := &mocks.Indexer{}
 	mockClient := &mocks.Client{}
-	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer)
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
 	ctx := context.Background()
 
 	// Test Derive
 	publicKey := &types.PublicKey{
-		Bytes: forceHexDecode(
-			t,
-			"0325c9a4252789b31dbb3454ec647e9516e7c596bcde2bd5da71a60fab8644e438",
-		),
+		Bytes:     forceHexDecode(t, "0325c9a4252789b31dbb3454ec647e9516e7c596bcde2bd5da71a60fab8644e438"),
 		CurveType: types.Secp256k1,
 	}
 	deriveResponse, err := servicer.ConstructionDerive(ctx, &types.ConstructionDeriveRequest{
@@ -174,6 +229,14 @@ func TestConstructionService(t *testing.T) {
 		},
 	}
 
+	// The fee breakdown is returned alongside the
+	// metadata passed to /construction/payloads.
+	withFee := func(fee *feeBreakdown) *constructionMetadata {
+		metadataWithFee := *metadata
+		metadataWithFee.Fee = fee
+		return &metadataWithFee
+	}
+
 	// Normal Fee
 	mockIndexer.On(
 		"GetScriptPubKeys",
@@ -183,8 +246,16 @@ func TestConstructionService(t *testing.T) {
 		metadata.ScriptPubKeys,
 		nil,
 	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.Coins,
+	).Return(
+		indexedAmounts(t, options.Coins),
+		nil,
+	).Once()
 	mockClient.On(
-		"SuggestedFeeRate",
+		"SuggestedFeeRatePerKB",
 		ctx,
 		defaultConfirmationTarget,
 	).Return(
@@ -197,7 +268,12 @@ func TestConstructionService(t *testing.T) {
 	})
 	assert.Nil(t, err)
 	assert.Equal(t, &types.ConstructionMetadataResponse{
-		Metadata: forceMarshalMap(t, metadata),
+		Metadata: forceMarshalMap(t, withFee(&feeBreakdown{
+			FeeRate:          7500, // 10,000 * 0.75
+			NodeFeeRate:      10000,
+			EstimatedSize:    142,
+			EffectiveFeeRate: 7.5,
+		})),
 		SuggestedFee: []*types.Amount{
 			{
 				Value:    "1065", // 1,420 * 0.75
@@ -215,8 +291,16 @@ func TestConstructionService(t *testing.T) {
 		metadata.ScriptPubKeys,
 		nil,
 	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.Coins,
+	).Return(
+		indexedAmounts(t, options.Coins),
+		nil,
+	).Once()
 	mockClient.On(
-		"SuggestedFeeRate",
+		"SuggestedFeeRatePerKB",
 		ctx,
 		defaultConfirmationTarget,
 	).Return(
@@ -229,7 +313,7 @@ func TestConstructionService(t *testing.T) {
 	})
 	assert.Nil(t, err)
 	assert.Equal(t, &types.ConstructionMetadataResponse{
-		Metadata: forceMarshalMap(t, metadata),
+		Metadata: forceMarshalMap(t, withFee(minFee(142))),
 		SuggestedFee: []*types.Amount{
 			{
 				Value:    "142", // we don't go below minimum fee rate
@@ -238,6 +322,52 @@ func TestConstructionService(t *testing.T) {
 		},
 	}, metadataResponse)
 
+	// Fee rates below the minimum are floored at MinFeeRate,
+	// not the (higher) dust relay fee rate.
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		options.Coins,
+	).Return(
+		metadata.ScriptPubKeys,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.Coins,
+	).Return(
+		indexedAmounts(t, options.Coins),
+		nil,
+	).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate/2,
+		nil,
+	).Once()
+	metadataResponse, err = servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           forceMarshalMap(t, options),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.ConstructionMetadataResponse{
+		Metadata: forceMarshalMap(t, withFee(&feeBreakdown{
+			FeeRate:          1000,
+			NodeFeeRate:      500,
+			EstimatedSize:    142,
+			EffectiveFeeRate: 1,
+		})),
+		SuggestedFee: []*types.Amount{
+			{
+				Value:    "142",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}, metadataResponse)
+
 	// Test Payloads
 	unsignedRaw := "7b227472616e73616374696f6e223a2230313030303030303031376639636635306230326464353235386638306364356333343337333032653032376464313333363137326132306364633830333035633561353537343162313031303030303030303066666666666666663032646239313065303030303030303030303136303031343838636536393235663835313361323334633035633932326565393333663232313332333035323037316165303030303030303030303030313630303134393430373236353935633431666361306234383130633632393931616439643238396565623832383030303030303030222c227363726970745075624b657973223a5b7b2261736d223a22302063303035623030616430373564333062383961376236356237646164383839396261366139633535222c22686578223a223030313463303035623030616430373564333062383961376236356237646164383839396261366139633535222c2272657153696773223a312c2274797065223a227769746e6573735f76305f6b657968617368222c22616464726573736573223a5b227462317163717a6d717a6b7377686673687a64386b6564686d7476676e78617834387a34666b6c68766d225d7d5d2c22696e7075745f616d6f756e7473223a5b222d31303030303030225d2c22696e7075745f616464726573736573223a5b227462317163717a6d717a6b7377686673687a64386b6564686d7476676e78617834387a34666b6c68766d225d7d" // nolint
 	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
@@ -327,7 +457,7 @@ func TestConstructionService(t *testing.T) {
 	}, parseUnsignedResponse)
 
 	// Test Combine
-	signedRaw := "7b227472616e73616374696f6e223a22303130303030303030303031303137663963663530623032646435323538663830636435633334333733303265303237646431333336313732613230636463383033303563356135353734316231303130303030303030306666666666666666303264623931306530303030303030303030313630303134383863653639323566383531336132333463303563393232656539333366323231333233303532303731616530303030303030303030303031363030313439343037323635393563343166636130623438313063363239393161643964323839656562383238303234373330343430323230323538373665633862396635316433343361356135366163353439633063383238303035656634356562653964613136366462363435633039313537323233663032323034636430386237323738613838383961383131333539313562636531306431656633626239326232313766383161306465376537396666623364666436616335303132313033323563396134323532373839623331646262333435346563363437653935313665376335393662636465326264356461373161363066616238363434653433383030303030303030222c22696e7075745f616d6f756e7473223a5b222d31303030303030225d7d" // nolint
+	signedRaw := "7b227472616e73616374696f6e223a22303130303030303030303031303137663963663530623032646435323538663830636435633334333733303265303237646431333336313732613230636463383033303563356135353734316231303130303030303030306666666666666666303264623931306530303030303030303030313630303134383863653639323566383531336132333463303563393232656539333366323231333233303532303731616530303030303030303030303031363030313439343037323635393563343166636130623438313063363239393161643964323839656562383238303234373330343430323230323538373665633862396635316433343361356135366163353439633063383238303035656634356562653964613136366462363435633039313537323233663032323034636430386237323738613838383961383131333539313562636531306431656633626239326232313766383161306465376537396666623364666436616335303132313033323563396134323532373839623331646262333435346563363437653935313665376335393662636465326264356461373161363066616238363434653433383030303030303030222c22696e7075745f616d6f756e7473223a5b222d31303030303030225d2c22696e7075745f616464726573736573223a5b227462317163717a6d717a6b7377686673687a64386b6564686d7476676e78617834387a34666b6c68766d225d7d" // nolint
 	combineResponse, err := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
 		NetworkIdentifier:   networkIdentifier,
 		UnsignedTransaction: unsignedRaw,
@@ -348,6 +478,48 @@ func TestConstructionService(t *testing.T) {
 		SignedTransaction: signedRaw,
 	}, combineResponse)
 
+	// Signatures must be ECDSA signatures of the
+	// payload of the input they sign.
+	signature := func(signatureType types.SignatureType, payload []byte) *types.Signature {
+		return &types.Signature{
+			Bytes: forceHexDecode(
+				t,
+				"25876ec8b9f51d343a5a56ac549c0c828005ef45ebe9da166db645c09157223f4cd08b7278a8889a81135915bce10d1ef3bb92b217f81a0de7e79ffb3dfd6ac5", // nolint
+			),
+			SigningPayload: &types.SigningPayload{
+				Bytes:             payload,
+				AccountIdentifier: signingPayload.AccountIdentifier,
+				SignatureType:     signatureType,
+			},
+			PublicKey:     publicKey,
+			SignatureType: signatureType,
+		}
+	}
+	missingPublicKey := signature(types.Ecdsa, signingPayload.Bytes)
+	missingPublicKey.PublicKey = nil
+	wrongCurve := signature(types.Ecdsa, signingPayload.Bytes)
+	wrongCurve.PublicKey = &types.PublicKey{
+		Bytes:     publicKey.Bytes,
+		CurveType: types.Edwards25519,
+	}
+	invalidSignatures := map[string]*types.Signature{
+		"wrong signature type": signature(types.EcdsaRecovery, signingPayload.Bytes),
+		"unknown payload":      signature(types.Ecdsa, make([]byte, 32)),
+		"missing public key":   missingPublicKey,
+		"wrong curve":          wrongCurve,
+	}
+	for name, invalidSignature := range invalidSignatures {
+		t.Run(name, func(t *testing.T) {
+			combineResponse, err := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+				NetworkIdentifier:   networkIdentifier,
+				UnsignedTransaction: unsignedRaw,
+				Signatures:          []*types.Signature{invalidSignature},
+			})
+			assert.Nil(t, combineResponse)
+			assert.Equal(t, ErrInvalidSignature.Code, err.Code)
+		})
+	}
+
 	// Test Parse Signed
 	parseSignedResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
 		NetworkIdentifier: networkIdentifier,
@@ -375,6 +547,54 @@ func TestConstructionService(t *testing.T) {
 		TransactionIdentifier: transactionIdentifier,
 	}, hashResponse)
 
+	// Test Offline
+	offlineCfg := *cfg
+	offlineCfg.Mode = configuration.Offline
+	offlineServicer := NewConstructionAPIService(&offlineCfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	offlineDeriveResponse, err := offlineServicer.ConstructionDerive(ctx, &types.ConstructionDeriveRequest{
+		NetworkIdentifier: networkIdentifier,
+		PublicKey:         publicKey,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, deriveResponse, offlineDeriveResponse)
+	offlinePreprocessResponse, err := offlineServicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier:      networkIdentifier,
+			Operations:             ops,
+			SuggestedFeeMultiplier: &feeMultiplier,
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, preprocessResponse, offlinePreprocessResponse)
+	offlinePayloadsResponse, err := offlineServicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          forceMarshalMap(t, metadata),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, payloadsResponse, offlinePayloadsResponse)
+	offlineParseResponse, err := offlineServicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       signedRaw,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, parseSignedResponse, offlineParseResponse)
+	offlineCombineResponse, err := offlineServicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: unsignedRaw,
+		Signatures:          []*types.Signature{signature(types.Ecdsa, signingPayload.Bytes)},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, combineResponse, offlineCombineResponse)
+	offlineHashResponse, err := offlineServicer.ConstructionHash(ctx, &types.ConstructionHashRequest{
+		NetworkIdentifier: networkIdentifier,
+		SignedTransaction: signedRaw,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, hashResponse, offlineHashResponse)
+
 	// Test Submit
 	ravencoinTransaction := "010000000001017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305c5a55741b10100000000ffffffff02db910e000000000016001488ce6925f8513a234c05c922ee933f221323052071ae000000000000160014940726595c41fca0b4810c62991ad9d289eeb82802473044022025876ec8b9f51d343a5a56ac549c0c828005ef45ebe9da166db645c09157223f02204cd08b7278a8889a81135915bce10d1ef3bb92b217f81a0de7e79ffb3dfd6ac501210325c9a4252789b31dbb3454ec647e9516e7c596bcde2bd5da71a60fab8644e43800000000" // nolint
 	mockClient.On(
@@ -394,6 +614,4876 @@ func TestConstructionService(t *testing.T) {
 		TransactionIdentifier: transactionIdentifier,
 	}, submitResponse)
 
+	// Test Parse Raw (plain transaction hex instead
+	// of an envelope, so input amounts are unknown)
+	rawParseOps := make([]*types.Operation, len(parseOps))
+	for i, op := range parseOps {
+		rawOp := *op
+		rawParseOps[i] = &rawOp
+	}
+	rawParseOps[0].Amount = nil
+	parseRawSignedResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       ravencoinTransaction,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.ConstructionParseResponse{
+		Operations: rawParseOps,
+		AccountIdentifierSigners: []*types.AccountIdentifier{
+			{Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm"},
+		},
+	}, parseRawSignedResponse)
+
+	// The input addresses of a plain unsigned
+	// transaction are unknown too.
+	rawParseOps[0].Account = nil
+	parseRawUnsignedResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       "01000000017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305c5a55741b10100000000ffffffff02db910e000000000016001488ce6925f8513a234c05c922ee933f221323052071ae000000000000160014940726595c41fca0b4810c62991ad9d289eeb82800000000", // nolint
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.ConstructionParseResponse{
+		Operations:               rawParseOps,
+		AccountIdentifierSigners: []*types.AccountIdentifier{},
+	}, parseRawUnsignedResponse)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+// assertUnavailableOffline asserts that err rejects
+// endpoint because it is not available offline.
+func assertUnavailableOffline(t *testing.T, endpoint string, err *types.Error) {
+	assert.NotNil(t, err)
+	assert.Equal(t, ErrUnavailableOffline.Code, err.Code)
+	assert.Equal(t, ErrUnavailableOffline.Message, err.Message)
+	assert.Equal(t, endpoint+" is not available in offline mode", err.Details["context"])
+}
+
+func TestConstructionService_Offline(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Offline,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	assert.Nil(t, metadataResponse)
+	assertUnavailableOffline(t, "/construction/metadata", err)
+
+	submitResponse, err := servicer.ConstructionSubmit(ctx, &types.ConstructionSubmitRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	assert.Nil(t, submitResponse)
+	assertUnavailableOffline(t, "/construction/submit", err)
+
 	mockClient.AssertExpectations(t)
 	mockIndexer.AssertExpectations(t)
 }
+
+func TestConstructionService_LegacyInputBech32Change(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+			},
+			Amount: &types.Amount{
+				Value:    "954843",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 2,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1qjsrjvk2ug872pdypp33fjxke62y7awpgefr6ua",
+			},
+			Amount: &types.Amount{
+				Value:    "44657",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+
+	// Change type must match the last output
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &preprocessMetadata{
+				ChangeAddressType: LegacyAddressType,
+			}),
+		},
+	)
+	assert.Nil(t, preprocessResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	preprocessResponse, err = servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &preprocessMetadata{
+				ChangeAddressType: Bech32AddressType,
+			}),
+		},
+	)
+	assert.Nil(t, err)
+	options := &preprocessOptions{
+		Coins: []*types.Coin{
+			{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		},
+		// 12 overhead + 148 legacy input + 34 P2PKH output + 31 P2WPKH change
+		EstimatedSize: 225,
+	}
+	assert.Equal(t, &types.ConstructionPreprocessResponse{
+		Options: forceMarshalMap(t, options),
+	}, preprocessResponse)
+
+	metadata := &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG", // nolint
+				Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+		},
+	}
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		options.Coins,
+	).Return(
+		metadata.ScriptPubKeys,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.Coins,
+	).Return(
+		indexedAmounts(t, options.Coins),
+		nil,
+	).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate*10,
+		nil,
+	).Once()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           forceMarshalMap(t, options),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.Amount{
+		{
+			Value:    "2250", // 225 * 10
+			Currency: ravencoin.TestnetCurrency,
+		},
+	}, metadataResponse.SuggestedFee)
+
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata:          metadataResponse.Metadata,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, payloadsResponse.Payloads, 1)
+	assert.Len(t, payloadsResponse.Payloads[0].Bytes, 32)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxOut, 2)
+	assert.Equal(t, txscript.PubKeyHashTy, txscript.GetScriptClass(tx.TxOut[0].PkScript))
+	assert.Equal(t, txscript.WitnessV0PubKeyHashTy, txscript.GetScriptClass(tx.TxOut[1].PkScript))
+	assert.Equal(t, int64(44657), tx.TxOut[1].Value)
+
+	// The fee breakdown from /construction/metadata
+	// survives into /construction/parse.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, forceMarshalMap(t, &parseMetadata{
+		Fee: &feeBreakdown{
+			FeeRate:          10000,
+			NodeFeeRate:      10000,
+			EstimatedSize:    225,
+			EffectiveFeeRate: 10,
+		},
+	}), parseResponse.Metadata)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_Replaceable(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "999000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+			Addresses: []string{
+				"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+		},
+	}
+
+	// Replaceable is carried from preprocess to metadata
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &preprocessMetadata{
+				Replaceable: true,
+			}),
+		},
+	)
+	assert.Nil(t, err)
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: ops[0].CoinChange.CoinIdentifier,
+			Amount:         ops[0].Amount,
+		},
+	}
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		coins,
+	).Return(
+		scriptPubKeys,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		coins,
+	).Return(
+		indexedAmounts(t, coins),
+		nil,
+	).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Once()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           preprocessResponse.Options,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: scriptPubKeys,
+		Replaceable:   true,
+		Fee:           minFee(111),
+	}), metadataResponse.Metadata)
+
+	buildTx := func(replaceable bool) (*wire.MsgTx, string) {
+		payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &constructionMetadata{
+				ScriptPubKeys: scriptPubKeys,
+				Replaceable:   replaceable,
+			}),
+		})
+		assert.Nil(t, err)
+
+		var unsigned unsignedTransaction
+		assert.NoError(t, json.Unmarshal(
+			forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+			&unsigned,
+		))
+
+		var tx wire.MsgTx
+		assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+
+		return &tx, payloadsResponse.UnsignedTransaction
+	}
+
+	rbfTx, rbfUnsigned := buildTx(true)
+	finalTx, finalUnsigned := buildTx(false)
+	assert.Equal(t, uint32(0xfffffffd), rbfTx.TxIn[0].Sequence)
+	assert.Equal(t, wire.MaxTxInSequenceNum, finalTx.TxIn[0].Sequence)
+	assert.NotEqual(t, finalTx.TxHash(), rbfTx.TxHash())
+
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       rbfUnsigned,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"replaceable": true}, parseResponse.Metadata)
+
+	parseResponse, err = servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       finalUnsigned,
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, parseResponse.Metadata)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_LockTime(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "999000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+			Addresses: []string{
+				"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+		},
+	}
+
+	// Locktime is carried from preprocess to metadata
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &preprocessMetadata{
+				LockTime: 1500000,
+			}),
+		},
+	)
+	assert.Nil(t, err)
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: ops[0].CoinChange.CoinIdentifier,
+			Amount:         ops[0].Amount,
+		},
+	}
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		coins,
+	).Return(
+		scriptPubKeys,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		coins,
+	).Return(
+		indexedAmounts(t, coins),
+		nil,
+	).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Once()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           preprocessResponse.Options,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: scriptPubKeys,
+		LockTime:      1500000,
+		Fee:           minFee(111),
+	}), metadataResponse.Metadata)
+
+	tests := map[string]struct {
+		lockTime int64
+	}{
+		"block height": {
+			lockTime: 1500000,
+		},
+		"unix time": {
+			lockTime: 1700000000,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+				NetworkIdentifier: networkIdentifier,
+				Operations:        ops,
+				Metadata: forceMarshalMap(t, &constructionMetadata{
+					ScriptPubKeys: scriptPubKeys,
+					LockTime:      test.lockTime,
+				}),
+			})
+			assert.Nil(t, err)
+
+			var unsigned unsignedTransaction
+			assert.NoError(t, json.Unmarshal(
+				forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+				&unsigned,
+			))
+
+			var tx wire.MsgTx
+			assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+			assert.Equal(t, uint32(test.lockTime), tx.LockTime)
+			assert.Equal(t, uint32(0xfffffffe), tx.TxIn[0].Sequence)
+
+			parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+				NetworkIdentifier: networkIdentifier,
+				Signed:            false,
+				Transaction:       payloadsResponse.UnsignedTransaction,
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, forceMarshalMap(t, &parseMetadata{
+				LockTime: uint32(test.lockTime),
+			}), parseResponse.Metadata)
+		})
+	}
+
+	// Locktimes that don't fit in a uint32 are rejected
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata: forceMarshalMap(t, &constructionMetadata{
+			ScriptPubKeys: scriptPubKeys,
+			LockTime:      1 << 32,
+		}),
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrInvalidLockTime.Code, err.Code)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_ConfirmationDepth(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:              configuration.Online,
+		Network:           networkIdentifier,
+		Params:            ravencoin.TestnetParams,
+		Currency:          ravencoin.TestnetCurrency,
+		ConfirmationDepth: 6,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+			Addresses: []string{
+				"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+		},
+	}
+	options := forceMarshalMap(t, &preprocessOptions{
+		Coins:         coins,
+		EstimatedSize: 142,
+	})
+
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Twice()
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		coins,
+	).Return(
+		scriptPubKeys,
+		nil,
+	).Twice()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		coins,
+	).Return(
+		indexedAmounts(t, coins),
+		nil,
+	).Twice()
+	tipHash := "00000000000002f1e0a49cf4a3d5e6fd08c14b8e30e18f3cac4e0ee5cb30a1b8"
+	replayHeight := int64(994)
+	mockClient.On("GetBestBlockHash", ctx).Return(tipHash, nil).Twice()
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Hash: &tipHash},
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Hash:  tipHash,
+					Index: 1000,
+				},
+			},
+		},
+		nil,
+	).Twice()
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Index: &replayHeight},
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Hash:  "0000000000000a3290f20e75860d505ce0e948a1d1d846bec7e39015d242884b",
+					Index: 994,
+				},
+			},
+		},
+		nil,
+	).Twice()
+
+	// The replay block is stable
+	mockClient.On(
+		"GetHashFromIndex",
+		ctx,
+		int64(994),
+	).Return(
+		"0000000000000a3290f20e75860d505ce0e948a1d1d846bec7e39015d242884b",
+		nil,
+	).Once()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           options,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys:     scriptPubKeys,
+		ReplayBlockHeight: 994,
+		ReplayBlockHash:   "0000000000000a3290f20e75860d505ce0e948a1d1d846bec7e39015d242884b",
+		Fee:               minFee(142),
+	}), metadataResponse.Metadata)
+
+	// The tip was found in the indexer, so only the tip
+	// hash and the closing replay block check are
+	// fetched from ravend.
+	mockClient.AssertNumberOfCalls(t, "GetBestBlockHash", 1)
+	mockClient.AssertNumberOfCalls(t, "GetHashFromIndex", 1)
+	mockClient.AssertNumberOfCalls(t, "GetBlockHeight", 0)
+
+	// The replay block is reorged out while fetching metadata
+	mockClient.On(
+		"GetHashFromIndex",
+		ctx,
+		int64(994),
+	).Return(
+		"00000000000004d1b9e16a2b1f2ad6ba1b1e9ef4e5e4ea3fa1fa1a33b7cdaf5e",
+		nil,
+	).Once()
+	metadataResponse, err = servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           options,
+	})
+	assert.Nil(t, metadataResponse)
+	assert.Equal(t, ErrReplayBlockChanged.Code, err.Code)
+	assert.True(t, err.Retriable)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_ReplayProtectionDepth(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:                  configuration.Online,
+		Network:               networkIdentifier,
+		Params:                ravencoin.TestnetParams,
+		Currency:              ravencoin.TestnetCurrency,
+		ConfirmationDepth:     6,
+		ReplayProtectionDepth: 212,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+			Addresses: []string{
+				"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+		},
+	}
+
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		coins,
+	).Return(
+		scriptPubKeys,
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		coins,
+	).Return(
+		indexedAmounts(t, coins),
+		nil,
+	).Once()
+	// The indexer has not stored the tip yet, so the
+	// replay block is looked up on the node.
+	tipHash := "00000000000002f1e0a49cf4a3d5e6fd08c14b8e30e18f3cac4e0ee5cb30a1b8"
+	mockClient.On("GetBestBlockHash", ctx).Return(tipHash, nil).Once()
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Hash: &tipHash},
+	).Return(
+		nil,
+		errors.New("block not found"),
+	).Once()
+	mockClient.On("GetBlockHeight", ctx, tipHash).Return(int64(1000), nil).Once()
+	mockClient.On(
+		"GetHashFromIndex",
+		ctx,
+		int64(788),
+	).Return(
+		"0000000000000a3290f20e75860d505ce0e948a1d1d846bec7e39015d242884b",
+		nil,
+	).Twice()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: forceMarshalMap(t, &preprocessOptions{
+			Coins:         coins,
+			EstimatedSize: 142,
+		}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys:     scriptPubKeys,
+		ReplayBlockHeight: 788,
+		ReplayBlockHash:   "0000000000000a3290f20e75860d505ce0e948a1d1d846bec7e39015d242884b",
+		Fee:               minFee(142),
+	}), metadataResponse.Metadata)
+
+	// The height of the fetched tip is looked up by hash,
+	// so the replay block is found with the tip hash, the
+	// tip height, and the replay block hash (which is also
+	// fetched for the closing replay block check).
+	mockClient.AssertNumberOfCalls(t, "GetBestBlockHash", 1)
+	mockClient.AssertNumberOfCalls(t, "GetBlockHeight", 1)
+	mockClient.AssertNumberOfCalls(t, "GetHashFromIndex", 2)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_ScriptPubKeyCache(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+		ScriptPubKeyCache: &configuration.ScriptPubKeyCacheConfiguration{
+			Size: 10,
+			TTL:  time.Minute,
+		},
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	coin := func(identifier string) *types.Coin {
+		return &types.Coin{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: identifier,
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		}
+	}
+	coin0 := coin("b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:0")
+	coin1 := coin("b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1")
+	coin2 := coin("b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:2")
+	script := func(hex string) *ravencoin.ScriptPubKey {
+		return &ravencoin.ScriptPubKey{
+			Hex:          hex,
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+		}
+	}
+	script0 := script("0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55")
+	script1 := script("001488ce6925f8513a234c05c922ee933f2212330520")
+	script2 := script("00143a4ac8d4bf5d3fab3fba4b48eb7fbbd0ae0db5ef")
+
+	metadata := func(coins []*types.Coin) map[string]interface{} {
+		response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+			NetworkIdentifier: networkIdentifier,
+			Options: forceMarshalMap(t, &preprocessOptions{
+				Coins:         coins,
+				EstimatedSize: 142,
+			}),
+		})
+		assert.Nil(t, err)
+
+		return response.Metadata
+	}
+
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Twice()
+
+	// All coins are fetched from the indexer
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		[]*types.Coin{coin0, coin1},
+	).Return(
+		[]*ravencoin.ScriptPubKey{script0, script1},
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		[]*types.Coin{coin0, coin1},
+	).Return(
+		indexedAmounts(t, []*types.Coin{coin0, coin1}),
+		nil,
+	).Once()
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{script0, script1},
+		Fee:           minFee(142),
+	}), metadata([]*types.Coin{coin0, coin1}))
+
+	// Only the uncached coin is fetched from the indexer
+	mockIndexer.On(
+		"GetScriptPubKeys",
+		ctx,
+		[]*types.Coin{coin2},
+	).Return(
+		[]*ravencoin.ScriptPubKey{script2},
+		nil,
+	).Once()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		[]*types.Coin{coin2},
+	).Return(
+		indexedAmounts(t, []*types.Coin{coin2}),
+		nil,
+	).Once()
+	assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{script2, script1},
+		Fee:           minFee(142),
+	}), metadata([]*types.Coin{coin2, coin1}))
+
+	// The amount of a cached coin is still checked
+	understatedCoin1 := &types.Coin{
+		CoinIdentifier: coin1.CoinIdentifier,
+		Amount: &types.Amount{
+			Value:    "-1",
+			Currency: coin1.Amount.Currency,
+		},
+	}
+	response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: forceMarshalMap(t, &preprocessOptions{
+			Coins:         []*types.Coin{understatedCoin1},
+			EstimatedSize: 142,
+		}),
+	})
+	assert.Nil(t, response)
+	assert.Equal(t, ErrInvalidCoin.Code, err.Code)
+	assert.Equal(
+		t,
+		"coin b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1 is worth 1000000 but was provided with amount -1", // nolint
+		err.Details["context"],
+	)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_InputAmounts(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	ctx := context.Background()
+	coin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{
+			Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+		},
+		Amount: &types.Amount{
+			Value:    "-500000",
+			Currency: ravencoin.TestnetCurrency,
+		},
+	}
+	script := &ravencoin.ScriptPubKey{
+		Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+		RequiredSigs: 1,
+		Type:         "witness_v0_keyhash",
+	}
+
+	tests := map[string]struct {
+		allowUnverified bool
+		indexedAmount   string
+
+		err *types.Error
+	}{
+		"matching amount": {
+			indexedAmount: "500000",
+		},
+		"understated amount": {
+			indexedAmount: "1000000",
+			err:           ErrInvalidCoin,
+		},
+		"unverified understated amount": {
+			allowUnverified: true,
+			indexedAmount:   "1000000",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:                        configuration.Online,
+				Network:                     networkIdentifier,
+				Params:                      ravencoin.TestnetParams,
+				Currency:                    ravencoin.TestnetCurrency,
+				AllowUnverifiedInputAmounts: test.allowUnverified,
+			}
+
+			mockIndexer := &mocks.Indexer{}
+			mockClient := &mocks.Client{}
+			servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+
+			mockIndexer.On(
+				"GetScriptPubKeys",
+				ctx,
+				[]*types.Coin{coin},
+			).Return(
+				[]*ravencoin.ScriptPubKey{script},
+				nil,
+			).Once()
+			if !test.allowUnverified {
+				mockIndexer.On(
+					"GetCoinAmounts",
+					ctx,
+					[]*types.Coin{coin},
+				).Return(
+					[]*types.Amount{
+						{
+							Value:    test.indexedAmount,
+							Currency: ravencoin.TestnetCurrency,
+						},
+					},
+					nil,
+				).Once()
+			}
+			if test.err == nil {
+				mockClient.On(
+					"SuggestedFeeRatePerKB",
+					ctx,
+					defaultConfirmationTarget,
+				).Return(
+					ravencoin.MinFeeRate,
+					nil,
+				).Once()
+			}
+
+			response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+				NetworkIdentifier: networkIdentifier,
+				Options: forceMarshalMap(t, &preprocessOptions{
+					Coins:         []*types.Coin{coin},
+					EstimatedSize: 142,
+				}),
+			})
+			if test.err != nil {
+				assert.Nil(t, response)
+				assert.Equal(t, test.err.Code, err.Code)
+				assert.Equal(
+					t,
+					"coin b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1 is worth 1000000 but was provided with amount -500000", // nolint
+					err.Details["context"],
+				)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, forceMarshalMap(t, &constructionMetadata{
+					ScriptPubKeys: []*ravencoin.ScriptPubKey{script},
+					Fee:           minFee(142),
+				}), response.Metadata)
+			}
+
+			mockClient.AssertExpectations(t)
+			mockIndexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConstructionService_AssetCoinFee(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	// An RVN coin and an owner token coin (as spent
+	// by a REISSUE_ASSET operation).
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-60000000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "6d1b6e3fa8b7ad3c2ef1b3e6a1e7b2c9d0f4a5b6c7d8e9f0a1b2c3d4e5f60718:2",
+			},
+			Amount: &types.Amount{
+				Value:    "-100000000",
+				Currency: ravencoin.AssetCurrency("ROSETTA!", ravencoin.MaxAssetUnits),
+			},
+		},
+	}
+	scripts := []*ravencoin.ScriptPubKey{
+		{
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+		},
+		{
+			Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01572766e7408524f53455454412100e1f5050000000075", // nolint
+			RequiredSigs: 1,
+			Type:         ravencoin.TransferAssetType,
+			Addresses: []string{
+				"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+			},
+		},
+	}
+
+	mockIndexer.On("GetScriptPubKeys", ctx, coins).Return(scripts, nil).Once()
+	mockIndexer.On("GetCoinAmounts", ctx, coins).Return(indexedAmounts(t, coins), nil).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		0.0002,
+		nil,
+	).Once()
+
+	response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: forceMarshalMap(t, &preprocessOptions{
+			Coins:         coins,
+			EstimatedSize: 524,
+		}),
+	})
+	assert.Nil(t, err)
+
+	// 524 vbytes at 20 Satoshis per vbyte, paid in tRVN.
+	assert.Equal(t, []*types.Amount{
+		{
+			Value:    "10480",
+			Currency: ravencoin.TestnetCurrency,
+		},
+	}, response.SuggestedFee)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_MaxFee(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-60000000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scripts := []*ravencoin.ScriptPubKey{
+		{
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+		},
+	}
+
+	// 524 vbytes at 20 Satoshis per vbyte.
+	tests := map[string]struct {
+		maxFeeAmount int64
+
+		fee *types.Amount
+		err *types.Error
+	}{
+		"just under cap": {
+			maxFeeAmount: 10481,
+			fee: &types.Amount{
+				Value:    "10480",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+		"just over cap": {
+			maxFeeAmount: 10479,
+			err:          ErrFeeTooHigh,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:         configuration.Online,
+				Network:      networkIdentifier,
+				Params:       ravencoin.TestnetParams,
+				Currency:     ravencoin.TestnetCurrency,
+				MaxFeeAmount: test.maxFeeAmount,
+			}
+
+			mockIndexer := &mocks.Indexer{}
+			mockClient := &mocks.Client{}
+			servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+			ctx := context.Background()
+
+			mockIndexer.On("GetScriptPubKeys", ctx, coins).Return(scripts, nil).Once()
+			mockIndexer.On("GetCoinAmounts", ctx, coins).Return(indexedAmounts(t, coins), nil).Once()
+			mockClient.On(
+				"SuggestedFeeRatePerKB",
+				ctx,
+				defaultConfirmationTarget,
+			).Return(
+				0.0002,
+				nil,
+			).Once()
+
+			response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+				NetworkIdentifier: networkIdentifier,
+				Options: forceMarshalMap(t, &preprocessOptions{
+					Coins:         coins,
+					EstimatedSize: 524,
+				}),
+			})
+			if test.err != nil {
+				assert.Nil(t, response)
+				assert.Equal(t, test.err.Code, err.Code)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, []*types.Amount{test.fee}, response.SuggestedFee)
+			}
+
+			mockClient.AssertExpectations(t)
+			mockIndexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConstructionService_EffectiveFeeRate(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-60000000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scripts := []*ravencoin.ScriptPubKey{
+		{
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+		},
+	}
+
+	// The node fee rate is floored at MinFeeRate, so the
+	// fee is the (truncated) estimated size.
+	estimatedSize := 330000.5
+	mockIndexer.On("GetScriptPubKeys", ctx, coins).Return(scripts, nil).Once()
+	mockIndexer.On("GetCoinAmounts", ctx, coins).Return(indexedAmounts(t, coins), nil).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate/2,
+		nil,
+	).Once()
+
+	response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: forceMarshalMap(t, &preprocessOptions{
+			Coins:         coins,
+			EstimatedSize: estimatedSize,
+		}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []*types.Amount{
+		{
+			Value:    "330000",
+			Currency: ravencoin.TestnetCurrency,
+		},
+	}, response.SuggestedFee)
+
+	var metadata constructionMetadata
+	assert.NoError(t, types.UnmarshalMap(response.Metadata, &metadata))
+	assert.Equal(t, math.Round(330000/estimatedSize*100)/100, metadata.Fee.EffectiveFeeRate)
+	assert.Equal(t, float64(1), metadata.Fee.EffectiveFeeRate)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_SubmitDuplicate(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	ravencoinTransaction := "010000000001017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305c5a55741b10100000000ffffffff02db910e000000000016001488ce6925f8513a234c05c922ee933f221323052071ae000000000000160014940726595c41fca0b4810c62991ad9d289eeb82802473044022025876ec8b9f51d343a5a56ac549c0c828005ef45ebe9da166db645c09157223f02204cd08b7278a8889a81135915bce10d1ef3bb92b217f81a0de7e79ffb3dfd6ac501210325c9a4252789b31dbb3454ec647e9516e7c596bcde2bd5da71a60fab8644e43800000000" // nolint
+	signed, err := json.Marshal(&signedTransaction{
+		Transaction:  ravencoinTransaction,
+		InputAmounts: []string{"-1000000"},
+	})
+	assert.NoError(t, err)
+
+	tests := map[string]struct {
+		sendErr error
+
+		expectedHash  string
+		expectedError *types.Error
+	}{
+		"already in mempool": {
+			sendErr: fmt.Errorf(
+				"%w: txn-already-in-mempool",
+				ravencoin.ErrTransactionAlreadyKnown,
+			),
+			expectedHash: "6d87ad0e26025128f5a8357fa423b340cbcffb9703f79f432f5520fca59cd20b",
+		},
+		"already in chain": {
+			sendErr: fmt.Errorf(
+				"%w: transaction already in block chain",
+				ravencoin.ErrTransactionAlreadyKnown,
+			),
+			expectedHash: "6d87ad0e26025128f5a8357fa423b340cbcffb9703f79f432f5520fca59cd20b",
+		},
+		"rejected": {
+			sendErr: fmt.Errorf(
+				"%w: error JSON RPC response, code: -26, message: min relay fee not met",
+				ravencoin.ErrJSONRPCError,
+			),
+			expectedError: ErrRavend,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockIndexer := &mocks.Indexer{}
+			mockClient := &mocks.Client{}
+			servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+			ctx := context.Background()
+
+			mockClient.On(
+				"SendRawTransaction",
+				ctx,
+				ravencoinTransaction,
+			).Return(
+				"",
+				test.sendErr,
+			).Once()
+			submitResponse, err := servicer.ConstructionSubmit(ctx, &types.ConstructionSubmitRequest{
+				NetworkIdentifier: networkIdentifier,
+				SignedTransaction: hex.EncodeToString(signed),
+			})
+			if test.expectedError != nil {
+				assert.Nil(t, submitResponse)
+				assert.Equal(t, test.expectedError.Code, err.Code)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, &types.TransactionIdentifierResponse{
+					TransactionIdentifier: &types.TransactionIdentifier{
+						Hash: test.expectedHash,
+					},
+				}, submitResponse)
+			}
+
+			mockClient.AssertExpectations(t)
+			mockIndexer.AssertExpectations(t)
+		})
+	}
+}
+
+func TestConstructionService_InsufficientFunds(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	ops := func(output string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    output,
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		}
+	}
+
+	// 12 overhead + 68 P2WPKH input + 31 P2WPKH output
+	// is 111 vbytes (so 111 Satoshis at MinFeeRate).
+	tests := map[string]struct {
+		output string
+
+		err *types.Error
+	}{
+		"covers minimum fee": {
+			output: "999889",
+		},
+		"below minimum fee": {
+			output: "999900",
+			err: &types.Error{
+				Code:    ErrInsufficientFunds.Code,
+				Message: ErrInsufficientFunds.Message,
+				Details: map[string]interface{}{
+					"context":   "available coins do not cover outputs and fee: 1000011 required but 1000000 available",
+					"required":  "1000011",
+					"available": "1000000",
+				},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			preprocessResponse, err := servicer.ConstructionPreprocess(
+				ctx,
+				&types.ConstructionPreprocessRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops(test.output),
+				},
+			)
+			if test.err != nil {
+				assert.Nil(t, preprocessResponse)
+				assert.Equal(t, test.err, err)
+			} else {
+				assert.Nil(t, err)
+				assert.NotNil(t, preprocessResponse)
+			}
+		})
+	}
+}
+
+func TestRegisterHDVersionBytes(t *testing.T) {
+	// SLIP-0132 Vpub/Vprv (testnet P2WSH multisig)
+	pub := [4]byte{0x02, 0x57, 0x54, 0x83}
+	priv := [4]byte{0x02, 0x57, 0x50, 0x48}
+
+	_, err := chaincfg.HDPrivateKeyToPublicKeyID(priv[:])
+	assert.True(t, errors.Is(err, chaincfg.ErrUnknownHDKeyID))
+
+	cfg := &configuration.Configuration{
+		Mode:           configuration.Offline,
+		Params:         ravencoin.TestnetParams,
+		Currency:       ravencoin.TestnetCurrency,
+		HDVersionBytes: [][2][4]byte{{pub, priv}},
+	}
+	assert.NoError(t, RegisterHDVersionBytes(cfg))
+
+	id, err := chaincfg.HDPrivateKeyToPublicKeyID(priv[:])
+	assert.NoError(t, err)
+	assert.Equal(t, pub[:], id)
+}
+
+func TestConstructionService_ParseUnbalanced(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	// The output is worth 1 Satoshi more than the input.
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "1000001",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		},
+		Metadata: forceMarshalMap(t, &constructionMetadata{
+			ScriptPubKeys: []*ravencoin.ScriptPubKey{
+				{
+					ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+					Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+					RequiredSigs: 1,
+					Type:         "witness_v0_keyhash",
+					Addresses: []string{
+						"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+					},
+				},
+			},
+		}),
+	})
+	assert.Nil(t, err)
+
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, parseResponse)
+	assert.Equal(t, ErrUnbalancedTransaction.Code, err.Code)
+	assert.Equal(t, "outputs of 1000001 exceed inputs of 1000000 by 1", err.Details["context"])
+
+	// Without input amounts, the balance cannot be checked.
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+	unsigned.InputAmounts = nil
+	rawUnsigned, marshalErr := json.Marshal(&unsigned)
+	assert.NoError(t, marshalErr)
+
+	parseResponse, err = servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       hex.EncodeToString(rawUnsigned),
+	})
+	assert.Nil(t, err)
+	assert.Len(t, parseResponse.Operations, 2)
+	assert.Nil(t, parseResponse.Operations[0].Amount)
+}
+
+func TestConstructionService_ParseMalformed(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	// A single input paying 50 tRVN to tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7.
+	rawTx := "01000000017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305c5a55741b10100000000ffffffff0100f2052a0100000016001488ce6925f8513a234c05c922ee933f221323052000000000" // nolint
+	envelope := func(fields map[string]interface{}) string {
+		raw, err := json.Marshal(fields)
+		assert.NoError(t, err)
+		return hex.EncodeToString(raw)
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			Hex:  "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			Type: "witness_v0_keyhash",
+		},
+	}
+	inputAddresses := []string{"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm"}
+
+	tests := map[string]struct {
+		signed      bool
+		transaction string
+
+		context string
+	}{
+		"truncated hex": {
+			transaction: envelope(map[string]interface{}{
+				"transaction": rawTx,
+			})[:41],
+			context: "encoding/hex: odd length hex string: unsigned transaction is not valid hex",
+		},
+		"non-JSON bytes": {
+			transaction: hex.EncodeToString([]byte("not a transaction")),
+			context:     "invalid character 'o' in literal null (expecting 'u'): unsigned transaction is not a valid envelope", // nolint
+		},
+		"missing transaction": {
+			transaction: envelope(map[string]interface{}{
+				"scriptPubKeys":   scriptPubKeys,
+				"input_addresses": inputAddresses,
+			}),
+			context: "unsigned transaction envelope is missing transaction",
+		},
+		"missing scriptPubKeys": {
+			transaction: envelope(map[string]interface{}{
+				"transaction":     rawTx,
+				"input_addresses": inputAddresses,
+			}),
+			context: "unsigned transaction envelope is missing scriptPubKeys",
+		},
+		"missing input_addresses": {
+			transaction: envelope(map[string]interface{}{
+				"transaction":   rawTx,
+				"scriptPubKeys": scriptPubKeys,
+			}),
+			context: "unsigned transaction envelope has 0 input_addresses for 1 inputs",
+		},
+		"truncated transaction": {
+			transaction: envelope(map[string]interface{}{
+				"transaction":     rawTx[:80],
+				"scriptPubKeys":   scriptPubKeys,
+				"input_addresses": inputAddresses,
+			}),
+			context: "unexpected EOF: unable to deserialize transaction",
+		},
+		"signed non-JSON bytes": {
+			signed:      true,
+			transaction: hex.EncodeToString([]byte("not a transaction")),
+			context:     "invalid character 'o' in literal null (expecting 'u'): signed transaction is not a valid envelope", // nolint
+		},
+		"signed missing transaction": {
+			signed: true,
+			transaction: envelope(map[string]interface{}{
+				"input_amounts": []string{"-1000000"},
+			}),
+			context: "signed transaction envelope is missing transaction",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+				NetworkIdentifier: networkIdentifier,
+				Signed:            test.signed,
+				Transaction:       test.transaction,
+			})
+			assert.Nil(t, parseResponse)
+			assert.Equal(t, ErrUnparseableTransaction.Code, err.Code)
+			assert.Equal(t, test.context, err.Details["context"])
+		})
+	}
+}
+
+func TestConstructionService_ParseMultisig(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	// The input is signed by the first and third keys of a 2-of-3
+	// P2SH-multisig redeem script.
+	signed, err := json.Marshal(&signedTransaction{
+		Transaction: "01000000017f9cf50b02dd5258f80cd5c3437302e027dd1336172a20cdc80305" +
+			"c5a55741b101000000fdfd0000483045022100bbff424738692a125d2ddd99ba" +
+			"d29b83921a52b534c6dc931b89e5b22174a52802204777e43930409ff48ac13a" +
+			"36d93d02e5355f3e2442139495a6fdccba02c24f4e0147304402207c3cdb456c" +
+			"9135f720532b5e087ea5226749dffec264a67dbfbae605ddb823f6022071f0fc" +
+			"a35f9e96d22a84e04470dbe862d04d5f9493e86e69b9d7f43225349a68014c69" +
+			"5221026814a32cd8340222adb5fe5809725aeb225d69ae327ee2fc240571d6b2" +
+			"bf32df2103b5b294289a63f3b02c58a66635713d52aedb1737ebfd2b7fc0ed4e" +
+			"9e0649b2e721025ed3557ffc2904508c7308c3ee901da7b61ff29377277a3917" +
+			"1fec95a9d10f9f53aeffffffff01583e0f00000000001976a914c005b00ad075" +
+			"d30b89a7b65b7dad8899ba6a9c5588ac00000000",
+		InputAmounts: []string{"-1000000"},
+	})
+	assert.NoError(t, err)
+
+	parseResponse, parseErr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       hex.EncodeToString(signed),
+	})
+	assert.Nil(t, parseErr)
+	assert.Len(t, parseResponse.Operations, 2)
+	assert.Equal(t, "2MuWok8zNQUyhxBQAemNnh9x7kVwkxKX4jE", parseResponse.Operations[0].Account.Address)
+	assert.Equal(t, "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj", parseResponse.Operations[1].Account.Address)
+	assert.Equal(t, []*types.AccountIdentifier{
+		{Address: "moHJPn7VKmPyXz9vCFMHWFeYpBaTCoWfqs"},
+		{Address: "mzmJDrUZhuqHbSTP773vbhRLLv3KDw1neX"},
+	}, parseResponse.AccountIdentifierSigners)
+}
+
+func TestConstructionService_ParseScriptPubKeys(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	// The third byte of the public key of this private key
+	// is 0x02, so the last 33 bytes of the signature script
+	// of a 1-of-1 multisig input look like a compressed
+	// public key.
+	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), append(make([]byte, 31), 0xd6))
+	signer, err := btcutil.NewAddressPubKeyHash(
+		btcutil.Hash160(pubKey.SerializeCompressed()),
+		cfg.Params.BtcdParams(),
+	)
+	assert.NoError(t, err)
+
+	// The signature script of a 1-of-1 P2SH-multisig input
+	// is as long as that of a P2PKH input, so the output it
+	// spends is ambiguous.
+	redeemScript, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_1).
+		AddData(pubKey.SerializeCompressed()).
+		AddOp(txscript.OP_1).
+		AddOp(txscript.OP_CHECKMULTISIG).
+		Script()
+	assert.NoError(t, err)
+	p2sh, err := btcutil.NewAddressScriptHash(redeemScript, cfg.Params.BtcdParams())
+	assert.NoError(t, err)
+	p2shScript, err := txscript.PayToAddrScript(p2sh)
+	assert.NoError(t, err)
+	signerScript, err := txscript.PayToAddrScript(signer)
+	assert.NoError(t, err)
+
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&chainhash.Hash{0x01}, 0), nil, nil))
+	tx.AddTxOut(wire.NewTxOut(990000, signerScript))
+	sig, err := txscript.RawTxInSignature(tx, 0, redeemScript, txscript.SigHashAll, privKey)
+	assert.NoError(t, err)
+	tx.TxIn[0].SignatureScript, err = txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(sig).
+		AddData(redeemScript).
+		Script()
+	assert.NoError(t, err)
+
+	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+	assert.NoError(t, tx.Serialize(buf))
+
+	tests := map[string]struct {
+		scriptPubKeys []*ravencoin.ScriptPubKey
+
+		expectedAddress string
+		expectedSigners []*types.AccountIdentifier
+		expectedError   *types.Error
+	}{
+		"p2sh funding script": {
+			scriptPubKeys: []*ravencoin.ScriptPubKey{
+				{Hex: hex.EncodeToString(p2shScript)},
+			},
+			expectedAddress: p2sh.EncodeAddress(),
+			expectedSigners: []*types.AccountIdentifier{
+				{Address: signer.EncodeAddress()},
+			},
+		},
+		"p2pkh funding script": {
+			scriptPubKeys: []*ravencoin.ScriptPubKey{
+				{Hex: hex.EncodeToString(signerScript)},
+			},
+			expectedAddress: signer.EncodeAddress(),
+			expectedSigners: []*types.AccountIdentifier{
+				{Address: signer.EncodeAddress()},
+			},
+		},
+		"too many funding scripts": {
+			scriptPubKeys: []*ravencoin.ScriptPubKey{
+				{Hex: hex.EncodeToString(p2shScript)},
+				{Hex: hex.EncodeToString(p2shScript)},
+			},
+			expectedError: ErrUnableToDecodeScriptPubKey,
+		},
+		"invalid funding script": {
+			scriptPubKeys: []*ravencoin.ScriptPubKey{
+				{Hex: "zz"},
+			},
+			expectedError: ErrUnableToDecodeScriptPubKey,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			signed, err := json.Marshal(&signedTransaction{
+				Transaction:   hex.EncodeToString(buf.Bytes()),
+				InputAmounts:  []string{"-1000000"},
+				ScriptPubKeys: test.scriptPubKeys,
+			})
+			assert.NoError(t, err)
+
+			parseResponse, parseErr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+				NetworkIdentifier: networkIdentifier,
+				Signed:            true,
+				Transaction:       hex.EncodeToString(signed),
+			})
+			if test.expectedError != nil {
+				assert.Nil(t, parseResponse)
+				assert.Equal(t, test.expectedError.Code, parseErr.Code)
+				return
+			}
+
+			assert.Nil(t, parseErr)
+			assert.Len(t, parseResponse.Operations, 2)
+			assert.Equal(t, test.expectedAddress, parseResponse.Operations[0].Account.Address)
+			assert.Equal(t, test.expectedSigners, parseResponse.AccountIdentifierSigners)
+		})
+	}
+
+	// Without the funding script, the input is
+	// mistaken for a P2PKH input.
+	signed, err := json.Marshal(&signedTransaction{
+		Transaction:  hex.EncodeToString(buf.Bytes()),
+		InputAmounts: []string{"-1000000"},
+	})
+	assert.NoError(t, err)
+
+	parseResponse, parseErr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       hex.EncodeToString(signed),
+	})
+	assert.Nil(t, parseErr)
+	assert.NotEqual(t, p2sh.EncodeAddress(), parseResponse.Operations[0].Account.Address)
+
+	// Combine carries the funding scripts into the signed
+	// envelope, so the asset spent by a coin (which is not
+	// in its input amount) is recovered by parse.
+	asset := ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)
+	assetScript, err := ravencoin.TransferAssetScript(signerScript, "ROSETTA", 10000000000)
+	assert.NoError(t, err)
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: signer.EncodeAddress(),
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: signer.EncodeAddress(),
+			},
+			Amount: &types.Amount{
+				Value:    "-10000000000",
+				Currency: asset,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:2",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 2,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: signer.EncodeAddress(),
+			},
+			Amount: &types.Amount{
+				Value:    "990000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 3,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: signer.EncodeAddress(),
+			},
+			Amount: &types.Amount{
+				Value:    "10000000000",
+				Currency: asset,
+			},
+		},
+	}
+	scriptPubKeys := []*ravencoin.ScriptPubKey{
+		{
+			Hex:          hex.EncodeToString(signerScript),
+			RequiredSigs: 1,
+			Type:         "pubkeyhash",
+			Addresses:    []string{signer.EncodeAddress()},
+		},
+		{
+			Hex:          hex.EncodeToString(assetScript),
+			RequiredSigs: 1,
+			Type:         ravencoin.TransferAssetType,
+			Addresses:    []string{signer.EncodeAddress()},
+		},
+	}
+
+	payloadsResponse, rosettaErr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata: forceMarshalMap(t, &constructionMetadata{
+			ScriptPubKeys: scriptPubKeys,
+		}),
+	})
+	assert.Nil(t, rosettaErr)
+
+	signatures := make([]*types.Signature, len(payloadsResponse.Payloads))
+	for i, payload := range payloadsResponse.Payloads {
+		signatures[i] = &types.Signature{
+			Bytes:          forceSign(t, privKey, payload.Bytes),
+			SigningPayload: payload,
+			PublicKey: &types.PublicKey{
+				Bytes:     pubKey.SerializeCompressed(),
+				CurveType: types.Secp256k1,
+			},
+			SignatureType: types.Ecdsa,
+		}
+	}
+
+	combineResponse, rosettaErr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures:          signatures,
+	})
+	assert.Nil(t, rosettaErr)
+
+	signedEnvelope, _, err := decodeSignedTransaction(combineResponse.SignedTransaction)
+	assert.NoError(t, err)
+	assert.Equal(t, scriptPubKeys, signedEnvelope.ScriptPubKeys)
+
+	parseResponse, parseErr = servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       combineResponse.SignedTransaction,
+	})
+	assert.Nil(t, parseErr)
+	assert.Len(t, parseResponse.Operations, len(ops))
+	for i, op := range parseResponse.Operations {
+		assert.Equal(t, ops[i].Account, op.Account)
+		assert.Equal(t, ops[i].Amount, op.Amount)
+	}
+	assert.Equal(
+		t,
+		[]*types.AccountIdentifier{
+			{Address: signer.EncodeAddress()},
+			{Address: signer.EncodeAddress()},
+		},
+		parseResponse.AccountIdentifierSigners,
+	)
+}
+
+func TestConstructionService_PayToPubKey(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	// Coinbase outputs of early blocks pay
+	// directly to a public key.
+	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{1}, 32))
+	pubKeyBytes := pubKey.SerializeCompressed()
+	script, err := txscript.NewScriptBuilder().
+		AddData(pubKeyBytes).
+		AddOp(txscript.OP_CHECKSIG).
+		Script()
+	assert.NoError(t, err)
+
+	addr, err := btcutil.NewAddressPubKey(pubKeyBytes, ravencoin.TestnetParams.BtcdParams())
+	assert.NoError(t, err)
+	address := addr.EncodeAddress()
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    "-5000000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:0",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 1,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "4999990000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+
+	payloadsResponse, rosettaErr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops,
+		Metadata: forceMarshalMap(t, &constructionMetadata{
+			ScriptPubKeys: []*ravencoin.ScriptPubKey{
+				{
+					ASM:          hex.EncodeToString(pubKeyBytes) + " OP_CHECKSIG",
+					Hex:          hex.EncodeToString(script),
+					RequiredSigs: 1,
+					Type:         "pubkey",
+					Addresses: []string{
+						address,
+					},
+				},
+			},
+		}),
+	})
+	assert.Nil(t, rosettaErr)
+	assert.Len(t, payloadsResponse.Payloads, 1)
+
+	// The signing payload commits to the full
+	// pubkey script.
+	_, unsignedTx, err := decodeUnsignedTransaction(payloadsResponse.UnsignedTransaction)
+	assert.NoError(t, err)
+	hash, err := txscript.CalcSignatureHash(script, txscript.SigHashAll, unsignedTx, 0)
+	assert.NoError(t, err)
+	payload := payloadsResponse.Payloads[0]
+	assert.Equal(t, hash, payload.Bytes)
+	assert.Equal(t, address, payload.AccountIdentifier.Address)
+
+	sigBytes := forceSign(t, privKey, payload.Bytes)
+	combineResponse, rosettaErr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+		NetworkIdentifier:   networkIdentifier,
+		UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+		Signatures: []*types.Signature{
+			{
+				Bytes:          sigBytes,
+				SigningPayload: payload,
+				PublicKey: &types.PublicKey{
+					Bytes:     pubKeyBytes,
+					CurveType: types.Secp256k1,
+				},
+				SignatureType: types.Ecdsa,
+			},
+		},
+	})
+	assert.Nil(t, rosettaErr)
+
+	// Only the signature is pushed and it
+	// satisfies the pubkey script.
+	_, signedTx, err := decodeSignedTransaction(combineResponse.SignedTransaction)
+	assert.NoError(t, err)
+	pushes, err := txscript.PushedData(signedTx.TxIn[0].SignatureScript)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{normalizeSignature(sigBytes, txscript.SigHashAll)}, pushes)
+	assert.Empty(t, signedTx.TxIn[0].Witness)
+
+	vm, err := txscript.NewEngine(
+		script,
+		signedTx,
+		0,
+		txscript.StandardVerifyFlags,
+		nil,
+		nil,
+		5000000000,
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, vm.Execute())
+
+	parseSignedResponse, rosettaErr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            true,
+		Transaction:       combineResponse.SignedTransaction,
+	})
+	assert.Nil(t, rosettaErr)
+	assert.Equal(
+		t,
+		[]*types.AccountIdentifier{{Address: address}},
+		parseSignedResponse.AccountIdentifierSigners,
+	)
+	assert.Equal(t, address, parseSignedResponse.Operations[0].Account.Address)
+}
+
+func TestConstructionService_Segwit(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	privKey, pubKey := btcec.PrivKeyFromBytes(btcec.S256(), bytes.Repeat([]byte{2}, 32))
+	publicKey := &types.PublicKey{
+		Bytes:     pubKey.SerializeCompressed(),
+		CurveType: types.Secp256k1,
+	}
+	pubKeyHash := btcutil.Hash160(publicKey.Bytes)
+	witnessProgram, err := txscript.NewScriptBuilder().
+		AddOp(txscript.OP_0).
+		AddData(pubKeyHash).
+		Script()
+	assert.NoError(t, err)
+
+	params := ravencoin.TestnetParams.BtcdParams()
+	witnessAddr, err := btcutil.NewAddressWitnessPubKeyHash(pubKeyHash, params)
+	assert.NoError(t, err)
+	nestedAddr, err := btcutil.NewAddressScriptHash(witnessProgram, params)
+	assert.NoError(t, err)
+	nestedScript, err := txscript.PayToAddrScript(nestedAddr)
+	assert.NoError(t, err)
+	nestedSigScript, err := txscript.NewScriptBuilder().AddData(witnessProgram).Script()
+	assert.NoError(t, err)
+
+	ops := func(address string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: address,
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		address    string
+		script     []byte
+		scriptType string
+
+		sigScript []byte
+	}{
+		"p2wpkh": {
+			address:    witnessAddr.EncodeAddress(),
+			script:     witnessProgram,
+			scriptType: "witness_v0_keyhash",
+		},
+		"p2sh-p2wpkh": {
+			address:    nestedAddr.EncodeAddress(),
+			script:     nestedScript,
+			scriptType: "scripthash",
+			sigScript:  nestedSigScript,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			payloadsResponse, rosettaErr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+				NetworkIdentifier: networkIdentifier,
+				Operations:        ops(test.address),
+				Metadata: forceMarshalMap(t, &constructionMetadata{
+					ScriptPubKeys: []*ravencoin.ScriptPubKey{
+						{
+							Hex:          hex.EncodeToString(test.script),
+							RequiredSigs: 1,
+							Type:         test.scriptType,
+							Addresses: []string{
+								test.address,
+							},
+						},
+					},
+				}),
+				PublicKeys: []*types.PublicKey{publicKey},
+			})
+			assert.Nil(t, rosettaErr)
+			assert.Len(t, payloadsResponse.Payloads, 1)
+
+			// The signing payload is the BIP143 hash of
+			// the witness program.
+			_, unsignedTx, err := decodeUnsignedTransaction(payloadsResponse.UnsignedTransaction)
+			assert.NoError(t, err)
+			hash, err := txscript.CalcWitnessSigHash(
+				witnessProgram,
+				txscript.NewTxSigHashes(unsignedTx),
+				txscript.SigHashAll,
+				unsignedTx,
+				0,
+				1000000,
+			)
+			assert.NoError(t, err)
+			payload := payloadsResponse.Payloads[0]
+			assert.Equal(t, hash, payload.Bytes)
+
+			sigBytes := forceSign(t, privKey, payload.Bytes)
+			combineResponse, rosettaErr := servicer.ConstructionCombine(ctx, &types.ConstructionCombineRequest{
+				NetworkIdentifier:   networkIdentifier,
+				UnsignedTransaction: payloadsResponse.UnsignedTransaction,
+				Signatures: []*types.Signature{
+					{
+						Bytes:          sigBytes,
+						SigningPayload: payload,
+						PublicKey:      publicKey,
+						SignatureType:  types.Ecdsa,
+					},
+				},
+			})
+			assert.Nil(t, rosettaErr)
+
+			_, signedTx, err := decodeSignedTransaction(combineResponse.SignedTransaction)
+			assert.NoError(t, err)
+			assert.True(t, bytes.Equal(test.sigScript, signedTx.TxIn[0].SignatureScript))
+			assert.Equal(t, wire.TxWitness{
+				normalizeSignature(sigBytes, txscript.SigHashAll),
+				publicKey.Bytes,
+			}, signedTx.TxIn[0].Witness)
+
+			vm, err := txscript.NewEngine(
+				test.script,
+				signedTx,
+				0,
+				txscript.StandardVerifyFlags,
+				nil,
+				nil,
+				1000000,
+			)
+			assert.NoError(t, err)
+			assert.NoError(t, vm.Execute())
+
+			// The txid does not commit to the witness.
+			hashResponse, rosettaErr := servicer.ConstructionHash(ctx, &types.ConstructionHashRequest{
+				NetworkIdentifier: networkIdentifier,
+				SignedTransaction: combineResponse.SignedTransaction,
+			})
+			assert.Nil(t, rosettaErr)
+			assert.Equal(t, unsignedTx.TxHash().String(), hashResponse.TransactionIdentifier.Hash)
+			assert.Equal(t, signedTx.TxHash().String(), hashResponse.TransactionIdentifier.Hash)
+			assert.NotEqual(t, signedTx.WitnessHash(), signedTx.TxHash())
+
+			parseSignedResponse, rosettaErr := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+				NetworkIdentifier: networkIdentifier,
+				Signed:            true,
+				Transaction:       combineResponse.SignedTransaction,
+			})
+			assert.Nil(t, rosettaErr)
+			assert.Equal(
+				t,
+				[]*types.AccountIdentifier{{Address: test.address}},
+				parseSignedResponse.AccountIdentifierSigners,
+			)
+			assert.Equal(t, test.address, parseSignedResponse.Operations[0].Account.Address)
+		})
+	}
+
+	// P2SH-P2WPKH payloads require the public key.
+	payloadsResponse, rosettaErr := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops(nestedAddr.EncodeAddress()),
+		Metadata: forceMarshalMap(t, &constructionMetadata{
+			ScriptPubKeys: []*ravencoin.ScriptPubKey{
+				{
+					Hex:          hex.EncodeToString(nestedScript),
+					RequiredSigs: 1,
+					Type:         "scripthash",
+				},
+			},
+		}),
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnableToCalculateSignatureHash.Code, rosettaErr.Code)
+}
+
+func TestConstructionService_ShuffleOutputs(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	output := func(index int64, address string, value string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: address,
+			},
+			Amount: &types.Amount{
+				Value:    value,
+				Currency: ravencoin.TestnetCurrency,
+			},
+		}
+	}
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				CoinAction: types.CoinSpent,
+			},
+		},
+		output(1, "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7", "100000"),
+		output(2, "tb1qjsrjvk2ug872pdypp33fjxke62y7awpgefr6ua", "200000"),
+		output(3, "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj", "690000"), // change
+	}
+
+	payloads := func(shuffle bool, nonce int64) (*types.ConstructionPayloadsResponse, *wire.MsgTx) {
+		payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &constructionMetadata{
+				ScriptPubKeys: []*ravencoin.ScriptPubKey{
+					{
+						ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+						Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+						RequiredSigs: 1,
+						Type:         "witness_v0_keyhash",
+						Addresses: []string{
+							"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+						},
+					},
+				},
+				ShuffleOutputs: shuffle,
+				ShuffleNonce:   nonce,
+			}),
+		})
+		assert.Nil(t, err)
+
+		var unsigned unsignedTransaction
+		assert.NoError(t, json.Unmarshal(
+			forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+			&unsigned,
+		))
+
+		var tx wire.MsgTx
+		assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+		return payloadsResponse, &tx
+	}
+
+	values := func(tx *wire.MsgTx) []int64 {
+		values := make([]int64, len(tx.TxOut))
+		for i, output := range tx.TxOut {
+			values[i] = output.Value
+		}
+
+		return values
+	}
+
+	// Outputs are in operation order by default.
+	_, tx := payloads(false, 42)
+	assert.Equal(t, []int64{100000, 200000, 690000}, values(tx))
+
+	// The same nonce always produces the same order
+	// (and therefore the same signing payloads).
+	shuffledResponse, shuffledTx := payloads(true, 42)
+	assert.Equal(t, []int64{690000, 100000, 200000}, values(shuffledTx))
+
+	repeatedResponse, repeatedTx := payloads(true, 42)
+	assert.Equal(t, shuffledResponse, repeatedResponse)
+	assert.Equal(t, shuffledTx.TxHash(), repeatedTx.TxHash())
+	assert.NotEqual(t, tx.TxHash(), shuffledTx.TxHash())
+
+	_, otherTx := payloads(true, 7)
+	assert.Equal(t, []int64{200000, 100000, 690000}, values(otherTx))
+
+	// Parsing assigns network indexes by position,
+	// so the change is the first output.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       shuffledResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, parseResponse.Operations, 4)
+	for i, value := range []string{"690000", "100000", "200000"} {
+		op := parseResponse.Operations[i+1]
+		assert.Equal(t, value, op.Amount.Value)
+		assert.Equal(t, int64(i), *op.OperationIdentifier.NetworkIndex)
+	}
+	assert.Equal(t, "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj", parseResponse.Operations[1].Account.Address)
+}
+
+func TestConstructionService_OperationTypes(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+		OperationTypes: &configuration.OperationTypes{
+			Input:        "RVN_INPUT",
+			Output:       "RVN_OUTPUT",
+			IssueAsset:   "RVN_ISSUE_ASSET",
+			ReissueAsset: "RVN_REISSUE_ASSET",
+		},
+	}
+
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	ops := func(inputType string, outputType string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: inputType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: outputType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		}
+	}
+	metadata := forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+				Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+				RequiredSigs: 1,
+				Type:         "witness_v0_keyhash",
+				Addresses: []string{
+					"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+			},
+		},
+	})
+
+	// The default operation types are not accepted.
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops(ravencoin.InputOpType, ravencoin.OutputOpType),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	customOps := ops("RVN_INPUT", "RVN_OUTPUT")
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        customOps,
+		},
+	)
+	assert.Nil(t, err)
+	assert.NotNil(t, preprocessResponse)
+
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        customOps,
+		Metadata:          metadata,
+	})
+	assert.Nil(t, err)
+
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, parseResponse.Operations, 2)
+	assert.Equal(t, "RVN_INPUT", parseResponse.Operations[0].Type)
+	assert.Equal(t, "RVN_OUTPUT", parseResponse.Operations[1].Type)
+	assert.Equal(t, customOps[1].Amount, parseResponse.Operations[1].Amount)
+}
+
+func TestEstimateVSize(t *testing.T) {
+	legacy := &ravencoin.ScriptPubKey{Type: "pubkeyhash"}
+	segwit := &ravencoin.ScriptPubKey{Type: "witness_v0_keyhash"}
+	nested := &ravencoin.ScriptPubKey{Type: "scripthash"}
+
+	tests := map[string]struct {
+		inputs     []*ravencoin.ScriptPubKey
+		numOutputs int
+
+		size int
+	}{
+		"legacy inputs": {
+			inputs:     []*ravencoin.ScriptPubKey{legacy, legacy},
+			numOutputs: 2,
+			size:       376, // 12 + 2 * 148 + 2 * 34
+		},
+		"segwit inputs": {
+			inputs:     []*ravencoin.ScriptPubKey{segwit, segwit},
+			numOutputs: 2,
+			size:       216, // 12 + 2 * 68 + 2 * 34
+		},
+		"nested segwit inputs": {
+			inputs:     []*ravencoin.ScriptPubKey{nested, nested},
+			numOutputs: 2,
+			size:       262, // 12 + 2 * 91 + 2 * 34
+		},
+		"mixed inputs": {
+			inputs:     []*ravencoin.ScriptPubKey{legacy, segwit, nested},
+			numOutputs: 1,
+			size:       353, // 12 + 148 + 68 + 91 + 34
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, test.size, estimateVSize(test.inputs, test.numOutputs))
+		})
+	}
+}
+
+func TestConstructionService_SigHashType(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	input := func(index int64, coin string) *types.Operation {
+		return &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: index,
+			},
+			Type: ravencoin.InputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			},
+			Amount: &types.Amount{
+				Value:    "-1000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+			CoinChange: &types.CoinChange{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: coin,
+				},
+				CoinAction: types.CoinSpent,
+			},
+		}
+	}
+	ops := []*types.Operation{
+		input(0, "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:0"),
+		input(1, "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1"),
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 2,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "1999000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scriptPubKey := &ravencoin.ScriptPubKey{
+		ASM:          "0 c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+		Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+		RequiredSigs: 1,
+		Type:         "witness_v0_keyhash",
+		Addresses: []string{
+			"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+		},
+	}
+
+	payloads := func(sigHashType string) (*types.ConstructionPayloadsResponse, *types.Error) {
+		return servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops,
+			Metadata: forceMarshalMap(t, &constructionMetadata{
+				ScriptPubKeys: []*ravencoin.ScriptPubKey{scriptPubKey, scriptPubKey},
+				SigHashType:   sigHashType,
+			}),
+		})
+	}
+
+	allResponse, err := payloads("")
+	assert.Nil(t, err)
+	assert.Len(t, allResponse.Payloads, 2)
+
+	explicitAllResponse, err := payloads("ALL")
+	assert.Nil(t, err)
+	assert.Equal(t, allResponse.Payloads, explicitAllResponse.Payloads)
+
+	// ANYONECANPAY only commits to the input being
+	// signed, so each input has a different payload.
+	anyoneCanPayResponse, err := payloads("ALL|ANYONECANPAY")
+	assert.Nil(t, err)
+	assert.Len(t, anyoneCanPayResponse.Payloads, 2)
+	for i := range anyoneCanPayResponse.Payloads {
+		assert.NotEqual(t, allResponse.Payloads[i].Bytes, anyoneCanPayResponse.Payloads[i].Bytes)
+	}
+
+	// The sighash type is carried to /construction/combine.
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, anyoneCanPayResponse.UnsignedTransaction),
+		&unsigned,
+	))
+	assert.Equal(t, "ALL|ANYONECANPAY", unsigned.SigHashType)
+
+	invalidResponse, err := payloads("ANYONECANPAY")
+	assert.Nil(t, invalidResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+}
+
+func TestConstructionService_DeriveWIF(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Offline,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	publicKey := &types.PublicKey{
+		Bytes:     forceHexDecode(t, "0236a97b1bca7e6af7dd9899330f26c7fe2abc03dac2b125e45119e4c810aba952"),
+		CurveType: types.Secp256k1,
+	}
+	otherPublicKey := &types.PublicKey{
+		Bytes:     forceHexDecode(t, "0325c9a4252789b31dbb3454ec647e9516e7c596bcde2bd5da71a60fab8644e438"),
+		CurveType: types.Secp256k1,
+	}
+
+	tests := map[string]struct {
+		publicKey *types.PublicKey
+		wif       string
+
+		address   string
+		errorCode int32
+	}{
+		"testnet wif": {
+			wif:     "cVyvMQSzsprp3Jdnr7zcVja84y2QsrmAneSSWA5EJ2Cp89SVTT8b",
+			address: "tb1qqrgjgs5k6hq9qa83c5rqd5rw3nj2m4enrtsmft",
+		},
+		"testnet wif with matching public key": {
+			publicKey: publicKey,
+			wif:       "cVyvMQSzsprp3Jdnr7zcVja84y2QsrmAneSSWA5EJ2Cp89SVTT8b",
+			address:   "tb1qqrgjgs5k6hq9qa83c5rqd5rw3nj2m4enrtsmft",
+		},
+		"uncompressed testnet wif": {
+			wif:     "93VJXTA4bdDePj4MMeP7gb7bgR2E7YDGp3bK1iUhAkKY4uSavxh",
+			address: "tb1qqrgjgs5k6hq9qa83c5rqd5rw3nj2m4enrtsmft",
+		},
+		"public key": {
+			publicKey: publicKey,
+			address:   "tb1qqrgjgs5k6hq9qa83c5rqd5rw3nj2m4enrtsmft",
+		},
+		"uncompressed public key": {
+			publicKey: &types.PublicKey{
+				Bytes: forceHexDecode(
+					t,
+					"0436a97b1bca7e6af7dd9899330f26c7fe2abc03dac2b125e45119e4c810aba952"+
+						"5b535a5c70ca1d00cbdb77b9aa2236c94dcfa717aaa14147e6ae7ca6e8951a18",
+				),
+				CurveType: types.Secp256k1,
+			},
+			address: "tb1qqrgjgs5k6hq9qa83c5rqd5rw3nj2m4enrtsmft",
+		},
+		"mismatched public key": {
+			publicKey: otherPublicKey,
+			wif:       "cVyvMQSzsprp3Jdnr7zcVja84y2QsrmAneSSWA5EJ2Cp89SVTT8b",
+			errorCode: ErrUnableToDerive.Code,
+		},
+		"edwards25519 public key": {
+			publicKey: &types.PublicKey{
+				Bytes: forceHexDecode(
+					t,
+					"d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a",
+				),
+				CurveType: types.Edwards25519,
+			},
+			errorCode: ErrInvalidPublicKey.Code,
+		},
+		"truncated public key": {
+			publicKey: &types.PublicKey{
+				Bytes:     publicKey.Bytes[:32],
+				CurveType: types.Secp256k1,
+			},
+			errorCode: ErrInvalidPublicKey.Code,
+		},
+		"public key not on curve": {
+			publicKey: &types.PublicKey{
+				Bytes:     append([]byte{0x02}, make([]byte, 32)...),
+				CurveType: types.Secp256k1,
+			},
+			errorCode: ErrInvalidPublicKey.Code,
+		},
+		"mainnet wif": {
+			wif:       "L5cvtVT9SmAYssAXTiBV8R54Sjj1DQfUicHyPjcinuYosQL6pBcR",
+			errorCode: ErrUnableToDerive.Code,
+		},
+		"invalid wif": {
+			wif:       "cVyvMQSzsprp3Jdnr7zcVja84y2QsrmAneSSWA5EJ2Cp89SVTT8c",
+			errorCode: ErrUnableToDerive.Code,
+		},
+		"no public key or wif": {
+			errorCode: ErrInvalidPublicKey.Code,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var metadata map[string]interface{}
+			if len(test.wif) > 0 {
+				metadata = forceMarshalMap(t, &deriveMetadata{WIF: test.wif})
+			}
+
+			deriveResponse, err := servicer.ConstructionDerive(ctx, &types.ConstructionDeriveRequest{
+				NetworkIdentifier: networkIdentifier,
+				PublicKey:         test.publicKey,
+				Metadata:          metadata,
+			})
+			if test.errorCode != 0 {
+				assert.Nil(t, deriveResponse)
+				assert.Equal(t, test.errorCode, err.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+			assert.Equal(t, &types.ConstructionDeriveResponse{
+				AccountIdentifier: &types.AccountIdentifier{
+					Address: test.address,
+				},
+			}, deriveResponse)
+		})
+	}
+}
+
+func TestConstructionService_AssetNameCase(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	ops := func(symbol string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "100000000",
+					Currency: ravencoin.AssetCurrency(symbol, ravencoin.MaxAssetUnits),
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		symbol   string
+		allowAny bool
+
+		err *types.Error
+	}{
+		"lowercase": {
+			symbol: "rosetta",
+			err: &types.Error{
+				Code:    ErrInvalidAssetName.Code,
+				Message: ErrInvalidAssetName.Message,
+				Details: map[string]interface{}{
+					"context": "asset name rosetta is not canonical (expected ROSETTA)",
+				},
+			},
+		},
+		"lowercase allowed": {
+			symbol:   "rosetta",
+			allowAny: true,
+		},
+		"canonical": {
+			symbol: "ROSETTA",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:                        configuration.Online,
+				Network:                     networkIdentifier,
+				Params:                      ravencoin.TestnetParams,
+				Currency:                    ravencoin.TestnetCurrency,
+				AllowNonCanonicalAssetNames: test.allowAny,
+			}
+			servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+			ctx := context.Background()
+
+			preprocessResponse, err := servicer.ConstructionPreprocess(
+				ctx,
+				&types.ConstructionPreprocessRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops(test.symbol),
+				},
+			)
+			if test.err != nil {
+				assert.Nil(t, preprocessResponse)
+				assert.Equal(t, test.err, err)
+			} else {
+				assert.NotNil(t, preprocessResponse)
+				assert.Nil(t, err)
+			}
+
+			payloadsResponse, err := servicer.ConstructionPayloads(
+				ctx,
+				&types.ConstructionPayloadsRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops(test.symbol),
+				},
+			)
+			assert.Nil(t, payloadsResponse)
+			if test.err != nil {
+				assert.Equal(t, test.err, err)
+			} else {
+				// No asset inputs are spent, so names that
+				// pass the guard fail the balance check.
+				assert.Equal(t, ErrUnbalancedTransaction.Code, err.Code)
+			}
+		})
+	}
+}
+
+func TestConstructionService_AssetBalances(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	asset := ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)
+	ops := func(change string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-10000000000",
+					Currency: asset,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:2",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 2,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 3,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "moHJPn7VKmPyXz9vCFMHWFeYpBaTCoWfqs",
+				},
+				Amount: &types.Amount{
+					Value:    "6000000000",
+					Currency: asset,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 4,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    change,
+					Currency: asset,
+				},
+			},
+		}
+	}
+
+	p2pkh := "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac"
+	assetScript, scriptErr := ravencoin.TransferAssetScript(forceHexDecode(t, p2pkh), "ROSETTA", 10000000000)
+	assert.NoError(t, scriptErr)
+	metadata := forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG", // nolint
+				Hex:          p2pkh,
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+			{
+				Hex:          hex.EncodeToString(assetScript),
+				RequiredSigs: 1,
+				Type:         ravencoin.TransferAssetType,
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+		},
+	})
+
+	// Outputs of 101 ROSETTA (one unit more than spent)
+	// would be rejected by ravend.
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("4000000001"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnbalancedTransaction.Code, err.Code)
+	assert.Equal(
+		t,
+		"asset ROSETTA is unbalanced: inputs of 10000000000 but outputs of 10000000001",
+		err.Details["context"],
+	)
+
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("4000000000"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, payloadsResponse.Payloads, 2)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+
+	// The coin carrying ROSETTA is worth no RVN.
+	assert.Equal(t, []string{"-1000000", "0"}, unsigned.InputAmounts)
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxIn, 2)
+	assert.Len(t, tx.TxOut, 3)
+	assert.Equal(t, int64(954843), tx.TxOut[0].Value)
+	assert.Equal(t, p2pkh, hex.EncodeToString(tx.TxOut[0].PkScript))
+
+	// The asset outputs transfer ROSETTA and carry no RVN.
+	for i, amount := range []int64{6000000000, 4000000000} {
+		output := tx.TxOut[i+1]
+		transfer, parseErr := ravencoin.ParseAssetScript(output.PkScript)
+		assert.NoError(t, parseErr)
+		assert.Equal(t, &ravencoin.AssetScript{
+			Type:   ravencoin.TransferAssetType,
+			Name:   "ROSETTA",
+			Amount: amount,
+		}, transfer)
+		assert.Equal(t, int64(0), output.Value)
+	}
+	assert.Equal(t, p2pkh, hex.EncodeToString(ravencoin.AssetScriptBase(tx.TxOut[2].PkScript)))
+
+	// Parsing recovers the assets moved by the
+	// inputs and outputs.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+
+	expected := ops("4000000000")
+	assert.Len(t, parseResponse.Operations, len(expected))
+	for i, op := range parseResponse.Operations {
+		assert.Equal(t, expected[i].Type, op.Type)
+		assert.Equal(t, expected[i].Account, op.Account)
+		assert.Equal(t, expected[i].Amount, op.Amount)
+		assert.Equal(t, expected[i].CoinChange, op.CoinChange)
+	}
+}
+
+func TestConstructionService_TransferMessage(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	message := "QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG"
+	asset := ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)
+	ops := func(currency *types.Currency, value string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-10000000000",
+					Currency: asset,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:2",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 2,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "moHJPn7VKmPyXz9vCFMHWFeYpBaTCoWfqs",
+				},
+				Amount: &types.Amount{
+					Value:    value,
+					Currency: currency,
+				},
+				Metadata: forceMarshalMap(t, &transferAssetMetadata{
+					Message:    message,
+					ExpireTime: 1700000000,
+				}),
+			},
+		}
+	}
+
+	p2pkh := "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac"
+	assetScript, scriptErr := ravencoin.TransferAssetScript(forceHexDecode(t, p2pkh), "ROSETTA", 10000000000)
+	assert.NoError(t, scriptErr)
+	metadata := forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				Hex:          p2pkh,
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+			{
+				Hex:          hex.EncodeToString(assetScript),
+				RequiredSigs: 1,
+				Type:         ravencoin.TransferAssetType,
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+		},
+	})
+
+	// Only asset transfers can carry a message.
+	rvnOps := ops(ravencoin.TestnetCurrency, "954843")
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        []*types.Operation{rvnOps[0], rvnOps[2]},
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops(asset, "10000000000"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, err)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxOut, 1)
+	transfer, parseErr := ravencoin.ParseAssetScript(tx.TxOut[0].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:       ravencoin.TransferAssetType,
+		Name:       "ROSETTA",
+		Amount:     10000000000,
+		Message:    message,
+		ExpireTime: 1700000000,
+	}, transfer)
+
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+
+	expected := ops(asset, "10000000000")
+	assert.Len(t, parseResponse.Operations, len(expected))
+	for i, op := range parseResponse.Operations {
+		assert.Equal(t, expected[i].Type, op.Type)
+		assert.Equal(t, expected[i].Account, op.Account)
+		assert.Equal(t, expected[i].Amount, op.Amount)
+		assert.Equal(t, expected[i].Metadata, op.Metadata)
+	}
+}
+
+func TestConstructionService_Currency(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	ops := func(inputCurrency *types.Currency, outputCurrency *types.Currency) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: inputCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: outputCurrency,
+				},
+			},
+		}
+	}
+
+	tests := map[string]struct {
+		inputCurrency  *types.Currency
+		outputCurrency *types.Currency
+
+		context string
+	}{
+		"configured currency": {
+			inputCurrency:  ravencoin.TestnetCurrency,
+			outputCurrency: ravencoin.TestnetCurrency,
+		},
+		"wrong input symbol": {
+			inputCurrency:  ravencoin.MainnetCurrency,
+			outputCurrency: ravencoin.TestnetCurrency,
+			context:        "operation 0 has currency RVN with 8 decimals but expected tRVN with 8 decimals",
+		},
+		"wrong output decimals": {
+			inputCurrency: ravencoin.TestnetCurrency,
+			outputCurrency: &types.Currency{
+				Symbol:   "tRVN",
+				Decimals: 2,
+			},
+			context: "operation 1 has currency tRVN with 2 decimals but expected tRVN with 8 decimals",
+		},
+		"wrong output symbol": {
+			inputCurrency: ravencoin.TestnetCurrency,
+			outputCurrency: &types.Currency{
+				Symbol:   "RAVEN",
+				Decimals: 8,
+			},
+			context: "operation 1 has currency RAVEN with 8 decimals but expected tRVN with 8 decimals",
+		},
+		"wrong asset decimals": {
+			inputCurrency: ravencoin.TestnetCurrency,
+			outputCurrency: &types.Currency{
+				Symbol:   "ROSETTA",
+				Decimals: 0,
+			},
+			context: "operation 1 has currency ROSETTA with 0 decimals but expected ROSETTA with 8 decimals",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			preprocessResponse, err := servicer.ConstructionPreprocess(
+				ctx,
+				&types.ConstructionPreprocessRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops(test.inputCurrency, test.outputCurrency),
+				},
+			)
+			if len(test.context) == 0 {
+				assert.NotNil(t, preprocessResponse)
+				assert.Nil(t, err)
+				return
+			}
+
+			assert.Nil(t, preprocessResponse)
+			assert.Equal(t, ErrInvalidCurrency.Code, err.Code)
+			assert.Equal(t, test.context, err.Details["context"])
+
+			payloadsResponse, err := servicer.ConstructionPayloads(
+				ctx,
+				&types.ConstructionPayloadsRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops(test.inputCurrency, test.outputCurrency),
+				},
+			)
+			assert.Nil(t, payloadsResponse)
+			assert.Equal(t, ErrInvalidCurrency.Code, err.Code)
+			assert.Equal(t, test.context, err.Details["context"])
+		})
+	}
+}
+
+func TestConstructionService_IssueAsset(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	ops := func(change string, issuer string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-60000000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "5000000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 2,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    change,
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 3,
+				},
+				Type: ravencoin.IssueAssetOpType,
+				Account: &types.AccountIdentifier{
+					Address: issuer,
+				},
+				Amount: &types.Amount{
+					Value:    "100000000000",
+					Currency: ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits),
+				},
+				Metadata: forceMarshalMap(t, &issueAssetMetadata{
+					Units:      0,
+					Reissuable: true,
+				}),
+			},
+		}
+	}
+
+	// 12 overhead + 148 legacy input + 31 P2WPKH output + 34 P2PKH change
+	// + 34 burn + 50 owner token + 60 asset
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj"),
+		},
+	)
+	assert.Nil(t, err)
+	var options preprocessOptions
+	assert.NoError(t, types.UnmarshalMap(preprocessResponse.Options, &options))
+	assert.Equal(t, float64(369), options.EstimatedSize)
+
+	metadata := forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG", // nolint
+				Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+		},
+	})
+
+	// The burn and fee are paid from the RVN input, so
+	// 600 tRVN in = 50 payment + 49.9999 change + 500 burn + 0.0001 fee.
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, payloadsResponse.Payloads, 1)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxOut, 5)
+
+	// RVN outputs (including change) come first.
+	assert.Equal(t, int64(5000000000), tx.TxOut[0].Value)
+	assert.Equal(t, int64(4999990000), tx.TxOut[1].Value)
+
+	// The burn is paid to the testnet issue asset burn address.
+	assert.Equal(t, int64(50000000000), tx.TxOut[2].Value)
+	assert.Equal(
+		t,
+		"76a914dda3d21797ff26cb8ae9a769bdc68cf4567f5bba88ac",
+		hex.EncodeToString(tx.TxOut[2].PkScript),
+	)
+
+	// The owner token and the asset are the last two outputs.
+	owner, parseErr := ravencoin.ParseAssetScript(tx.TxOut[3].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:   ravencoin.NewAssetType,
+		Name:   "ROSETTA!",
+		Amount: ravencoin.OwnerAssetAmount,
+	}, owner)
+	assert.Equal(t, int64(0), tx.TxOut[3].Value)
+
+	asset, parseErr := ravencoin.ParseAssetScript(tx.TxOut[4].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:       ravencoin.NewAssetType,
+		Name:       "ROSETTA",
+		Amount:     100000000000,
+		Reissuable: true,
+	}, asset)
+	assert.Equal(t, int64(0), tx.TxOut[4].Value)
+
+	// Parsing folds the burn, owner token and asset
+	// outputs back into the ISSUE_ASSET operation.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+
+	expectedOps := ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj")
+	for i, networkIndex := range []int64{0, 0, 1, 4} {
+		index := networkIndex
+		expectedOps[i].OperationIdentifier.NetworkIndex = &index
+	}
+	assert.Equal(t, &types.ConstructionParseResponse{
+		Operations:               expectedOps,
+		AccountIdentifierSigners: []*types.AccountIdentifier{},
+	}, parseResponse)
+
+	// Inputs must cover the burn.
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("54999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	// Assets cannot be issued to segwit addresses.
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("4999990000", "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	// Issuances are in Satoshis like every other asset
+	// operation, regardless of the units of the asset.
+	unitsOps := ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj")
+	unitsOps[3].Amount.Currency = ravencoin.AssetCurrency("ROSETTA", 0)
+	preprocessResponse, err = servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        unitsOps,
+		},
+	)
+	assert.Nil(t, preprocessResponse)
+	assert.Equal(t, ErrInvalidCurrency.Code, err.Code)
+	assert.Equal(
+		t,
+		"operation 3 has currency ROSETTA with 0 decimals but expected ROSETTA with 8 decimals",
+		err.Details["context"],
+	)
+
+	// The amount cannot be more precise than the units.
+	unitsOps = ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj")
+	unitsOps[3].Amount.Value = "100000000001"
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        unitsOps,
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	// Only root assets can be issued.
+	subAssetOps := ops("4999990000", "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj")
+	subAssetOps[3].Amount.Currency = ravencoin.AssetCurrency("ROSETTA/SUB", ravencoin.MaxAssetUnits)
+	preprocessResponse, err = servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        subAssetOps,
+		},
+	)
+	assert.Nil(t, preprocessResponse)
+	assert.Equal(t, ErrInvalidAssetName.Code, err.Code)
+	assert.Equal(
+		t,
+		"invalid asset name: ROSETTA/SUB is a sub asset but only root assets can be issued",
+		err.Details["context"],
+	)
+}
+
+func TestConstructionService_ReissueAsset(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	ownerCoin := &types.CoinIdentifier{
+		Identifier: "6d1b6e3fa8b7ad3c2ef1b3e6a1e7b2c9d0f4a5b6c7d8e9f0a1b2c3d4e5f60718:2",
+	}
+	ops := func(change string, assetName string) []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "-60000000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "5000000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 2,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    change,
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 3,
+				},
+				Type: ravencoin.ReissueAssetOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: ownerCoin,
+					CoinAction:     types.CoinSpent,
+				},
+				Metadata: forceMarshalMap(t, &reissueAssetMetadata{
+					AssetName:  assetName,
+					Quantity:   "50000000000",
+					Units:      ravencoin.KeepAssetUnits,
+					Reissuable: true,
+				}),
+			},
+		}
+	}
+
+	// 12 overhead + 148 legacy input + 31 P2WPKH output + 34 P2PKH change
+	// + 148 owner token input + 34 burn + 58 owner token + 59 reissuance
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops("44999990000", "ROSETTA"),
+		},
+	)
+	assert.Nil(t, err)
+	var options preprocessOptions
+	assert.NoError(t, types.UnmarshalMap(preprocessResponse.Options, &options))
+	assert.Equal(t, float64(524), options.EstimatedSize)
+
+	// The owner token is the last coin.
+	assert.Len(t, options.Coins, 2)
+	assert.Equal(t, &types.Coin{
+		CoinIdentifier: ownerCoin,
+		Amount: &types.Amount{
+			Value:    "-100000000",
+			Currency: ravencoin.AssetCurrency("ROSETTA!", ravencoin.MaxAssetUnits),
+		},
+	}, options.Coins[1])
+
+	metadata := forceMarshalMap(t, &constructionMetadata{
+		ScriptPubKeys: []*ravencoin.ScriptPubKey{
+			{
+				ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG", // nolint
+				Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+				RequiredSigs: 1,
+				Type:         "pubkeyhash",
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+			{
+				ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG OP_RVN_ASSET 72766e7408524f534554544121 00e1f50500000000 OP_DROP", // nolint
+				Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01572766e7408524f53455454412100e1f5050000000075",
+				RequiredSigs: 1,
+				Type:         ravencoin.TransferAssetType,
+				Addresses: []string{
+					"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+			},
+		},
+	})
+
+	// The burn and fee are paid from the RVN input, so
+	// 600 tRVN in = 50 payment + 449.9999 change + 100 burn + 0.0001 fee.
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("44999990000", "ROSETTA"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, payloadsResponse.Payloads, 2)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+	assert.Equal(t, []string{"-60000000000", "0"}, unsigned.InputAmounts)
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxIn, 2)
+	assert.Len(t, tx.TxOut, 5)
+
+	// RVN outputs (including change) come first.
+	assert.Equal(t, int64(5000000000), tx.TxOut[0].Value)
+	assert.Equal(t, int64(44999990000), tx.TxOut[1].Value)
+
+	// The burn is paid to the testnet reissue asset burn address.
+	assert.Equal(t, int64(10000000000), tx.TxOut[2].Value)
+	assert.Equal(
+		t,
+		"76a914da61c47adbad4a81e5f14e1fabb3d167a51ca44888ac",
+		hex.EncodeToString(tx.TxOut[2].PkScript),
+	)
+
+	// The owner token is returned and the reissuance
+	// is the last output.
+	owner, parseErr := ravencoin.ParseAssetScript(tx.TxOut[3].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:   ravencoin.TransferAssetType,
+		Name:   "ROSETTA!",
+		Amount: ravencoin.OwnerAssetAmount,
+	}, owner)
+	assert.Equal(t, int64(0), tx.TxOut[3].Value)
+
+	reissue, parseErr := ravencoin.ParseAssetScript(tx.TxOut[4].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:       ravencoin.ReissueAssetType,
+		Name:       "ROSETTA",
+		Amount:     50000000000,
+		Units:      ravencoin.KeepAssetUnits,
+		Reissuable: true,
+	}, reissue)
+	assert.Equal(t, int64(0), tx.TxOut[4].Value)
+
+	// Parsing folds the owner token input and the burn,
+	// owner token and reissuance outputs back into the
+	// REISSUE_ASSET operation.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+
+	expectedOps := ops("44999990000", "ROSETTA")
+	for i, networkIndex := range []int64{0, 0, 1, 4} {
+		index := networkIndex
+		expectedOps[i].OperationIdentifier.NetworkIndex = &index
+	}
+	assert.Equal(t, &types.ConstructionParseResponse{
+		Operations:               expectedOps,
+		AccountIdentifierSigners: []*types.AccountIdentifier{},
+	}, parseResponse)
+
+	// A reissuance must spend the owner token.
+	noInputs := tx.Copy()
+	noInputs.TxIn = nil
+	parsedOps, err := servicer.(*ConstructionAPIService).parseOutputs(
+		noInputs,
+		[]*types.Operation{},
+	)
+	assert.Nil(t, parsedOps)
+	assert.Equal(t, ErrUnableToParseIntermediateResult.Code, err.Code)
+	assert.Equal(t, "asset reissuance does not spend an owner token", err.Details["context"])
+
+	// Inputs must cover the burn.
+	payloadsResponse, err = servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops("54999990000", "ROSETTA"),
+		Metadata:          metadata,
+	})
+	assert.Nil(t, payloadsResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	// Only root and sub assets can be reissued.
+	preprocessResponse, err = servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops("44999990000", "ROSETTA#TAG"),
+		},
+	)
+	assert.Nil(t, preprocessResponse)
+	assert.Equal(t, ErrInvalidAssetName.Code, err.Code)
+	assert.Equal(
+		t,
+		"invalid asset name: ROSETTA#TAG is a unique asset but only root and sub assets can be reissued", // nolint
+		err.Details["context"],
+	)
+}
+
+func TestConstructionService_BurnAsset(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, nil)
+	ctx := context.Background()
+
+	assetCurrency := ravencoin.AssetCurrency("ROSETTA", ravencoin.MaxAssetUnits)
+	rvnCoin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{
+			Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+		},
+		Amount: &types.Amount{
+			Value:    "-60000000000",
+			Currency: ravencoin.TestnetCurrency,
+		},
+	}
+	assetCoin := &types.Coin{
+		CoinIdentifier: &types.CoinIdentifier{
+			Identifier: "6d1b6e3fa8b7ad3c2ef1b3e6a1e7b2c9d0f4a5b6c7d8e9f0a1b2c3d4e5f60718:3",
+		},
+		Amount: &types.Amount{
+			Value:    "-1000000000",
+			Currency: assetCurrency,
+		},
+	}
+	ops := func() []*types.Operation {
+		return []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: ravencoin.InputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: rvnCoin.Amount,
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: rvnCoin.CoinIdentifier,
+					CoinAction:     types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: ravencoin.OutputOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: &types.Amount{
+					Value:    "59999990000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 2,
+				},
+				Type: ravencoin.BurnAssetOpType,
+				Account: &types.AccountIdentifier{
+					Address: "my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+				},
+				Amount: assetCoin.Amount,
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: assetCoin.CoinIdentifier,
+					CoinAction:     types.CoinSpent,
+				},
+			},
+		}
+	}
+
+	// 12 overhead + 148 legacy input + 34 P2PKH change
+	// + 148 asset input + 57 asset burn
+	preprocessResponse, err := servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        ops(),
+		},
+	)
+	assert.Nil(t, err)
+	var options preprocessOptions
+	assert.NoError(t, types.UnmarshalMap(preprocessResponse.Options, &options))
+	assert.Equal(t, float64(399), options.EstimatedSize)
+	assert.Equal(t, []*types.Coin{rvnCoin, assetCoin}, options.Coins)
+	assert.Equal(t, []*types.Coin{assetCoin}, options.BurnedCoins)
+
+	scripts := []*ravencoin.ScriptPubKey{
+		{
+			ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG", // nolint
+			Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588ac",
+			RequiredSigs: 1,
+			Type:         "pubkeyhash",
+			Addresses: []string{
+				"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+			},
+		},
+		{
+			ASM:          "OP_DUP OP_HASH160 c005b00ad075d30b89a7b65b7dad8899ba6a9c55 OP_EQUALVERIFY OP_CHECKSIG OP_RVN_ASSET 72766e7407524f5345545441 00ca9a3b00000000 OP_DROP", // nolint
+			Hex:          "76a914c005b00ad075d30b89a7b65b7dad8899ba6a9c5588acc01472766e7407524f534554544100ca9a3b0000000075",
+			RequiredSigs: 1,
+			Type:         ravencoin.TransferAssetType,
+			Addresses: []string{
+				"my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj",
+			},
+		},
+	}
+
+	// The burned coin must hold exactly the burned quantity.
+	mockIndexer.On("GetScriptPubKeys", ctx, options.Coins).Return(scripts, nil).Twice()
+	mockIndexer.On("GetCoinAmounts", ctx, options.Coins).Return(indexedAmounts(t, options.Coins), nil).Twice()
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.BurnedCoins,
+	).Return(
+		[]*types.Amount{{Value: "2000000000", Currency: assetCurrency}},
+		nil,
+	).Once()
+	metadataResponse, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           preprocessResponse.Options,
+	})
+	assert.Nil(t, metadataResponse)
+	assert.Equal(t, ErrInvalidCoin.Code, err.Code)
+
+	mockIndexer.On(
+		"GetCoinAmounts",
+		ctx,
+		options.BurnedCoins,
+	).Return(
+		[]*types.Amount{{Value: "1000000000", Currency: assetCurrency}},
+		nil,
+	).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		ravencoin.MinFeeRate,
+		nil,
+	).Once()
+	metadataResponse, err = servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options:           preprocessResponse.Options,
+	})
+	assert.Nil(t, err)
+
+	payloadsResponse, err := servicer.ConstructionPayloads(ctx, &types.ConstructionPayloadsRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations:        ops(),
+		Metadata:          metadataResponse.Metadata,
+	})
+	assert.Nil(t, err)
+	assert.Len(t, payloadsResponse.Payloads, 2)
+
+	var unsigned unsignedTransaction
+	assert.NoError(t, json.Unmarshal(
+		forceHexDecode(t, payloadsResponse.UnsignedTransaction),
+		&unsigned,
+	))
+	assert.Equal(t, []string{"-60000000000", "0"}, unsigned.InputAmounts)
+
+	var tx wire.MsgTx
+	assert.NoError(t, tx.Deserialize(bytes.NewReader(forceHexDecode(t, unsigned.Transaction))))
+	assert.Len(t, tx.TxIn, 2)
+	assert.Len(t, tx.TxOut, 2)
+	assert.Equal(t, int64(59999990000), tx.TxOut[0].Value)
+
+	// All 10 ROSETTA are transferred to the testnet global
+	// burn address, so no output carrying an asset can
+	// be spent.
+	burn, parseErr := ravencoin.ParseAssetScript(tx.TxOut[1].PkScript)
+	assert.NoError(t, parseErr)
+	assert.Equal(t, &ravencoin.AssetScript{
+		Type:   ravencoin.TransferAssetType,
+		Name:   "ROSETTA",
+		Amount: 1000000000,
+	}, burn)
+	assert.Equal(t, int64(0), tx.TxOut[1].Value)
+	for _, output := range tx.TxOut {
+		asset, err := ravencoin.ParseAssetScript(output.PkScript)
+		assert.NoError(t, err)
+		if asset == nil {
+			continue
+		}
+
+		assert.Equal(
+			t,
+			"76a914d7c8944771bbfe427418f27320e72a1322faf13488ac",
+			hex.EncodeToString(ravencoin.AssetScriptBase(output.PkScript)),
+		)
+	}
+
+	// Parsing folds the asset input and the burn
+	// output back into the BURN_ASSET operation.
+	parseResponse, err := servicer.ConstructionParse(ctx, &types.ConstructionParseRequest{
+		NetworkIdentifier: networkIdentifier,
+		Signed:            false,
+		Transaction:       payloadsResponse.UnsignedTransaction,
+	})
+	assert.Nil(t, err)
+
+	expectedOps := ops()
+	for i, networkIndex := range []int64{0, 0, 1} {
+		index := networkIndex
+		expectedOps[i].OperationIdentifier.NetworkIndex = &index
+	}
+	assert.Equal(t, expectedOps, parseResponse.Operations)
+
+	// Only assets can be burned.
+	rvnBurn := ops()
+	rvnBurn[2].Amount = &types.Amount{
+		Value:    "-1000000000",
+		Currency: ravencoin.TestnetCurrency,
+	}
+	preprocessResponse, err = servicer.ConstructionPreprocess(
+		ctx,
+		&types.ConstructionPreprocessRequest{
+			NetworkIdentifier: networkIdentifier,
+			Operations:        rvnBurn,
+		},
+	)
+	assert.Nil(t, preprocessResponse)
+	assert.Equal(t, ErrUnclearIntent.Code, err.Code)
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+// capturingLogger records the messages and
+// key-value pairs logged to it.
+type capturingLogger struct {
+	messages []string
+	fields   []map[string]interface{}
+}
+
+func (l *capturingLogger) Debugw(msg string, keysAndValues ...interface{}) {
+	fields := map[string]interface{}{}
+	for i := 0; i+1 < len(keysAndValues); i += 2 {
+		fields[keysAndValues[i].(string)] = keysAndValues[i+1]
+	}
+
+	l.messages = append(l.messages, msg)
+	l.fields = append(l.fields, fields)
+}
+
+func TestConstructionService_Logger(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+
+	ctx := context.Background()
+	coins := []*types.Coin{
+		{
+			CoinIdentifier: &types.CoinIdentifier{
+				Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+			},
+			Amount: &types.Amount{
+				Value:    "-500000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+	scripts := []*ravencoin.ScriptPubKey{
+		{
+			Hex:          "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55",
+			RequiredSigs: 1,
+			Type:         "witness_v0_keyhash",
+		},
+	}
+
+	logger := &capturingLogger{}
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewConstructionAPIService(cfg, mockClient, mockIndexer, logger)
+
+	mockIndexer.On("GetScriptPubKeys", ctx, coins).Return(scripts, nil).Once()
+	mockIndexer.On("GetCoinAmounts", ctx, coins).Return(indexedAmounts(t, coins), nil).Once()
+	mockClient.On(
+		"SuggestedFeeRatePerKB",
+		ctx,
+		defaultConfirmationTarget,
+	).Return(
+		0.0002,
+		nil,
+	).Once()
+
+	response, err := servicer.ConstructionMetadata(ctx, &types.ConstructionMetadataRequest{
+		NetworkIdentifier: networkIdentifier,
+		Options: forceMarshalMap(t, &preprocessOptions{
+			Coins:         coins,
+			EstimatedSize: 142,
+		}),
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "2840", response.SuggestedFee[0].Value)
+
+	// 142 vbytes at 20 Satoshis per vbyte.
+	assert.Equal(t, []string{"fee computed"}, logger.messages)
+	assert.Equal(t, map[string]interface{}{
+		"fee":            "2840",
+		"fee_rate":       int64(20000),
+		"node_fee_rate":  int64(20000),
+		"estimated_size": float64(142),
+	}, logger.fields[0])
+
+	mockClient.AssertExpectations(t)
+	mockIndexer.AssertExpectations(t)
+}
+
+func TestConstructionService_CoinbaseMaturity(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	coinbaseCoin := func(identifier string, height int64) *ravencoin.SpendableCoin {
+		return ravencoin.NewSpendableCoin(
+			&types.Coin{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: identifier,
+				},
+				Amount: &types.Amount{
+					Value:    "500000000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			"tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+			true,
+			height,
+			1000,
+			int64(ravencoin.TestnetParams.CoinbaseMaturity),
+		)
+	}
+
+	// 50 blocks deep
+	immature := coinbaseCoin(
+		"2b2a7f8a0e0c6e4f4b1bfa4e42e5b3cb04d4d0f2b3e1a2f7d7d2f0c8a6a1e3b9:0",
+		950,
+	)
+	assert.True(t, immature.Immature)
+	assert.Equal(t, int32(1050), immature.MaturesAtHeight)
+
+	// 150 blocks deep
+	mature := coinbaseCoin(
+		"c4f1a3d8b7e2f6a9d0c5b4e3a2f1d8c7b6a5e4d3c2b1a0f9e8d7c6b5a4f3e2d1:0",
+		850,
+	)
+	assert.False(t, mature.Immature)
+	assert.Equal(t, int32(950), mature.MaturesAtHeight)
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "100000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		available []*ravencoin.SpendableCoin
+
+		selected []string
+		err      *types.Error
+	}{
+		"immature coin excluded": {
+			available: []*ravencoin.SpendableCoin{immature, mature},
+			selected:  []string{mature.CoinIdentifier.Identifier},
+		},
+		"only immature coins": {
+			available: []*ravencoin.SpendableCoin{immature},
+			err:       ErrInsufficientFunds,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			preprocessResponse, err := servicer.ConstructionPreprocess(
+				ctx,
+				&types.ConstructionPreprocessRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops,
+					Metadata: forceMarshalMap(t, &preprocessMetadata{
+						AvailableCoins: test.available,
+					}),
+				},
+			)
+			if test.err != nil {
+				assert.Nil(t, preprocessResponse)
+				assert.Equal(t, test.err.Code, err.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+
+			var options preprocessOptions
+			assert.NoError(t, types.UnmarshalMap(preprocessResponse.Options, &options))
+
+			selected := make([]string, len(options.Coins))
+			for i, coin := range options.Coins {
+				selected[i] = coin.CoinIdentifier.Identifier
+			}
+			assert.Equal(t, test.selected, selected)
+		})
+	}
+}
+
+func TestConstructionService_CoinSelectionInputSize(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	servicer := NewConstructionAPIService(cfg, &mocks.Client{}, &mocks.Indexer{}, nil)
+	ctx := context.Background()
+
+	coin := func(address string) *ravencoin.SpendableCoin {
+		return &ravencoin.SpendableCoin{
+			Coin: &types.Coin{
+				CoinIdentifier: &types.CoinIdentifier{
+					Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+				},
+				Amount: &types.Amount{
+					Value:    "500000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+			Address: address,
+		}
+	}
+
+	ops := []*types.Operation{
+		{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: 0,
+			},
+			Type: ravencoin.OutputOpType,
+			Account: &types.AccountIdentifier{
+				Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+			},
+			Amount: &types.Amount{
+				Value:    "100000000",
+				Currency: ravencoin.TestnetCurrency,
+			},
+		},
+	}
+
+	tests := map[string]struct {
+		coin *ravencoin.SpendableCoin
+
+		estimatedSize float64
+		err           *types.Error
+	}{
+		"segwit coin": {
+			coin: coin("tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm"),
+			// 12 overhead + 31 output + 68 input + 31 change
+			estimatedSize: 142,
+		},
+		"legacy coin": {
+			coin: coin("my2Gr56HqNx2Z7QGtpw474g28ZS8rxB7Hj"),
+			// 12 overhead + 31 output + 148 input + 31 change
+			estimatedSize: 222,
+		},
+		"missing address": {
+			coin: coin(""),
+			err:  ErrInvalidCoin,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			preprocessResponse, err := servicer.ConstructionPreprocess(
+				ctx,
+				&types.ConstructionPreprocessRequest{
+					NetworkIdentifier: networkIdentifier,
+					Operations:        ops,
+					Metadata: forceMarshalMap(t, &preprocessMetadata{
+						AvailableCoins: []*ravencoin.SpendableCoin{test.coin},
+					}),
+				},
+			)
+			if test.err != nil {
+				assert.Nil(t, preprocessResponse)
+				assert.Equal(t, test.err.Code, err.Code)
+				return
+			}
+
+			assert.Nil(t, err)
+
+			var options preprocessOptions
+			assert.NoError(t, types.UnmarshalMap(preprocessResponse.Options, &options))
+			assert.Len(t, options.Coins, 1)
+			assert.Equal(t, test.estimatedSize, options.EstimatedSize)
+		})
+	}
+}
+
+func TestConstructionService_ReplacementOperations(t *testing.T) {
+	networkIdentifier = &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+	}
+	ctx := context.Background()
+
+	txHash := "5c1b7e0b3a9c2f4d6e8a0b1c2d3e4f5a6b7c8d9e0f1a2b3c4d5e6f7a8b9c0d1e"
+	funder := &types.AccountIdentifier{
+		Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+	}
+	recipient := &types.AccountIdentifier{
+		Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+	}
+	coinChange := &types.CoinChange{
+		CoinIdentifier: &types.CoinIdentifier{
+			Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+		},
+		CoinAction: types.CoinSpent,
+	}
+	amount := func(value string) *types.Amount {
+		return &types.Amount{
+			Value:    value,
+			Currency: ravencoin.TestnetCurrency,
+		}
+	}
+
+	// The mempool transaction pays 500000 to recipient and
+	// (if change is populated) change to funder, with a fee
+	// of 10000.
+	mempoolTransaction := func(sequence int64, change string) *types.Transaction {
+		output := func(index int64, account *types.AccountIdentifier, value string) *types.Operation {
+			return &types.Operation{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index:        index,
+					NetworkIndex: types.Int64(index - 1),
+				},
+				Type:    ravencoin.OutputOpType,
+				Status:  types.String(ravencoin.SuccessStatus),
+				Account: account,
+				Amount:  amount(value),
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: fmt.Sprintf("%s:%d", txHash, index-1),
+					},
+					CoinAction: types.CoinCreated,
+				},
+			}
+		}
+
+		ops := []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index:        0,
+					NetworkIndex: types.Int64(0),
+				},
+				Type:       ravencoin.InputOpType,
+				Status:     types.String(ravencoin.SuccessStatus),
+				Account:    funder,
+				Amount:     amount("-1000000"),
+				CoinChange: coinChange,
+				Metadata: forceMarshalMap(t, &ravencoin.OperationMetadata{
+					Sequence: sequence,
+				}),
+			},
+			output(1, recipient, "500000"),
+		}
+		if len(change) > 0 {
+			ops = append(ops, output(2, funder, change))
+		}
+
+		return &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{
+				Hash: txHash,
+			},
+			Operations: ops,
+		}
+	}
+
+	tests := map[string]struct {
+		mempoolTransaction *types.Transaction
+		mempoolErr         error
+		feeIncrease        int64
+
+		operations []*types.Operation
+		errorCode  int32
+	}{
+		"replacement": {
+			mempoolTransaction: mempoolTransaction(int64(replaceableSequence), "490000"),
+			feeIncrease:        1000,
+			operations: []*types.Operation{
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 0,
+					},
+					Type:       ravencoin.InputOpType,
+					Account:    funder,
+					Amount:     amount("-1000000"),
+					CoinChange: coinChange,
+				},
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 1,
+					},
+					Type:    ravencoin.OutputOpType,
+					Account: recipient,
+					Amount:  amount("500000"),
+				},
+				{
+					OperationIdentifier: &types.OperationIdentifier{
+						Index: 2,
+					},
+					Type:    ravencoin.OutputOpType,
+					Account: funder,
+					Amount:  amount("489000"),
+				},
+			},
+		},
+		"not in mempool": {
+			mempoolErr:  ravencoin.ErrTransactionNotInMempool,
+			feeIncrease: 1000,
+			errorCode:   ErrTransactionNotFound.Code,
+		},
+		"not replaceable": {
+			mempoolTransaction: mempoolTransaction(int64(wire.MaxTxInSequenceNum), "490000"),
+			feeIncrease:        1000,
+			errorCode:          ErrUnclearIntent.Code,
+		},
+		"no change": {
+			mempoolTransaction: mempoolTransaction(int64(replaceableSequence), ""),
+			feeIncrease:        1000,
+			errorCode:          ErrInsufficientFunds.Code,
+		},
+		"change too small": {
+			mempoolTransaction: mempoolTransaction(int64(replaceableSequence), "490000"),
+			feeIncrease:        490000,
+			errorCode:          ErrInsufficientFunds.Code,
+		},
+		"no fee increase": {
+			feeIncrease: 0,
+			errorCode:   ErrUnclearIntent.Code,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			mockClient := &mocks.Client{}
+			servicer := NewConstructionAPIService(
+				cfg,
+				mockClient,
+				&mocks.Indexer{},
+				nil,
+			).(*ConstructionAPIService)
+
+			if test.mempoolTransaction != nil || test.mempoolErr != nil {
+				mockClient.On(
+					"GetMempoolTransaction",
+					ctx,
+					txHash,
+				).Return(
+					test.mempoolTransaction,
+					test.mempoolErr,
+				).Once()
+			}
+
+			operations, rosettaErr := servicer.ReplacementOperations(ctx, txHash, test.feeIncrease)
+			mockClient.AssertExpectations(t)
+			if test.errorCode != 0 {
+				assert.Nil(t, operations)
+				assert.Equal(t, test.errorCode, rosettaErr.Code)
+				return
+			}
+
+			assert.Nil(t, rosettaErr)
+			assert.Equal(t, test.operations, operations)
+
+			// The replacement pays a higher fee than the original.
+			fee := func(ops []*types.Operation) int64 {
+				total := int64(0)
+				for _, op := range ops {
+					value, err := strconv.ParseInt(op.Amount.Value, 10, 64)
+					assert.NoError(t, err)
+					total -= value
+				}
+
+				return total
+			}
+			assert.Equal(
+				t,
+				fee(test.mempoolTransaction.Operations)+test.feeIncrease,
+				fee(operations),
+			)
+
+			// The replacement can be constructed like any transfer.
+			_, rosettaErr = servicer.ConstructionPreprocess(ctx, &types.ConstructionPreprocessRequest{
+				NetworkIdentifier: networkIdentifier,
+				Operations:        operations,
+				Metadata:          forceMarshalMap(t, &preprocessMetadata{Replaceable: true}),
+			})
+			assert.Nil(t, rosettaErr)
+		})
+	}
+}
@@ -41,6 +41,18 @@ var (
 		ErrTransactionNotFound,
 		ErrCouldNotGetFeeRate,
 		ErrUnableToGetBalance,
+		ErrRateLimited,
+		ErrInvalidLockTime,
+		ErrReplayBlockChanged,
+		ErrInvalidAssetName,
+		ErrInvalidSignature,
+		ErrUnbalancedTransaction,
+		ErrIncompatibleNode,
+		ErrInvalidCurrency,
+		ErrUnparseableTransaction,
+		ErrFeeTooHigh,
+		ErrInsufficientFunds,
+		ErrInvalidPublicKey,
 	}
 
 	// ErrUnimplemented is returned when an endpoint
@@ -183,6 +195,109 @@ var (
 		Code:    18, //nolint
 		Message: "Unable to get balance",
 	}
+
+	// ErrRateLimited is returned when a client
+	// makes more requests than allowed by the
+	// rate limiter.
+	ErrRateLimited = &types.Error{
+		Code:      19, //nolint
+		Message:   "Rate limit exceeded",
+		Retriable: true,
+	}
+
+	// ErrInvalidLockTime is returned when a provided
+	// locktime does not fit in a uint32.
+	ErrInvalidLockTime = &types.Error{
+		Code:    20, //nolint
+		Message: "Invalid locktime",
+	}
+
+	// ErrReplayBlockChanged is returned when the hash
+	// of the replay block changes while constructing
+	// metadata (i.e. there was a reorg deeper than the
+	// configured confirmation depth).
+	ErrReplayBlockChanged = &types.Error{
+		Code:      21, //nolint
+		Message:   "Replay block changed",
+		Retriable: true,
+	}
+
+	// ErrInvalidAssetName is returned when an operation
+	// references an asset name that is not in its
+	// canonical casing.
+	ErrInvalidAssetName = &types.Error{
+		Code:    22, //nolint
+		Message: "Invalid asset name",
+	}
+
+	// ErrInvalidSignature is returned when a signature
+	// provided to /construction/combine is not an ECDSA
+	// signature of the payload of the input it signs.
+	ErrInvalidSignature = &types.Error{
+		Code:    23, //nolint
+		Message: "Invalid signature",
+	}
+
+	// ErrUnbalancedTransaction is returned when the
+	// outputs of a parsed transaction are worth more
+	// than its inputs or when the quantity of an asset
+	// spent by the operations provided to
+	// /construction/payloads differs from the quantity
+	// it pays.
+	ErrUnbalancedTransaction = &types.Error{
+		Code:    24, //nolint
+		Message: "Transaction outputs exceed inputs",
+	}
+
+	// ErrIncompatibleNode is returned when ravend
+	// runs a protocol version that predates KAWPOW.
+	ErrIncompatibleNode = &types.Error{
+		Code:    25, //nolint
+		Message: "Incompatible ravend version",
+	}
+
+	// ErrInvalidCurrency is returned when the currency
+	// of an operation provided in /construction/preprocess
+	// or /construction/payloads does not match the currency
+	// it moves.
+	ErrInvalidCurrency = &types.Error{
+		Code:    26, //nolint
+		Message: "Invalid operation currency",
+	}
+
+	// ErrUnparseableTransaction is returned when the
+	// transaction provided to /construction/parse is
+	// not a hex-encoded envelope returned by
+	// /construction/payloads or /construction/combine.
+	ErrUnparseableTransaction = &types.Error{
+		Code:    27, //nolint
+		Message: "Unable to parse transaction",
+	}
+
+	// ErrFeeTooHigh is returned when the fee suggested
+	// by /construction/metadata exceeds the configured
+	// MaxFeeAmount.
+	ErrFeeTooHigh = &types.Error{
+		Code:    28, //nolint
+		Message: "Suggested fee exceeds maximum fee",
+	}
+
+	// ErrInsufficientFunds is returned when the coins
+	// spent in /construction/preprocess do not cover the
+	// outputs and the minimum fee. The required and
+	// available Satoshis are populated in its details.
+	ErrInsufficientFunds = &types.Error{
+		Code:    29, //nolint
+		Message: "Insufficient funds",
+	}
+
+	// ErrInvalidPublicKey is returned when a public key
+	// provided to /construction/derive is not a compressed
+	// or uncompressed secp256k1 public key.
+	ErrInvalidPublicKey = &types.Error{
+		Code:    30, //nolint
+		Message: "Invalid public key",
+	}
 )
 
 // wrapErr adds details to the types.Error provided. We use a function
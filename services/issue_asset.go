@@ -0,0 +1,244 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/base58"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// issueAssetDescription matches an optional ISSUE_ASSET
+// operation. Ravencoin only allows a single root asset to
+// be issued in each transaction.
+func (s *ConstructionAPIService) issueAssetDescription() *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: s.opTypes.IssueAsset,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		Amount: &parser.AmountDescription{
+			Exists: true,
+			Sign:   parser.PositiveAmountSign,
+		},
+		Optional: true,
+	}
+}
+
+// burnScript returns the locking script of a burn address.
+// Burn addresses are decoded without checking their network
+// because they are only ever paid to.
+func (s *ConstructionAPIService) burnScript(address string) ([]byte, error) {
+	hash, _, err := base58.CheckDecode(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode burn address %s", err, address)
+	}
+
+	addr, err := btcutil.NewAddressPubKeyHash(hash, s.config.Params.BtcdParams())
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid burn address %s", err, address)
+	}
+
+	return txscript.PayToAddrScript(addr)
+}
+
+// issuanceBurn returns the locking script and amount
+// (in Satoshis) of the burn paid to issue an asset.
+func (s *ConstructionAPIService) issuanceBurn() ([]byte, int64, error) {
+	address, amount := s.config.Params.BurnAddress(chaincfg.BurnIssueAsset)
+	if len(address) == 0 {
+		return nil, 0, fmt.Errorf("no issue asset burn address for %s", s.config.Params.Name)
+	}
+
+	script, err := s.burnScript(address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return script, amount, nil
+}
+
+// issuanceOutputs returns the outputs that issue the asset
+// described by op. Ravencoin requires the owner token and
+// the new asset to be the last two outputs of the transaction,
+// so these are returned in that order following the burn.
+func (s *ConstructionAPIService) issuanceOutputs(op *types.Operation) ([]*wire.TxOut, error) {
+	var metadata issueAssetMetadata
+	if err := types.UnmarshalMap(op.Metadata, &metadata); err != nil {
+		return nil, fmt.Errorf("%w: unable to parse issuance metadata", err)
+	}
+
+	amount, err := types.AmountValue(op.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to parse issuance amount", err)
+	}
+
+	if !amount.IsInt64() {
+		return nil, fmt.Errorf("issuance amount %s is too large", amount.String())
+	}
+
+	burnScript, burnAmount, err := s.issuanceBurn()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := btcutil.DecodeAddress(op.Account.Address, s.config.Params.BtcdParams())
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode address %s", err, op.Account.Address)
+	}
+
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to construct payToAddrScript", err)
+	}
+
+	name := op.Amount.Currency.Symbol
+	ownerScript, err := ravencoin.OwnerAssetScript(script, name)
+	if err != nil {
+		return nil, err
+	}
+
+	assetScript, err := ravencoin.NewAssetScript(
+		script,
+		name,
+		amount.Int64(),
+		metadata.Units,
+		metadata.Reissuable,
+		metadata.IPFSHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*wire.TxOut{
+		{Value: burnAmount, PkScript: burnScript},
+		{Value: 0, PkScript: ownerScript},
+		{Value: 0, PkScript: assetScript},
+	}, nil
+}
+
+// issuanceOutputCount is the number of outputs
+// returned by issuanceOutputs.
+const issuanceOutputCount = 3
+
+// parseIssuance returns the ISSUE_ASSET operation represented
+// by the last issuanceOutputCount outputs of tx, as built by
+// issuanceOutputs. If tx does not issue an asset, nil is
+// returned.
+func (s *ConstructionAPIService) parseIssuance(tx *wire.MsgTx) (*types.Operation, error) {
+	if len(tx.TxOut) < issuanceOutputCount {
+		return nil, nil
+	}
+
+	outputs := tx.TxOut[len(tx.TxOut)-issuanceOutputCount:]
+	burn, owner, asset := outputs[0], outputs[1], outputs[2]
+
+	assetScript, err := ravencoin.ParseAssetScript(asset.PkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if assetScript == nil || assetScript.Type != ravencoin.NewAssetType ||
+		ravencoin.IsOwnerToken(assetScript.Name) {
+		return nil, nil
+	}
+
+	ownerScript, err := ravencoin.ParseAssetScript(owner.PkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerScript == nil || ownerScript.Name != ravencoin.OwnerTokenName(assetScript.Name) {
+		return nil, errors.New("asset issuance is missing the owner token output")
+	}
+
+	burnScript, burnAmount, err := s.issuanceBurn()
+	if err != nil {
+		return nil, err
+	}
+
+	if burn.Value != burnAmount || !bytes.Equal(burn.PkScript, burnScript) {
+		return nil, errors.New("asset issuance is missing the burn output")
+	}
+
+	_, addr, err := ravencoin.ParseSingleAddress(
+		s.config.Params.BtcdParams(),
+		ravencoin.AssetScriptBase(asset.PkScript),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w unable to parse asset output address", err)
+	}
+
+	metadata, err := types.MarshalMap(&issueAssetMetadata{
+		Units:      assetScript.Units,
+		Reissuable: assetScript.Reissuable,
+		IPFSHash:   assetScript.IPFSHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	networkIndex := int64(len(tx.TxOut) - 1)
+	return &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			NetworkIndex: &networkIndex,
+		},
+		Type: s.opTypes.IssueAsset,
+		Account: &types.AccountIdentifier{
+			Address: addr.String(),
+		},
+		Amount: &types.Amount{
+			Value:    strconv.FormatInt(assetScript.Amount, 10),
+			Currency: ravencoin.AssetCurrency(assetScript.Name, ravencoin.MaxAssetUnits),
+		},
+		Metadata: metadata,
+	}, nil
+}
+
+// validateIssuanceFunding returns an error if inputs do
+// not cover outputs and the issuance burn.
+func validateIssuanceFunding(inputs []*big.Int, outputs []*big.Int, burn int64) error {
+	remaining := big.NewInt(0)
+	for _, input := range inputs {
+		remaining.Sub(remaining, input)
+	}
+
+	for _, output := range outputs {
+		remaining.Sub(remaining, output)
+	}
+
+	remaining.Sub(remaining, big.NewInt(burn))
+	if remaining.Sign() < 0 {
+		return fmt.Errorf(
+			"inputs are %s short of the outputs and the issuance burn of %d",
+			new(big.Int).Neg(remaining).String(),
+			burn,
+		)
+	}
+
+	return nil
+}
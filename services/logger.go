@@ -21,6 +21,12 @@ import (
 	"go.uber.org/zap"
 )
 
+// Logger logs structured messages. It is
+// satisfied by *zap.SugaredLogger.
+type Logger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+}
+
 // StatusRecorder is used to surface the status
 // code of a HTTP response. We must use this wrapping
 // because the status code is not exposed by the
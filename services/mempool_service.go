@@ -16,8 +16,10 @@ package services
 
 import (
 	"context"
+	"errors"
 
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
 
 	"github.com/coinbase/rosetta-sdk-go/server"
 	"github.com/coinbase/rosetta-sdk-go/types"
@@ -49,7 +51,7 @@ func (s *MempoolAPIService) Mempool(
 		return nil, wrapErr(ErrUnavailableOffline, nil)
 	}
 
-	mempoolTransactions, err := s.client.RawMempool(ctx)
+	mempoolTransactions, err := s.client.GetRawMempool(ctx)
 	if err != nil {
 		return nil, wrapErr(ErrRavend, err)
 	}
@@ -73,5 +75,24 @@ func (s *MempoolAPIService) MempoolTransaction(
 		return nil, wrapErr(ErrUnavailableOffline, nil)
 	}
 
-	return nil, wrapErr(ErrUnimplemented, nil)
+	entry, err := s.client.GetMempoolEntry(ctx, request.TransactionIdentifier.Hash)
+	if errors.Is(err, ravencoin.ErrTransactionNotInMempool) {
+		return nil, wrapErr(ErrTransactionNotFound, err)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
+	metadata, err := types.MarshalMap(entry)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	return &types.MempoolTransactionResponse{
+		Transaction: &types.Transaction{
+			TransactionIdentifier: request.TransactionIdentifier,
+			Operations:            []*types.Operation{},
+			Metadata:              metadata,
+		},
+	}, nil
 }
@@ -20,6 +20,7 @@ import (
 
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
 	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
 
 	"github.com/coinbase/rosetta-sdk-go/types"
 	"github.com/stretchr/testify/assert"
@@ -53,7 +54,7 @@ func TestMempoolEndpoints_Online(t *testing.T) {
 	servicer := NewMempoolAPIService(cfg, mockClient)
 	ctx := context.Background()
 
-	mockClient.On("RawMempool", ctx).Return([]string{
+	mockClient.On("GetRawMempool", ctx).Return([]string{
 		"tx1",
 		"tx2",
 	}, nil)
@@ -70,9 +71,45 @@ func TestMempoolEndpoints_Online(t *testing.T) {
 		},
 	}, mem)
 
-	memTransaction, err := servicer.MempoolTransaction(ctx, nil)
+	entry := &ravencoin.MempoolEntry{
+		Size:            225,
+		Fee:             0.0000226,
+		Time:            1604423617,
+		AncestorCount:   2,
+		DescendantCount: 1,
+	}
+	mockClient.On("GetMempoolEntry", ctx, "tx1").Return(entry, nil).Once()
+	memTransaction, err := servicer.MempoolTransaction(ctx, &types.MempoolTransactionRequest{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx1"},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, &types.MempoolTransactionResponse{
+		Transaction: &types.Transaction{
+			TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx1"},
+			Operations:            []*types.Operation{},
+			Metadata: map[string]interface{}{
+				"size":            float64(225),
+				"fee":             0.0000226,
+				"time":            float64(1604423617),
+				"height":          float64(0),
+				"ancestorcount":   float64(2),
+				"ancestorsize":    float64(0),
+				"descendantcount": float64(1),
+				"descendantsize":  float64(0),
+				"depends":         nil,
+			},
+		},
+	}, memTransaction)
+
+	mockClient.On(
+		"GetMempoolEntry",
+		ctx,
+		"tx3",
+	).Return(nil, ravencoin.ErrTransactionNotInMempool).Once()
+	memTransaction, err = servicer.MempoolTransaction(ctx, &types.MempoolTransactionRequest{
+		TransactionIdentifier: &types.TransactionIdentifier{Hash: "tx3"},
+	})
 	assert.Nil(t, memTransaction)
-	assert.Equal(t, ErrUnimplemented.Code, err.Code)
-	assert.Equal(t, ErrUnimplemented.Message, err.Message)
+	assert.Equal(t, ErrTransactionNotFound.Code, err.Code)
 	mockClient.AssertExpectations(t)
 }
@@ -0,0 +1,125 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/btcsuite/btcd/btcec"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// multisigSigners returns the accounts of the keys that signed
+// a P2SH-multisig input. Each signature is matched to the
+// public keys of the redeem script in order, as OP_CHECKMULTISIG
+// does. Nil is returned if the input does not spend a
+// P2SH-multisig output.
+//
+// If fundingScript, the locking script of the output spent
+// by the input, is provided, the input is only treated as a
+// multisig spend if fundingScript pays to the hash of its
+// redeem script. Otherwise, any input whose last push is a
+// multisig script is.
+func (s *ConstructionAPIService) multisigSigners(
+	tx *wire.MsgTx,
+	i int,
+	fundingScript []byte,
+) ([]*types.AccountIdentifier, error) {
+	pushes, err := txscript.PushedData(tx.TxIn[i].SignatureScript)
+	if err != nil || len(pushes) < 2 {
+		return nil, nil
+	}
+
+	redeemScript := pushes[len(pushes)-1]
+	if txscript.GetScriptClass(redeemScript) != txscript.MultiSigTy {
+		return nil, nil
+	}
+
+	if fundingScript != nil {
+		p2shScript, err := txscript.NewScriptBuilder().
+			AddOp(txscript.OP_HASH160).
+			AddData(btcutil.Hash160(redeemScript)).
+			AddOp(txscript.OP_EQUAL).
+			Script()
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to build p2sh script of input %d", err, i)
+		}
+
+		if !bytes.Equal(ravencoin.AssetScriptBase(fundingScript), p2shScript) {
+			return nil, nil
+		}
+	}
+
+	_, addrs, _, err := txscript.ExtractPkScriptAddrs(
+		redeemScript,
+		s.config.Params.BtcdParams(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to extract multisig public keys", err)
+	}
+
+	signers := []*types.AccountIdentifier{}
+	next := 0
+	for _, sig := range pushes[:len(pushes)-1] {
+		// The leading OP_0 consumed by the OP_CHECKMULTISIG
+		// off-by-one bug pushes no data.
+		if len(sig) == 0 {
+			continue
+		}
+
+		hashType := txscript.SigHashType(sig[len(sig)-1])
+		signature, err := btcec.ParseDERSignature(sig[:len(sig)-1], btcec.S256())
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to parse signature of input %d", err, i)
+		}
+
+		hash, err := txscript.CalcSignatureHash(redeemScript, hashType, tx, i)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to calculate signature hash of input %d", err, i)
+		}
+
+		matched := false
+		for ; next < len(addrs); next++ {
+			pubKey, ok := addrs[next].(*btcutil.AddressPubKey)
+			if !ok || !signature.Verify(hash, pubKey.PubKey()) {
+				continue
+			}
+
+			address, err := s.config.Params.EncodeP2PKH(btcutil.Hash160(pubKey.ScriptAddress()))
+			if err != nil {
+				return nil, fmt.Errorf("%w: unable to encode signer of input %d", err, i)
+			}
+
+			signers = append(signers, &types.AccountIdentifier{
+				Address: address,
+			})
+			next++
+			matched = true
+			break
+		}
+
+		if !matched {
+			return nil, fmt.Errorf("signature of input %d does not match any public key", i)
+		}
+	}
+
+	return signers, nil
+}
@@ -16,6 +16,8 @@ package services
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
@@ -65,6 +67,20 @@ func (s *NetworkAPIService) NetworkStatus(
 		return nil, wrapErr(ErrUnavailableOffline, nil)
 	}
 
+	nodeInfo, err := s.client.GetNodeInfo(ctx)
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
+	if nodeInfo.ProtocolVersion < ravencoin.KawpowProtocolVersion {
+		return nil, wrapErr(ErrIncompatibleNode, fmt.Errorf(
+			"%s has protocol version %d but %d is required",
+			nodeInfo.SubVersion,
+			nodeInfo.ProtocolVersion,
+			ravencoin.KawpowProtocolVersion,
+		))
+	}
+
 	peers, err := s.client.GetPeers(ctx)
 	if err != nil {
 		return nil, wrapErr(ErrRavend, err)
@@ -75,14 +91,111 @@ func (s *NetworkAPIService) NetworkStatus(
 		return nil, wrapErr(ErrNotReady, nil)
 	}
 
+	// If the indexer synced past the max servable
+	// height before it was configured, we report the
+	// pinned block as the tip.
+	maxServableHeight := s.config.MaxServableHeight
+	if maxServableHeight > 0 && cachedBlockResponse.Block.BlockIdentifier.Index > maxServableHeight {
+		cachedBlockResponse, err = s.i.GetBlockLazy(
+			ctx,
+			&types.PartialBlockIdentifier{Index: &maxServableHeight},
+		)
+		if err != nil {
+			return nil, wrapErr(ErrBlockNotFound, err)
+		}
+	}
+
+	// Only the header of the node's best block is
+	// needed, so we avoid fetching its transactions.
+	bestHash, err := s.client.GetBestBlockHash(ctx)
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
+	best, err := s.client.GetBlockHeader(ctx, bestHash)
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
+	difficulty, err := best.Difficulty()
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
 	return &types.NetworkStatusResponse{
 		CurrentBlockIdentifier: cachedBlockResponse.Block.BlockIdentifier,
 		CurrentBlockTimestamp:  cachedBlockResponse.Block.Timestamp,
 		GenesisBlockIdentifier: s.config.GenesisBlockIdentifier,
-		Peers:                  peers,
+		SyncStatus:             s.syncStatus(cachedBlockResponse.Block, best),
+		Peers:                  withChainDifficulty(peers, difficulty),
 	}, nil
 }
 
+// withChainDifficulty returns a copy of peers with
+// difficulty added to the metadata of each peer, as
+// the /network/status response has no metadata of
+// its own.
+func withChainDifficulty(peers []*types.Peer, difficulty float64) []*types.Peer {
+	reported := make([]*types.Peer, len(peers))
+	for i, peer := range peers {
+		metadata := map[string]interface{}{}
+		for k, v := range peer.Metadata {
+			metadata[k] = v
+		}
+		metadata[ChainDifficultyKey] = difficulty
+
+		reported[i] = &types.Peer{
+			PeerID:   peer.PeerID,
+			Metadata: metadata,
+		}
+	}
+
+	return reported
+}
+
+// syncStatus returns the *types.SyncStatus of tip
+// relative to best, the header of the node's best
+// block. If tip was mined longer ago than the
+// configured TipStaleThreshold, it is reported in
+// the TipStaleStage.
+func (s *NetworkAPIService) syncStatus(
+	tip *types.Block,
+	best *ravencoin.BlockHeader,
+) *types.SyncStatus {
+	// The indexer never serves past the max
+	// servable height, so it is not behind
+	// once it reaches it.
+	targetIndex := best.Height
+	maxServableHeight := s.config.MaxServableHeight
+	if maxServableHeight > 0 && targetIndex > maxServableHeight {
+		targetIndex = maxServableHeight
+	}
+
+	status := &types.SyncStatus{
+		CurrentIndex: types.Int64(tip.BlockIdentifier.Index),
+		TargetIndex:  types.Int64(targetIndex),
+		Synced:       types.Bool(tip.BlockIdentifier.Index >= targetIndex),
+	}
+
+	if s.tipStale(tip) {
+		status.Stage = types.String(TipStaleStage)
+		status.Synced = types.Bool(false)
+	}
+
+	return status
+}
+
+// tipStale returns true if tip was mined longer
+// ago than the configured TipStaleThreshold.
+func (s *NetworkAPIService) tipStale(tip *types.Block) bool {
+	if s.config.TipStaleThreshold <= 0 {
+		return false
+	}
+
+	mined := time.Unix(0, tip.Timestamp*int64(time.Millisecond))
+	return time.Since(mined) > s.config.TipStaleThreshold
+}
+
 // NetworkOptions implements the /network/options endpoint.
 func (s *NetworkAPIService) NetworkOptions(
 	ctx context.Context,
@@ -96,7 +209,7 @@ func (s *NetworkAPIService) NetworkOptions(
 		},
 		Allow: &types.Allow{
 			OperationStatuses:       ravencoin.OperationStatuses,
-			OperationTypes:          ravencoin.OperationTypes,
+			OperationTypes:          s.config.OperationTypes.Supported(),
 			Errors:                  Errors,
 			HistoricalBalanceLookup: HistoricalBalanceLookup,
 			MempoolCoins:            MempoolCoins,
@@ -17,6 +17,7 @@ package services
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
 	"github.com/RavenProject/rosetta-ravencoin/configuration"
@@ -46,6 +47,13 @@ var (
 		Network:    ravencoin.MainnetNetwork,
 		Blockchain: ravencoin.Blockchain,
 	}
+
+	kawpowNodeInfo = &ravencoin.NodeInfo{
+		ProtocolVersion: 70028,
+		SubVersion:      "/Ravencoin:4.3.2.1/",
+		Blocks:          1000,
+		Connections:     8,
+	}
 )
 
 func TestNetworkEndpoints_Offline(t *testing.T) {
@@ -102,6 +110,7 @@ func TestNetworkEndpoints_Online(t *testing.T) {
 			},
 		},
 	}
+	mockClient.On("GetNodeInfo", ctx).Return(kawpowNodeInfo, nil)
 	mockClient.On("GetPeers", ctx).Return([]*types.Peer{
 		{
 			PeerID: "77.93.223.9:8333",
@@ -115,14 +124,30 @@ func TestNetworkEndpoints_Online(t *testing.T) {
 		blockResponse,
 		nil,
 	)
+
+	// The node is ahead of the indexer.
+	mockClient.On("GetBestBlockHash", ctx).Return("block 105", nil)
+	mockClient.On("GetBlockHeader", ctx, "block 105").Return(&ravencoin.BlockHeader{
+		Hash:   "block 105",
+		Height: 105,
+		Bits:   "1d00ffff",
+	}, nil)
 	networkStatus, err := servicer.NetworkStatus(ctx, nil)
 	assert.Nil(t, err)
 	assert.Equal(t, &types.NetworkStatusResponse{
 		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
 		CurrentBlockIdentifier: blockResponse.Block.BlockIdentifier,
+		SyncStatus: &types.SyncStatus{
+			CurrentIndex: types.Int64(100),
+			TargetIndex:  types.Int64(105),
+			Synced:       types.Bool(false),
+		},
 		Peers: []*types.Peer{
 			{
 				PeerID: "77.93.223.9:8333",
+				Metadata: map[string]interface{}{
+					ChainDifficultyKey: float64(1),
+				},
 			},
 		},
 	}, networkStatus)
@@ -134,3 +159,253 @@ func TestNetworkEndpoints_Online(t *testing.T) {
 	mockIndexer.AssertExpectations(t)
 	mockClient.AssertExpectations(t)
 }
+
+func TestNetworkStatus_MaxServableHeight(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                   configuration.Online,
+		Network:                networkIdentifier,
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		MaxServableHeight:      500,
+	}
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewNetworkAPIService(cfg, mockClient, mockIndexer)
+	ctx := context.Background()
+
+	// The indexer synced to 1000 before the pin
+	// was configured.
+	mockClient.On("GetNodeInfo", ctx).Return(kawpowNodeInfo, nil)
+	mockClient.On("GetPeers", ctx).Return([]*types.Peer{}, nil)
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		(*types.PartialBlockIdentifier)(nil),
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: 1000,
+					Hash:  "block 1000",
+				},
+				Timestamp: 1600001000000,
+			},
+		},
+		nil,
+	).Once()
+	pinnedIndex := int64(500)
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		&types.PartialBlockIdentifier{Index: &pinnedIndex},
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: 500,
+					Hash:  "block 500",
+				},
+				Timestamp: 1600000500000,
+			},
+		},
+		nil,
+	).Once()
+	mockClient.On("GetBestBlockHash", ctx).Return("block 1000", nil)
+	mockClient.On("GetBlockHeader", ctx, "block 1000").Return(&ravencoin.BlockHeader{
+		Hash:   "block 1000",
+		Height: 1000,
+		Bits:   "1d00ffff",
+	}, nil)
+
+	// The pinned block is the sync target.
+	networkStatus, err := servicer.NetworkStatus(ctx, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, &types.NetworkStatusResponse{
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+		CurrentBlockIdentifier: &types.BlockIdentifier{
+			Index: 500,
+			Hash:  "block 500",
+		},
+		CurrentBlockTimestamp: 1600000500000,
+		SyncStatus: &types.SyncStatus{
+			CurrentIndex: types.Int64(500),
+			TargetIndex:  types.Int64(500),
+			Synced:       types.Bool(true),
+		},
+		Peers: []*types.Peer{},
+	}, networkStatus)
+
+	mockIndexer.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func TestNetworkStatus_TipStale(t *testing.T) {
+	tests := map[string]struct {
+		age time.Duration
+
+		syncStatus *types.SyncStatus
+	}{
+		"recent tip": {
+			age: 30 * time.Second,
+			syncStatus: &types.SyncStatus{
+				CurrentIndex: types.Int64(1000),
+				TargetIndex:  types.Int64(1000),
+				Synced:       types.Bool(true),
+			},
+		},
+		"stale tip": {
+			age: time.Hour,
+			syncStatus: &types.SyncStatus{
+				CurrentIndex: types.Int64(1000),
+				TargetIndex:  types.Int64(1000),
+				Stage:        types.String(TipStaleStage),
+				Synced:       types.Bool(false),
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:                   configuration.Online,
+				Network:                networkIdentifier,
+				GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+				TipStaleThreshold:      3 * time.Minute,
+			}
+			mockIndexer := &mocks.Indexer{}
+			mockClient := &mocks.Client{}
+			servicer := NewNetworkAPIService(cfg, mockClient, mockIndexer)
+			ctx := context.Background()
+
+			timestamp := time.Now().Add(-test.age).UnixNano() / int64(time.Millisecond)
+			mockClient.On("GetNodeInfo", ctx).Return(kawpowNodeInfo, nil)
+			mockClient.On("GetPeers", ctx).Return([]*types.Peer{}, nil)
+			mockIndexer.On(
+				"GetBlockLazy",
+				ctx,
+				(*types.PartialBlockIdentifier)(nil),
+			).Return(
+				&types.BlockResponse{
+					Block: &types.Block{
+						BlockIdentifier: &types.BlockIdentifier{
+							Index: 1000,
+							Hash:  "block 1000",
+						},
+						Timestamp: timestamp,
+					},
+				},
+				nil,
+			).Once()
+			mockClient.On("GetBestBlockHash", ctx).Return("block 1000", nil)
+			mockClient.On("GetBlockHeader", ctx, "block 1000").Return(&ravencoin.BlockHeader{
+				Hash:   "block 1000",
+				Height: 1000,
+				Bits:   "1d00ffff",
+			}, nil)
+
+			networkStatus, err := servicer.NetworkStatus(ctx, nil)
+			assert.Nil(t, err)
+			assert.Equal(t, &types.NetworkStatusResponse{
+				GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+				CurrentBlockIdentifier: &types.BlockIdentifier{
+					Index: 1000,
+					Hash:  "block 1000",
+				},
+				CurrentBlockTimestamp: timestamp,
+				SyncStatus:            test.syncStatus,
+				Peers:                 []*types.Peer{},
+			}, networkStatus)
+
+			mockIndexer.AssertExpectations(t)
+			mockClient.AssertExpectations(t)
+		})
+	}
+}
+
+func TestNetworkStatus_ChainDifficulty(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                   configuration.Online,
+		Network:                networkIdentifier,
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+	}
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewNetworkAPIService(cfg, mockClient, mockIndexer)
+	ctx := context.Background()
+
+	mockClient.On("GetNodeInfo", ctx).Return(kawpowNodeInfo, nil)
+	mockClient.On("GetPeers", ctx).Return([]*types.Peer{
+		{
+			PeerID: "77.93.223.9:8767",
+			Metadata: map[string]interface{}{
+				"version": float64(70028),
+			},
+		},
+		{
+			PeerID: "104.248.42.7:8767",
+		},
+	}, nil)
+	mockIndexer.On(
+		"GetBlockLazy",
+		ctx,
+		(*types.PartialBlockIdentifier)(nil),
+	).Return(
+		&types.BlockResponse{
+			Block: &types.Block{
+				BlockIdentifier: &types.BlockIdentifier{
+					Index: 1219736,
+					Hash:  "block 1219736",
+				},
+			},
+		},
+		nil,
+	).Once()
+	mockClient.On("GetBestBlockHash", ctx).Return("block 1219736", nil)
+	mockClient.On("GetBlockHeader", ctx, "block 1219736").Return(&ravencoin.BlockHeader{
+		Hash:   "block 1219736",
+		Height: 1219736,
+		Bits:   "1b01a4f3",
+	}, nil)
+
+	networkStatus, err := servicer.NetworkStatus(ctx, nil)
+	assert.Nil(t, err)
+	assert.Len(t, networkStatus.Peers, 2)
+	for _, peer := range networkStatus.Peers {
+		assert.InDelta(t, 39855.068622811166, peer.Metadata[ChainDifficultyKey], 1e-9)
+	}
+	assert.Equal(t, float64(70028), networkStatus.Peers[0].Metadata["version"])
+
+	mockIndexer.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
+
+func TestNetworkStatus_IncompatibleNode(t *testing.T) {
+	cfg := &configuration.Configuration{
+		Mode:                   configuration.Online,
+		Network:                networkIdentifier,
+		GenesisBlockIdentifier: ravencoin.MainnetGenesisBlockIdentifier,
+	}
+	mockIndexer := &mocks.Indexer{}
+	mockClient := &mocks.Client{}
+	servicer := NewNetworkAPIService(cfg, mockClient, mockIndexer)
+	ctx := context.Background()
+
+	// Protocol version 70026 predates KAWPOW.
+	mockClient.On("GetNodeInfo", ctx).Return(&ravencoin.NodeInfo{
+		ProtocolVersion: 70026,
+		SubVersion:      "/Ravencoin:3.3.1/",
+		Blocks:          1000,
+		Connections:     8,
+	}, nil)
+
+	networkStatus, err := servicer.NetworkStatus(ctx, nil)
+	assert.Nil(t, networkStatus)
+	assert.Equal(t, ErrIncompatibleNode.Code, err.Code)
+	assert.Equal(
+		t,
+		"/Ravencoin:3.3.1/ has protocol version 70026 but 70027 is required",
+		err.Details["context"],
+	)
+
+	mockIndexer.AssertExpectations(t)
+	mockClient.AssertExpectations(t)
+}
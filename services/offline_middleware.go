@@ -0,0 +1,65 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// onlineEndpoints are the endpoints that require
+// access to ravend or the indexer (so they cannot
+// be served in offline mode).
+var onlineEndpoints = map[string]struct{}{
+	"/network/status":        {},
+	"/account/balance":       {},
+	"/account/coins":         {},
+	"/block":                 {},
+	"/block/transaction":     {},
+	"/mempool":               {},
+	"/mempool/transaction":   {},
+	"/construction/metadata": {},
+	"/construction/submit":   {},
+}
+
+// OfflineMiddleware rejects requests to endpoints that
+// require node access with ErrUnavailableOffline when
+// the implementation is running in offline mode.
+func OfflineMiddleware(config *configuration.Configuration, inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := onlineEndpoints[r.URL.Path]; !ok || config.Mode == configuration.Online {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(errUnavailableOffline(r.URL.Path))
+	})
+}
+
+// errUnavailableOffline returns ErrUnavailableOffline
+// with the endpoint that was rejected.
+func errUnavailableOffline(endpoint string) *types.Error {
+	return wrapErr(
+		ErrUnavailableOffline,
+		fmt.Errorf("%s is not available in offline mode", endpoint),
+	)
+}
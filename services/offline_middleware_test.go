@@ -0,0 +1,87 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOfflineMiddleware(t *testing.T) {
+	onlineOnly := []string{
+		"/network/status",
+		"/account/balance",
+		"/account/coins",
+		"/block",
+		"/block/transaction",
+		"/mempool",
+		"/mempool/transaction",
+		"/construction/metadata",
+		"/construction/submit",
+	}
+	offlineSafe := []string{
+		"/network/list",
+		"/network/options",
+		"/construction/derive",
+		"/construction/preprocess",
+		"/construction/payloads",
+		"/construction/combine",
+		"/construction/parse",
+		"/construction/hash",
+	}
+
+	serve := func(mode configuration.Mode, path string) *httptest.ResponseRecorder {
+		handler := OfflineMiddleware(
+			&configuration.Configuration{Mode: mode},
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}),
+		)
+
+		request := httptest.NewRequest(http.MethodPost, path, nil)
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	for _, path := range onlineOnly {
+		t.Run("offline "+path, func(t *testing.T) {
+			recorder := serve(configuration.Offline, path)
+			assert.Equal(t, http.StatusInternalServerError, recorder.Code)
+
+			var rosettaErr types.Error
+			assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rosettaErr))
+			assert.Equal(t, ErrUnavailableOffline.Code, rosettaErr.Code)
+		})
+
+		t.Run("online "+path, func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, serve(configuration.Online, path).Code)
+		})
+	}
+
+	for _, path := range offlineSafe {
+		t.Run("offline "+path, func(t *testing.T) {
+			assert.Equal(t, http.StatusOK, serve(configuration.Offline, path).Code)
+		})
+	}
+}
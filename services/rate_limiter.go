@@ -0,0 +1,180 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+)
+
+const (
+	// rateLimiterSweepSize is the number of tracked
+	// client IPs at which we remove the buckets of
+	// clients that have been idle long enough to be full.
+	rateLimiterSweepSize = 10000
+)
+
+// tokenBucket tracks the requests a single client
+// IP is allowed to make.
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// RateLimiter is a token bucket rate limiter
+// keyed by client IP.
+type RateLimiter struct {
+	rate      float64
+	burst     float64
+	allowList []*net.IPNet
+
+	buckets map[string]*tokenBucket
+	lock    sync.Mutex
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// NewRateLimiter returns a new *RateLimiter.
+func NewRateLimiter(config *configuration.RateLimitConfiguration) (*RateLimiter, error) {
+	allowList := make([]*net.IPNet, len(config.AllowList))
+	for i, entry := range config.AllowList {
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("%s is not a valid IP", entry)
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip = ip.To4()
+				bits = 8 * net.IPv4len
+			}
+
+			allowList[i] = &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}
+			continue
+		}
+
+		_, ipNet, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s is not a valid CIDR", err, entry)
+		}
+
+		allowList[i] = ipNet
+	}
+
+	return &RateLimiter{
+		rate:      config.Rate,
+		burst:     float64(config.Burst),
+		allowList: allowList,
+		buckets:   map[string]*tokenBucket{},
+		now:       time.Now,
+	}, nil
+}
+
+// allowListed returns a boolean indicating if
+// ip is exempt from rate limiting.
+func (l *RateLimiter) allowListed(ip net.IP) bool {
+	for _, ipNet := range l.allowList {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Allow returns a boolean indicating if a request
+// from ip should be served.
+func (l *RateLimiter) Allow(ip net.IP) bool {
+	if l.allowListed(ip) {
+		return true
+	}
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	now := l.now()
+	if len(l.buckets) >= rateLimiterSweepSize {
+		l.sweep(now)
+	}
+
+	key := ip.String()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	bucket.tokens = math.Min(l.burst, bucket.tokens+elapsed*l.rate)
+	bucket.last = now
+
+	if bucket.tokens < 1 {
+		return false
+	}
+
+	bucket.tokens--
+	return true
+}
+
+// sweep removes all buckets that would be full at
+// now (these are identical to a new bucket).
+func (l *RateLimiter) sweep(now time.Time) {
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// clientIP returns the IP of the client that
+// made a request.
+func clientIP(r *http.Request) net.IP {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	return net.ParseIP(host)
+}
+
+// RateLimiterMiddleware rejects requests from client IPs
+// that exceed the limits of the provided *RateLimiter
+// with ErrRateLimited.
+func RateLimiterMiddleware(limiter *RateLimiter, inner http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := clientIP(r)
+		if ip == nil || limiter.Allow(ip) {
+			inner.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusTooManyRequests)
+		_ = json.NewEncoder(w).Encode(wrapErr(
+			ErrRateLimited,
+			fmt.Errorf("%s exceeded %g requests per second", ip.String(), limiter.rate),
+		))
+	})
+}
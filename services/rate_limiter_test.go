@@ -0,0 +1,94 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiterMiddleware(t *testing.T) {
+	limiter, err := NewRateLimiter(&configuration.RateLimitConfiguration{
+		Rate:      1,
+		Burst:     2,
+		AllowList: []string{"10.0.0.0/8", "192.168.1.1"},
+	})
+	assert.NoError(t, err)
+
+	now := time.Unix(1600000000, 0)
+	limiter.now = func() time.Time { return now }
+
+	handler := RateLimiterMiddleware(
+		limiter,
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)
+
+	serve := func(remoteAddr string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(http.MethodPost, "/network/status", nil)
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, request)
+
+		return recorder
+	}
+
+	t.Run("limited IP", func(t *testing.T) {
+		assert.Equal(t, http.StatusOK, serve("1.2.3.4:5000").Code)
+		assert.Equal(t, http.StatusOK, serve("1.2.3.4:5001").Code)
+
+		recorder := serve("1.2.3.4:5002")
+		assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+
+		var rosettaErr types.Error
+		assert.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rosettaErr))
+		assert.Equal(t, ErrRateLimited.Code, rosettaErr.Code)
+		assert.True(t, rosettaErr.Retriable)
+
+		// Other IPs are tracked separately
+		assert.Equal(t, http.StatusOK, serve("5.6.7.8:5000").Code)
+
+		// Tokens are refilled over time
+		now = now.Add(time.Second)
+		assert.Equal(t, http.StatusOK, serve("1.2.3.4:5003").Code)
+		assert.Equal(t, http.StatusTooManyRequests, serve("1.2.3.4:5004").Code)
+	})
+
+	t.Run("allow-listed IPs", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			assert.Equal(t, http.StatusOK, serve("10.1.2.3:5000").Code)
+			assert.Equal(t, http.StatusOK, serve("192.168.1.1:5000").Code)
+		}
+	})
+}
+
+func TestNewRateLimiter_InvalidAllowList(t *testing.T) {
+	limiter, err := NewRateLimiter(&configuration.RateLimitConfiguration{
+		Rate:      1,
+		Burst:     1,
+		AllowList: []string{"not an ip"},
+	})
+	assert.Nil(t, limiter)
+	assert.Error(t, err)
+}
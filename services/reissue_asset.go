@@ -0,0 +1,234 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin/chaincfg"
+
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/coinbase/rosetta-sdk-go/parser"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// reissueAssetDescription matches an optional REISSUE_ASSET
+// operation. The operation spends the owner token of the
+// asset, which is returned to its account along with the
+// reissued quantity.
+func (s *ConstructionAPIService) reissueAssetDescription() *parser.OperationDescription {
+	return &parser.OperationDescription{
+		Type: s.opTypes.ReissueAsset,
+		Account: &parser.AccountDescription{
+			Exists: true,
+		},
+		CoinAction: types.CoinSpent,
+		Optional:   true,
+	}
+}
+
+// parseReissueAssetMetadata returns the reissueAssetMetadata
+// of the REISSUE_ASSET operation op and the quantity it
+// reissues (in Satoshis).
+func parseReissueAssetMetadata(op *types.Operation) (*reissueAssetMetadata, int64, error) {
+	var metadata reissueAssetMetadata
+	if err := types.UnmarshalMap(op.Metadata, &metadata); err != nil {
+		return nil, 0, fmt.Errorf("%w: unable to parse reissuance metadata", err)
+	}
+
+	quantity, err := strconv.ParseInt(metadata.Quantity, 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: unable to parse reissuance quantity %s", err, metadata.Quantity)
+	}
+
+	return &metadata, quantity, nil
+}
+
+// reissuanceOwnerCoin returns the owner token coin spent
+// by the REISSUE_ASSET operation op.
+func reissuanceOwnerCoin(op *types.Operation) (*types.Coin, error) {
+	if op.CoinChange == nil {
+		return nil, errors.New("CoinChange cannot be nil")
+	}
+
+	metadata, _, err := parseReissueAssetMetadata(op)
+	if err != nil {
+		return nil, err
+	}
+
+	return &types.Coin{
+		CoinIdentifier: op.CoinChange.CoinIdentifier,
+		Amount: &types.Amount{
+			Value: strconv.FormatInt(-ravencoin.OwnerAssetAmount, 10),
+			Currency: ravencoin.AssetCurrency(
+				ravencoin.OwnerTokenName(metadata.AssetName),
+				ravencoin.MaxAssetUnits,
+			),
+		},
+	}, nil
+}
+
+// reissuanceBurn returns the locking script and amount
+// (in Satoshis) of the burn paid to reissue an asset.
+func (s *ConstructionAPIService) reissuanceBurn() ([]byte, int64, error) {
+	address, amount := s.config.Params.BurnAddress(chaincfg.BurnReissueAsset)
+	if len(address) == 0 {
+		return nil, 0, fmt.Errorf("no reissue asset burn address for %s", s.config.Params.Name)
+	}
+
+	script, err := s.burnScript(address)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return script, amount, nil
+}
+
+// reissuanceOutputs returns the outputs that reissue the asset
+// described by op. Ravencoin requires the reissuance to be the
+// last output of the transaction, so it follows the burn and
+// the owner token returned to the account of op.
+func (s *ConstructionAPIService) reissuanceOutputs(op *types.Operation) ([]*wire.TxOut, error) {
+	metadata, quantity, err := parseReissueAssetMetadata(op)
+	if err != nil {
+		return nil, err
+	}
+
+	burnScript, burnAmount, err := s.reissuanceBurn()
+	if err != nil {
+		return nil, err
+	}
+
+	addr, err := btcutil.DecodeAddress(op.Account.Address, s.config.Params.BtcdParams())
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to decode address %s", err, op.Account.Address)
+	}
+
+	script, err := txscript.PayToAddrScript(addr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to construct payToAddrScript", err)
+	}
+
+	ownerScript, err := ravencoin.TransferAssetScript(
+		script,
+		ravencoin.OwnerTokenName(metadata.AssetName),
+		ravencoin.OwnerAssetAmount,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	reissueScript, err := ravencoin.NewReissueAssetScript(
+		script,
+		metadata.AssetName,
+		quantity,
+		metadata.Units,
+		metadata.Reissuable,
+		metadata.IPFSHash,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*wire.TxOut{
+		{Value: burnAmount, PkScript: burnScript},
+		{Value: 0, PkScript: ownerScript},
+		{Value: 0, PkScript: reissueScript},
+	}, nil
+}
+
+// reissuanceOutputCount is the number of outputs
+// returned by reissuanceOutputs.
+const reissuanceOutputCount = 3
+
+// parseReissuance returns the REISSUE_ASSET operation represented
+// by the last reissuanceOutputCount outputs of tx, as built by
+// reissuanceOutputs. The operation does not yet have a CoinChange
+// (the owner token is spent by the last input of tx). If tx does
+// not reissue an asset, nil is returned.
+func (s *ConstructionAPIService) parseReissuance(tx *wire.MsgTx) (*types.Operation, error) {
+	if len(tx.TxOut) < reissuanceOutputCount || len(tx.TxIn) == 0 {
+		return nil, nil
+	}
+
+	outputs := tx.TxOut[len(tx.TxOut)-reissuanceOutputCount:]
+	burn, owner, reissue := outputs[0], outputs[1], outputs[2]
+
+	reissueScript, err := ravencoin.ParseAssetScript(reissue.PkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if reissueScript == nil || reissueScript.Type != ravencoin.ReissueAssetType {
+		return nil, nil
+	}
+
+	ownerScript, err := ravencoin.ParseAssetScript(owner.PkScript)
+	if err != nil {
+		return nil, err
+	}
+
+	if ownerScript == nil ||
+		ownerScript.Type != ravencoin.TransferAssetType ||
+		ownerScript.Name != ravencoin.OwnerTokenName(reissueScript.Name) {
+		return nil, errors.New("asset reissuance is missing the owner token output")
+	}
+
+	burnScript, burnAmount, err := s.reissuanceBurn()
+	if err != nil {
+		return nil, err
+	}
+
+	if burn.Value != burnAmount || !bytes.Equal(burn.PkScript, burnScript) {
+		return nil, errors.New("asset reissuance is missing the burn output")
+	}
+
+	_, addr, err := ravencoin.ParseSingleAddress(
+		s.config.Params.BtcdParams(),
+		ravencoin.AssetScriptBase(reissue.PkScript),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("%w unable to parse reissuance output address", err)
+	}
+
+	metadata, err := types.MarshalMap(&reissueAssetMetadata{
+		AssetName:  reissueScript.Name,
+		Quantity:   strconv.FormatInt(reissueScript.Amount, 10),
+		Units:      reissueScript.Units,
+		Reissuable: reissueScript.Reissuable,
+		IPFSHash:   reissueScript.IPFSHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	networkIndex := int64(len(tx.TxOut) - 1)
+	return &types.Operation{
+		OperationIdentifier: &types.OperationIdentifier{
+			NetworkIndex: &networkIndex,
+		},
+		Type: s.opTypes.ReissueAsset,
+		Account: &types.AccountIdentifier{
+			Address: addr.String(),
+		},
+		Metadata: metadata,
+	}, nil
+}
@@ -0,0 +1,143 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// ReplacementOperations returns the operations of a transaction
+// that replaces (BIP125) the transaction txHash in the mempool
+// and pays feeIncrease (in Satoshis) more in fees. It spends the
+// same coins and makes the same payments, except that the fee
+// increase is taken from the change output (the last output of
+// the configured currency paid to an account that funded the
+// transaction).
+//
+// The operations are passed to /construction/preprocess like
+// any others (with replaceable set, so that the replacement can
+// be replaced in turn).
+func (s *ConstructionAPIService) ReplacementOperations(
+	ctx context.Context,
+	txHash string,
+	feeIncrease int64,
+) ([]*types.Operation, *types.Error) {
+	if s.config.Mode != configuration.Online {
+		return nil, wrapErr(ErrUnavailableOffline, nil)
+	}
+
+	if feeIncrease <= 0 {
+		return nil, wrapErr(
+			ErrUnclearIntent,
+			fmt.Errorf("fee increase %d must be positive", feeIncrease),
+		)
+	}
+
+	tx, err := s.client.GetMempoolTransaction(ctx, txHash)
+	if errors.Is(err, ravencoin.ErrTransactionNotInMempool) {
+		return nil, wrapErr(ErrTransactionNotFound, err)
+	}
+	if err != nil {
+		return nil, wrapErr(ErrRavend, err)
+	}
+
+	// Inputs are always parsed before outputs, so all
+	// funders are known when the outputs are reached.
+	funders := map[string]struct{}{}
+	replaceable := false
+	change := -1
+	ops := []*types.Operation{}
+	for _, op := range tx.Operations {
+		replacement := &types.Operation{
+			OperationIdentifier: &types.OperationIdentifier{
+				Index: int64(len(ops)),
+			},
+			Account: op.Account,
+			Amount:  op.Amount,
+		}
+
+		switch op.Type {
+		case ravencoin.InputOpType:
+			var metadata ravencoin.OperationMetadata
+			if err := types.UnmarshalMap(op.Metadata, &metadata); err != nil {
+				return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+			}
+
+			if metadata.Sequence <= int64(replaceableSequence) {
+				replaceable = true
+			}
+
+			funders[op.Account.Address] = struct{}{}
+			replacement.Type = s.opTypes.Input
+			replacement.CoinChange = op.CoinChange
+		case ravencoin.OutputOpType:
+			if _, ok := funders[op.Account.Address]; ok &&
+				op.Amount.Currency.Symbol == s.config.Currency.Symbol {
+				change = len(ops)
+			}
+
+			replacement.Type = s.opTypes.Output
+		default:
+			return nil, wrapErr(
+				ErrUnclearIntent,
+				fmt.Errorf("operation type %s cannot be replaced", op.Type),
+			)
+		}
+
+		ops = append(ops, replacement)
+	}
+
+	if !replaceable {
+		return nil, wrapErr(
+			ErrUnclearIntent,
+			fmt.Errorf("transaction %s does not signal replaceability", txHash),
+		)
+	}
+
+	if change < 0 {
+		return nil, wrapErr(
+			ErrInsufficientFunds,
+			fmt.Errorf("transaction %s has no change output to pay the fee increase", txHash),
+		)
+	}
+
+	changeAmount := ops[change].Amount
+	value, err := strconv.ParseInt(changeAmount.Value, 10, 64)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	if value <= feeIncrease {
+		return nil, wrapErr(
+			ErrInsufficientFunds,
+			fmt.Errorf("change of %d cannot pay a fee increase of %d", value, feeIncrease),
+		)
+	}
+
+	ops[change].Amount = &types.Amount{
+		Value:    strconv.FormatInt(value-feeIncrease, 10),
+		Currency: changeAmount.Currency,
+	}
+
+	return ops, nil
+}
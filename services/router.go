@@ -30,6 +30,7 @@ func NewBlockchainRouter(
 	client Client,
 	i Indexer,
 	asserter *asserter.Asserter,
+	logger Logger,
 ) http.Handler {
 	networkAPIService := NewNetworkAPIService(config, client, i)
 	networkAPIController := server.NewNetworkAPIController(
@@ -49,7 +50,7 @@ func NewBlockchainRouter(
 		asserter,
 	)
 
-	constructionAPIService := NewConstructionAPIService(config, client, i)
+	constructionAPIService := NewConstructionAPIService(config, client, i, logger)
 	constructionAPIController := server.NewConstructionAPIController(
 		constructionAPIService,
 		asserter,
@@ -61,11 +62,13 @@ func NewBlockchainRouter(
 		asserter,
 	)
 
-	return server.NewRouter(
+	router := server.NewRouter(
 		networkAPIController,
 		blockAPIController,
 		accountAPIController,
 		constructionAPIController,
 		mempoolAPIController,
 	)
+
+	return OfflineMiddleware(config, router)
 }
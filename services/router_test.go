@@ -0,0 +1,149 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/asserter"
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockchainRouter_OperationTypes(t *testing.T) {
+	networkIdentifier := &types.NetworkIdentifier{
+		Network:    ravencoin.TestnetNetwork,
+		Blockchain: ravencoin.Blockchain,
+	}
+
+	cfg := &configuration.Configuration{
+		Mode:     configuration.Online,
+		Network:  networkIdentifier,
+		Params:   ravencoin.TestnetParams,
+		Currency: ravencoin.TestnetCurrency,
+		OperationTypes: &configuration.OperationTypes{
+			Input:        "RVN_INPUT",
+			Output:       "RVN_OUTPUT",
+			IssueAsset:   "RVN_ISSUE_ASSET",
+			ReissueAsset: "RVN_REISSUE_ASSET",
+			BurnAsset:    "RVN_BURN_ASSET",
+		},
+	}
+
+	serverAsserter, err := asserter.NewServer(
+		cfg.OperationTypes.Supported(),
+		HistoricalBalanceLookup,
+		[]*types.NetworkIdentifier{networkIdentifier},
+		nil,
+		MempoolCoins,
+	)
+	assert.NoError(t, err)
+
+	router := NewBlockchainRouter(cfg, &mocks.Client{}, &mocks.Indexer{}, serverAsserter, nil)
+
+	post := func(path string, request interface{}) *httptest.ResponseRecorder {
+		body, err := json.Marshal(request)
+		assert.NoError(t, err)
+
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, bytes.NewReader(body)))
+
+		return recorder
+	}
+
+	optionsRecorder := post("/network/options", &types.NetworkRequest{
+		NetworkIdentifier: networkIdentifier,
+	})
+	assert.Equal(t, http.StatusOK, optionsRecorder.Code)
+
+	var options types.NetworkOptionsResponse
+	assert.NoError(t, json.Unmarshal(optionsRecorder.Body.Bytes(), &options))
+	for _, opType := range []string{"RVN_INPUT", "RVN_OUTPUT", "RVN_BURN_ASSET"} {
+		assert.Contains(t, options.Allow.OperationTypes, opType)
+	}
+	assert.Contains(t, options.Allow.OperationTypes, ravencoin.CoinbaseOpType)
+
+	preprocessRecorder := post("/construction/preprocess", &types.ConstructionPreprocessRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: "RVN_INPUT",
+				Account: &types.AccountIdentifier{
+					Address: "tb1qcqzmqzkswhfshzd8kedhmtvgnxax48z4fklhvm",
+				},
+				Amount: &types.Amount{
+					Value:    "-1000000",
+					Currency: ravencoin.TestnetCurrency,
+				},
+				CoinChange: &types.CoinChange{
+					CoinIdentifier: &types.CoinIdentifier{
+						Identifier: "b14157a5c50503c8cd202a173613dd27e0027343c3d50cf85852dd020bf59c7f:1",
+					},
+					CoinAction: types.CoinSpent,
+				},
+			},
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 1,
+				},
+				Type: "RVN_OUTPUT",
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		},
+	})
+	assert.Equal(t, http.StatusOK, preprocessRecorder.Code)
+
+	var preprocess types.ConstructionPreprocessResponse
+	assert.NoError(t, json.Unmarshal(preprocessRecorder.Body.Bytes(), &preprocess))
+	assert.NotNil(t, preprocess.Options)
+
+	// Names outside of the supported set never reach the servicer.
+	unknownRecorder := post("/construction/preprocess", &types.ConstructionPreprocessRequest{
+		NetworkIdentifier: networkIdentifier,
+		Operations: []*types.Operation{
+			{
+				OperationIdentifier: &types.OperationIdentifier{
+					Index: 0,
+				},
+				Type: "UNKNOWN",
+				Account: &types.AccountIdentifier{
+					Address: "tb1q3r8xjf0c2yazxnq9ey3wayelygfjxpfqjvj5v7",
+				},
+				Amount: &types.Amount{
+					Value:    "954843",
+					Currency: ravencoin.TestnetCurrency,
+				},
+			},
+		},
+	})
+	assert.Equal(t, http.StatusInternalServerError, unknownRecorder.Code)
+}
@@ -0,0 +1,180 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+// scriptPubKeyCacheEntry is a cached *ravencoin.ScriptPubKey
+// and the indexed amount of the coin it locks.
+type scriptPubKeyCacheEntry struct {
+	identifier string
+	script     *ravencoin.ScriptPubKey
+	amount     *types.Amount
+	expires    time.Time
+}
+
+// scriptPubKeyCache caches the *ravencoin.ScriptPubKey and
+// amount of each coin populated by the indexer, keyed by coin
+// identifier. Neither changes once a coin is created, so entries
+// are only evicted to bound memory usage (least recently used
+// first) or when they are older than the configured TTL.
+type scriptPubKeyCache struct {
+	size int
+	ttl  time.Duration
+
+	entries map[string]*list.Element
+	order   *list.List
+	lock    sync.Mutex
+
+	// now is overridden in tests.
+	now func() time.Time
+}
+
+// newScriptPubKeyCache returns a new *scriptPubKeyCache.
+func newScriptPubKeyCache(
+	config *configuration.ScriptPubKeyCacheConfiguration,
+) *scriptPubKeyCache {
+	return &scriptPubKeyCache{
+		size:    config.Size,
+		ttl:     config.TTL,
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+		now:     time.Now,
+	}
+}
+
+// get returns the cached *ravencoin.ScriptPubKey and
+// amount of the coin with identifier (if they exist).
+func (c *scriptPubKeyCache) get(identifier string) (*ravencoin.ScriptPubKey, *types.Amount, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	element, ok := c.entries[identifier]
+	if !ok {
+		return nil, nil, false
+	}
+
+	entry := element.Value.(*scriptPubKeyCacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.order.Remove(element)
+		delete(c.entries, identifier)
+		return nil, nil, false
+	}
+
+	c.order.MoveToFront(element)
+	return entry.script, entry.amount, true
+}
+
+// put caches the *ravencoin.ScriptPubKey and amount
+// of the coin with identifier.
+func (c *scriptPubKeyCache) put(
+	identifier string,
+	script *ravencoin.ScriptPubKey,
+	amount *types.Amount,
+) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	expires := c.now().Add(c.ttl)
+	if element, ok := c.entries[identifier]; ok {
+		entry := element.Value.(*scriptPubKeyCacheEntry)
+		entry.script = script
+		entry.amount = amount
+		entry.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[identifier] = c.order.PushFront(&scriptPubKeyCacheEntry{
+		identifier: identifier,
+		script:     script,
+		amount:     amount,
+		expires:    expires,
+	})
+
+	for c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*scriptPubKeyCacheEntry).identifier)
+	}
+}
+
+// GetScriptPubKeys returns the *ravencoin.ScriptPubKey and
+// indexed amount of each coin, only fetching those that are
+// not cached from the indexer. Like Indexer.GetScriptPubKeys,
+// it confirms that the currency provided with each coin is
+// valid.
+func (c *scriptPubKeyCache) GetScriptPubKeys(
+	ctx context.Context,
+	i Indexer,
+	coins []*types.Coin,
+) ([]*ravencoin.ScriptPubKey, []*types.Amount, error) {
+	scripts := make([]*ravencoin.ScriptPubKey, len(coins))
+	amounts := make([]*types.Amount, len(coins))
+	missingIndexes := []int{}
+	missingCoins := []*types.Coin{}
+	for j, coin := range coins {
+		script, amount, ok := c.get(coin.CoinIdentifier.Identifier)
+		if !ok {
+			missingIndexes = append(missingIndexes, j)
+			missingCoins = append(missingCoins, coin)
+			continue
+		}
+
+		if types.Hash(amount.Currency) != types.Hash(coin.Amount.Currency) {
+			return nil, nil, fmt.Errorf(
+				"currency expected %s does not match coin %s",
+				types.PrintStruct(coin.Amount.Currency),
+				types.PrintStruct(amount.Currency),
+			)
+		}
+
+		scripts[j] = script
+		amounts[j] = amount
+	}
+
+	if len(missingCoins) == 0 {
+		return scripts, amounts, nil
+	}
+
+	fetchedScripts, err := i.GetScriptPubKeys(ctx, missingCoins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fetchedAmounts, err := i.GetCoinAmounts(ctx, missingCoins)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for k, script := range fetchedScripts {
+		scripts[missingIndexes[k]] = script
+		amounts[missingIndexes[k]] = fetchedAmounts[k]
+		c.put(missingCoins[k].CoinIdentifier.Identifier, script, fetchedAmounts[k])
+	}
+
+	return scripts, amounts, nil
+}
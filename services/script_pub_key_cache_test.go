@@ -0,0 +1,66 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScriptPubKeyCache(t *testing.T) {
+	cache := newScriptPubKeyCache(&configuration.ScriptPubKeyCacheConfiguration{
+		Size: 2,
+		TTL:  time.Minute,
+	})
+
+	now := time.Unix(1600000000, 0)
+	cache.now = func() time.Time { return now }
+
+	script0 := &ravencoin.ScriptPubKey{Hex: "0014c005b00ad075d30b89a7b65b7dad8899ba6a9c55"}
+	script1 := &ravencoin.ScriptPubKey{Hex: "001488ce6925f8513a234c05c922ee933f2212330520"}
+	script2 := &ravencoin.ScriptPubKey{Hex: "00143a4ac8d4bf5d3fab3fba4b48eb7fbbd0ae0db5ef"}
+
+	amount := &types.Amount{Value: "1000000", Currency: ravencoin.TestnetCurrency}
+
+	cache.put("coin 0", script0, amount)
+	cache.put("coin 1", script1, amount)
+
+	// coin 0 is now the most recently used
+	script, cachedAmount, ok := cache.get("coin 0")
+	assert.True(t, ok)
+	assert.Equal(t, script0, script)
+	assert.Equal(t, amount, cachedAmount)
+
+	// Adding a third coin evicts coin 1
+	cache.put("coin 2", script2, amount)
+	_, _, ok = cache.get("coin 1")
+	assert.False(t, ok)
+
+	script, _, ok = cache.get("coin 2")
+	assert.True(t, ok)
+	assert.Equal(t, script2, script)
+
+	// Entries expire after the TTL
+	now = now.Add(time.Minute)
+	_, _, ok = cache.get("coin 0")
+	assert.False(t, ok)
+	assert.Len(t, cache.entries, 1)
+}
@@ -46,6 +46,26 @@ const (
 	// we typically need the pointer of this
 	// value.
 	MiddlewareVersion = "0.0.9"
+
+	// LegacyAddressType is the change_address_type
+	// that requires change to be sent to a P2PKH
+	// output.
+	LegacyAddressType = "legacy"
+
+	// Bech32AddressType is the change_address_type
+	// that requires change to be sent to a P2WPKH
+	// (witness v0) output.
+	Bech32AddressType = "bech32"
+
+	// ChainDifficultyKey is the key of the difficulty of
+	// the node's best block in the metadata of each peer
+	// reported by /network/status.
+	ChainDifficultyKey = "chain_difficulty"
+
+	// TipStaleStage is the /network/status sync stage
+	// reported when the tip has not advanced for longer
+	// than the configured TipStaleThreshold.
+	TipStaleStage = "tip_stale"
 )
 
 // Client is used by the servicers to get Peer information
@@ -53,8 +73,17 @@ const (
 type Client interface {
 	GetPeers(context.Context) ([]*types.Peer, error)
 	SendRawTransaction(context.Context, string) (string, error)
-	SuggestedFeeRate(context.Context, int64) (float64, error)
-	RawMempool(context.Context) ([]string, error)
+	SuggestedFeeRatePerKB(context.Context, int64) (float64, error)
+	GetRawMempool(context.Context) ([]string, error)
+	GetMempoolEntry(context.Context, string) (*ravencoin.MempoolEntry, error)
+	GetMempoolTransaction(context.Context, string) (*types.Transaction, error)
+	GetBestBlockHash(context.Context) (string, error)
+	GetBlockHeader(context.Context, string) (*ravencoin.BlockHeader, error)
+	GetBlockHeight(context.Context, string) (int64, error)
+	GetHashFromIndex(context.Context, int64) (string, error)
+	GetNodeInfo(context.Context) (*ravencoin.NodeInfo, error)
+	GetBlock(context.Context, string) (*ravencoin.Block, error)
+	GetRawTransaction(context.Context, string) (*ravencoin.Transaction, error)
 }
 
 // Indexer is used by the servicers to get block and account data.
@@ -76,34 +105,235 @@ type Indexer interface {
 		context.Context,
 		[]*types.Coin,
 	) ([]*ravencoin.ScriptPubKey, error)
+	GetCoinAmounts(
+		context.Context,
+		[]*types.Coin,
+	) ([]*types.Amount, error)
 	GetBalance(
 		context.Context,
 		*types.AccountIdentifier,
 		*types.Currency,
 		*types.PartialBlockIdentifier,
 	) (*types.Amount, *types.BlockIdentifier, error)
+	GetAssetBalance(
+		context.Context,
+		*types.AccountIdentifier,
+		string,
+	) (*types.Amount, error)
+	GetAssetCoins(
+		context.Context,
+		*types.AccountIdentifier,
+		string,
+	) ([]*types.Coin, error)
+	GetImmatureCoinbaseCoins(
+		context.Context,
+		*types.AccountIdentifier,
+	) ([]*types.Coin, error)
+	AddressUsed(context.Context, string) (bool, error)
+}
+
+// accountMetadata is returned from /account/balance
+// and /account/coins when there are caveats to the result.
+//
+// SyncStartHeight is populated if the indexer did not
+// sync from genesis (so the result may be incomplete).
+//
+// ImmatureCoinbase is the value of immature coinbase
+// outputs excluded from the result.
+type accountMetadata struct {
+	SyncStartHeight  int64         `json:"sync_start_height,omitempty"`
+	ImmatureCoinbase *types.Amount `json:"immature_coinbase,omitempty"`
+}
+
+// deriveMetadata is the optional metadata
+// accepted by /construction/derive.
+//
+// If WIF is populated, the address is derived
+// from the compressed public key of the WIF-encoded
+// private key instead of the provided public key.
+// The Rosetta API still requires every request to
+// carry a public key, so over HTTP it must be that
+// of the WIF (any other is rejected).
+type deriveMetadata struct {
+	WIF string `json:"wif,omitempty"`
+}
+
+// issueAssetMetadata is the metadata of an
+// ISSUE_ASSET operation. The amount of the
+// operation is in Satoshis regardless of Units.
+type issueAssetMetadata struct {
+	Units      int    `json:"units"`
+	Reissuable bool   `json:"reissuable"`
+	IPFSHash   string `json:"ipfs_hash,omitempty"`
+}
+
+// reissueAssetMetadata is the metadata of a
+// REISSUE_ASSET operation.
+//
+// Quantity is the amount of AssetName added
+// to its supply (in Satoshis). If Units is
+// ravencoin.KeepAssetUnits (-1), the units of
+// the asset are not changed. If IPFSHash is
+// populated, it replaces the IPFS hash attached
+// to the asset.
+type reissueAssetMetadata struct {
+	AssetName  string `json:"asset_name"`
+	Quantity   string `json:"quantity"`
+	Units      int    `json:"units"`
+	Reissuable bool   `json:"reissuable"`
+	IPFSHash   string `json:"ipfs_hash,omitempty"`
+}
+
+// transferAssetMetadata is the optional metadata of
+// an OUTPUT operation transferring an asset.
+//
+// If Message (an IPFS hash) is populated, it is
+// attached to the transfer as a RIP5 message. If
+// ExpireTime (a unix time) is populated, the message
+// expires at that time.
+type transferAssetMetadata struct {
+	Message    string `json:"message,omitempty"`
+	ExpireTime int64  `json:"expire_time,omitempty"`
 }
 
 type unsignedTransaction struct {
-	Transaction    string                  `json:"transaction"`
+	Transaction    string                    `json:"transaction"`
 	ScriptPubKeys  []*ravencoin.ScriptPubKey `json:"scriptPubKeys"`
-	InputAmounts   []string                `json:"input_amounts"`
-	InputAddresses []string                `json:"input_addresses"`
+	InputAmounts   []string                  `json:"input_amounts"`
+	InputAddresses []string                  `json:"input_addresses"`
+
+	Fee         *feeBreakdown `json:"fee,omitempty"`
+	SigHashType string        `json:"sighash_type,omitempty"`
+}
+
+// preprocessMetadata is the optional metadata
+// accepted by /construction/preprocess.
+//
+// If ChangeAddressType is populated, the last
+// output operation is considered to be change
+// and must pay to an address of that type.
+//
+// If AvailableCoins is populated, no input operations
+// should be provided and coins are instead selected
+// to pay for the output operations at FeeRate
+// (in Satoshis per vByte). Each coin must include
+// the address that owns it, as legacy inputs cost
+// more to spend. Immature coinbase coins are never
+// selected.
+//
+// If Replaceable is true, the constructed transaction
+// signals opt-in replace-by-fee (BIP125).
+//
+// If LockTime is non-zero, the constructed transaction
+// cannot be included in a block until the provided
+// block height (or unix time, if >= 500000000).
+//
+// If ShuffleOutputs is true, the RVN outputs of the
+// constructed transaction are not in operation order
+// (so change cannot be identified by its position)
+// but in an order derived from ShuffleNonce.
+type preprocessMetadata struct {
+	ChangeAddressType string                     `json:"change_address_type,omitempty"`
+	AvailableCoins    []*ravencoin.SpendableCoin `json:"available_coins,omitempty"`
+	FeeRate           float64                    `json:"fee_rate,omitempty"`
+	Replaceable       bool                       `json:"replaceable,omitempty"`
+	LockTime          int64                      `json:"locktime,omitempty"`
+	ShuffleOutputs    bool                       `json:"shuffle_outputs,omitempty"`
+	ShuffleNonce      int64                      `json:"shuffle_nonce,omitempty"`
 }
 
 type preprocessOptions struct {
 	Coins         []*types.Coin `json:"coins"`
 	EstimatedSize float64       `json:"estimated_size"`
 	FeeMultiplier *float64      `json:"fee_multiplier,omitempty"`
+	Replaceable   bool          `json:"replaceable,omitempty"`
+	LockTime      int64         `json:"locktime,omitempty"`
+
+	ShuffleOutputs bool  `json:"shuffle_outputs,omitempty"`
+	ShuffleNonce   int64 `json:"shuffle_nonce,omitempty"`
+
+	// Change is only populated when coins were
+	// selected by the server and a change output
+	// must be added by the caller.
+	Change *types.Amount `json:"change,omitempty"`
+
+	// BurnedCoins are the coins (also in Coins)
+	// burned by BURN_ASSET operations.
+	BurnedCoins []*types.Coin `json:"burned_coins,omitempty"`
 }
 
+// constructionMetadata is returned from
+// /construction/metadata.
+//
+// ReplayBlockHeight and ReplayBlockHash identify the
+// block (ReplayProtectionDepth or ConfirmationDepth below
+// the best block) the metadata was fetched against. They
+// are only populated when either depth is configured.
 type constructionMetadata struct {
 	ScriptPubKeys []*ravencoin.ScriptPubKey `json:"script_pub_keys"`
+	Replaceable   bool                      `json:"replaceable,omitempty"`
+	LockTime      int64                     `json:"locktime,omitempty"`
+
+	ShuffleOutputs bool  `json:"shuffle_outputs,omitempty"`
+	ShuffleNonce   int64 `json:"shuffle_nonce,omitempty"`
+
+	ReplayBlockHeight int64  `json:"replay_block_height,omitempty"`
+	ReplayBlockHash   string `json:"replay_block_hash,omitempty"`
+
+	Fee *feeBreakdown `json:"fee,omitempty"`
+
+	// SigHashType is the signature hash type inputs
+	// are signed with (SIGHASH_ALL if not provided).
+	SigHashType string `json:"sighash_type,omitempty"`
 }
 
+// feeBreakdown describes how the fee suggested
+// by /construction/metadata was calculated.
+type feeBreakdown struct {
+	// FeeRate is the fee rate (in Satoshis per kB)
+	// after the fee multiplier and minimum fee rate
+	// are applied.
+	FeeRate int64 `json:"fee_rate"`
+
+	// NodeFeeRate is the fee rate (in Satoshis
+	// per kB) suggested by ravend.
+	NodeFeeRate int64 `json:"node_fee_rate"`
+
+	// EstimatedSize is the estimated size of the
+	// transaction in vbytes.
+	EstimatedSize float64 `json:"estimated_size"`
+
+	// EffectiveFeeRate is the suggested fee divided
+	// by EstimatedSize (in Satoshis per vbyte),
+	// rounded to two decimals.
+	EffectiveFeeRate float64 `json:"effective_fee_rate,omitempty"`
+}
+
+// parseMetadata is returned from ConstructionParse
+// when the transaction has non-default properties.
+type parseMetadata struct {
+	Replaceable bool          `json:"replaceable,omitempty"`
+	LockTime    uint32        `json:"locktime,omitempty"`
+	Fee         *feeBreakdown `json:"fee,omitempty"`
+}
+
+// signedTransaction is the envelope of a signed
+// transaction.
+//
+// ScriptPubKeys are the optional locking scripts of the
+// outputs spent by the inputs of Transaction (as provided
+// to /construction/payloads). They are not populated by
+// /construction/combine, but when provided to
+// /construction/parse they are used to classify inputs
+// instead of inferring the spent outputs from their
+// signature scripts.
 type signedTransaction struct {
-	Transaction  string   `json:"transaction"`
-	InputAmounts []string `json:"input_amounts"`
+	Transaction    string                    `json:"transaction"`
+	InputAmounts   []string                  `json:"input_amounts"`
+	InputAddresses []string                  `json:"input_addresses,omitempty"`
+	ScriptPubKeys  []*ravencoin.ScriptPubKey `json:"scriptPubKeys,omitempty"`
+
+	Fee *feeBreakdown `json:"fee,omitempty"`
 }
 
 // ParseOperationMetadata is returned from
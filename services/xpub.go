@@ -0,0 +1,249 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/coinbase/rosetta-sdk-go/types"
+)
+
+const (
+	// maxGapLimit is the largest gap limit
+	// accepted when scanning an xpub.
+	maxGapLimit = 1000
+
+	// externalChain is the BIP32 child index of
+	// the external (receiving) address chain.
+	externalChain = 0
+
+	// internalChain is the BIP32 child index of
+	// the internal (change) address chain.
+	internalChain = 1
+)
+
+// xpubAccountMetadata is the optional metadata of an
+// AccountIdentifier in /account/balance. If GapLimit is
+// populated, the address is considered to be an xpub.
+type xpubAccountMetadata struct {
+	GapLimit int64 `json:"gap_limit,omitempty"`
+}
+
+// xpubAddress is a used address derived from an xpub.
+type xpubAddress struct {
+	Chain   uint32        `json:"chain"`
+	Index   uint32        `json:"index"`
+	Address string        `json:"address"`
+	Balance *types.Amount `json:"balance"`
+}
+
+// xpubBalanceMetadata is returned from /account/balance
+// when the AccountIdentifier is an xpub.
+type xpubBalanceMetadata struct {
+	Addresses []*xpubAddress `json:"addresses"`
+}
+
+// scanXpub derives P2WPKH addresses on the external and
+// internal chains of xpub until gapLimit consecutive
+// addresses on each chain are unused, returning the used
+// addresses. As in Indexer.ScanAddresses, an address is
+// used if it has any transactions, so a used address may
+// have no balance. All balances are read at the current
+// block when the scan starts, which is returned.
+func (s *AccountAPIService) scanXpub(
+	ctx context.Context,
+	xpub string,
+	gapLimit int64,
+) ([]*xpubAddress, *types.BlockIdentifier, error) {
+	if gapLimit <= 0 || gapLimit > maxGapLimit {
+		return nil, nil, fmt.Errorf("gap limit must be between 1 and %d", maxGapLimit)
+	}
+
+	key, err := hdkeychain.NewKeyFromString(xpub)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to parse xpub", err)
+	}
+
+	if key.IsPrivate() {
+		return nil, nil, errors.New("extended private keys are not supported")
+	}
+
+	params := s.config.Params.BtcdParams()
+	if !key.IsForNet(params) {
+		return nil, nil, fmt.Errorf("xpub is not for %s", params.Name)
+	}
+
+	// Blocks may be indexed during the scan, so
+	// the tip is resolved once.
+	tip, err := s.i.GetBlockLazy(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: unable to get current block", err)
+	}
+
+	block := tip.Block.BlockIdentifier
+	partialBlock := &types.PartialBlockIdentifier{
+		Index: &block.Index,
+		Hash:  &block.Hash,
+	}
+
+	used := []*xpubAddress{}
+	for _, chainIndex := range []uint32{externalChain, internalChain} {
+		chainUsed, err := s.scanXpubChain(ctx, key, chainIndex, gapLimit, partialBlock)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		used = append(used, chainUsed...)
+	}
+
+	return used, block, nil
+}
+
+// scanXpubChain derives P2WPKH addresses on chainIndex
+// of key until gapLimit consecutive addresses are unused,
+// returning the used addresses with their balances at block.
+func (s *AccountAPIService) scanXpubChain(
+	ctx context.Context,
+	key *hdkeychain.ExtendedKey,
+	chainIndex uint32,
+	gapLimit int64,
+	block *types.PartialBlockIdentifier,
+) ([]*xpubAddress, error) {
+	params := s.config.Params.BtcdParams()
+	chain, err := key.Child(chainIndex)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to derive chain %d", err, chainIndex)
+	}
+
+	used := []*xpubAddress{}
+	for index, unused := uint32(0), int64(0); unused < gapLimit; index++ {
+		child, err := chain.Child(index)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to derive child %d/%d", err, chainIndex, index)
+		}
+
+		pubKey, err := child.ECPubKey()
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to get public key of child %d/%d",
+				err,
+				chainIndex,
+				index,
+			)
+		}
+
+		addr, err := btcutil.NewAddressWitnessPubKeyHash(
+			btcutil.Hash160(pubKey.SerializeCompressed()),
+			params,
+		)
+		if err != nil {
+			return nil, fmt.Errorf(
+				"%w: unable to derive address of child %d/%d",
+				err,
+				chainIndex,
+				index,
+			)
+		}
+
+		amount, _, err := s.i.GetBalance(
+			ctx,
+			&types.AccountIdentifier{Address: addr.EncodeAddress()},
+			s.config.Currency,
+			block,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%w: unable to get balance of %s", err, addr.EncodeAddress())
+		}
+
+		isUsed, err := s.i.AddressUsed(ctx, addr.EncodeAddress())
+		if err != nil {
+			return nil, err
+		}
+
+		if !isUsed {
+			unused++
+			continue
+		}
+
+		unused = 0
+		used = append(used, &xpubAddress{
+			Chain:   chainIndex,
+			Index:   index,
+			Address: addr.EncodeAddress(),
+			Balance: amount,
+		})
+	}
+
+	return used, nil
+}
+
+// xpubBalance returns the total balance of the used
+// addresses of an xpub (and the addresses in metadata).
+func (s *AccountAPIService) xpubBalance(
+	ctx context.Context,
+	request *types.AccountBalanceRequest,
+	gapLimit int64,
+) (*types.AccountBalanceResponse, *types.Error) {
+	if request.BlockIdentifier != nil {
+		return nil, wrapErr(
+			ErrUnableToGetBalance,
+			errors.New("historical balance of an xpub is not supported"),
+		)
+	}
+
+	for _, currency := range request.Currencies {
+		if currency.Symbol != s.config.Currency.Symbol {
+			return nil, wrapErr(
+				ErrUnableToGetBalance,
+				fmt.Errorf("balance of asset %s for an xpub is not supported", currency.Symbol),
+			)
+		}
+	}
+
+	addresses, block, err := s.scanXpub(ctx, request.AccountIdentifier.Address, gapLimit)
+	if err != nil {
+		return nil, wrapErr(ErrUnableToGetBalance, err)
+	}
+
+	total := "0"
+	for _, address := range addresses {
+		total, err = types.AddValues(total, address.Balance.Value)
+		if err != nil {
+			return nil, wrapErr(ErrUnableToGetBalance, err)
+		}
+	}
+
+	metadata, err := types.MarshalMap(&xpubBalanceMetadata{
+		Addresses: addresses,
+	})
+	if err != nil {
+		return nil, wrapErr(ErrUnableToParseIntermediateResult, err)
+	}
+
+	return &types.AccountBalanceResponse{
+		BlockIdentifier: block,
+		Balances: []*types.Amount{
+			{
+				Value:    total,
+				Currency: s.config.Currency,
+			},
+		},
+		Metadata: metadata,
+	}, nil
+}
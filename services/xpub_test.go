@@ -0,0 +1,214 @@
+// Copyright 2020 Coinbase, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/RavenProject/rosetta-ravencoin/configuration"
+	mocks "github.com/RavenProject/rosetta-ravencoin/mocks/services"
+	"github.com/RavenProject/rosetta-ravencoin/ravencoin"
+
+	"github.com/coinbase/rosetta-sdk-go/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountBalance_Online_Xpub(t *testing.T) {
+	xpub := "tpubD6NzVbkrYhZ4XgiXtGrdW5XDAPFCL9h7we1vwNCpn8tGbBcgfVYjXyhWo4E1xkh56hjod1RhGjxbaTLV3X4FyWuejifB9jusQ46QzG87VKp" // nolint
+	addresses := map[uint32][]string{
+		externalChain: {
+			"tb1qp5wfcq48h6d63wyy9qz0awtpfqwwv4smhppgv3",
+			"tb1qrfxr69jqnhwufxgkqgcdep9prq4j4vuwqglm5l",
+			"tb1qhvd6suvqzjcu9pxjhrwhtrlj85ny3n2m2xy84x",
+			"tb1qjzgwzugce3mqfvn2cdq8wt8drz50mf6jnr8crv",
+			"tb1q4lrflkd0sddm4ktujw8e5syxmlwcdprdpyhwqj",
+			"tb1qc6xkeyekth5xe7lsey3qgxm55nypxe7dr8jw40",
+			"tb1qmenjwqyj4kvfftjum5wlakxv4aqglf30p4k5jm",
+			"tb1qkq5g90a6f7etapm5el6umchkdpdm77vut5hyqn",
+			"tb1qhc6qe9fgw0f7dt4wnmu6zrfhupgmaqs3gvupk7",
+		},
+		internalChain: {
+			"tb1q7zwtzcqsm3k43ha0ac7nl8cz0hqrhckyy0prza",
+			"tb1qf7x2v0de6hvgv6tke54pyzmkc9022wh5chjfl8",
+			"tb1qa3ht4xx9evh8dp8p66tzftu45zccugw444rfgq",
+			"tb1q4cunrvqcccqtn39tm8lr7ezvuurvq92n4lqd3u",
+			"tb1qaer3qp5y40x8xztq6qegnewxkmn3v6a9umzqeg",
+		},
+	}
+
+	// External addresses 0, 2 and 5 and internal (change)
+	// address 1 have transactions. All coins of external
+	// address 2 have been spent.
+	balances := map[uint32]map[int]string{
+		externalChain: {
+			0: "100000",
+			2: "0",
+			5: "250000",
+		},
+		internalChain: {
+			1: "50000",
+		},
+	}
+
+	block := &types.BlockIdentifier{
+		Index: 1000,
+		Hash:  "block 1000",
+	}
+	partialBlock := &types.PartialBlockIdentifier{
+		Index: &block.Index,
+		Hash:  &block.Hash,
+	}
+
+	tests := map[string]struct {
+		gapLimit int64
+
+		// scanned is the number of addresses on each
+		// chain scanned before reaching the gap limit.
+		scanned   map[uint32]int
+		used      map[uint32][]int
+		balance   string
+		errorCode int32
+	}{
+		"gap limit before second address": {
+			gapLimit: 2,
+			scanned: map[uint32]int{
+				externalChain: 5,
+				internalChain: 4,
+			},
+			used: map[uint32][]int{
+				externalChain: {0, 2},
+				internalChain: {1},
+			},
+			balance: "150000",
+		},
+		"gap limit spans second address": {
+			gapLimit: 3,
+			scanned: map[uint32]int{
+				externalChain: 9,
+				internalChain: 5,
+			},
+			used: map[uint32][]int{
+				externalChain: {0, 2, 5},
+				internalChain: {1},
+			},
+			balance: "400000",
+		},
+		"invalid gap limit": {
+			gapLimit:  maxGapLimit + 1,
+			errorCode: ErrUnableToGetBalance.Code,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := &configuration.Configuration{
+				Mode:     configuration.Online,
+				Params:   ravencoin.TestnetParams,
+				Currency: ravencoin.TestnetCurrency,
+			}
+			mockIndexer := &mocks.Indexer{}
+			servicer := NewAccountAPIService(cfg, mockIndexer)
+			ctx := context.Background()
+
+			if test.errorCode == 0 {
+				mockIndexer.On(
+					"GetBlockLazy",
+					ctx,
+					(*types.PartialBlockIdentifier)(nil),
+				).Return(
+					&types.BlockResponse{
+						Block: &types.Block{
+							BlockIdentifier: block,
+						},
+					},
+					nil,
+				).Once()
+			}
+
+			for chain, scanned := range test.scanned {
+				for i := 0; i < scanned; i++ {
+					value, used := balances[chain][i]
+					if !used {
+						value = "0"
+					}
+
+					address := addresses[chain][i]
+					mockIndexer.On(
+						"GetBalance",
+						ctx,
+						&types.AccountIdentifier{Address: address},
+						ravencoin.TestnetCurrency,
+						partialBlock,
+					).Return(
+						&types.Amount{
+							Value:    value,
+							Currency: ravencoin.TestnetCurrency,
+						},
+						block,
+						nil,
+					).Once()
+					mockIndexer.On("AddressUsed", ctx, address).Return(used, nil).Once()
+				}
+			}
+
+			bal, err := servicer.AccountBalance(ctx, &types.AccountBalanceRequest{
+				AccountIdentifier: &types.AccountIdentifier{
+					Address: xpub,
+					Metadata: map[string]interface{}{
+						"gap_limit": test.gapLimit,
+					},
+				},
+			})
+			if test.errorCode != 0 {
+				assert.Nil(t, bal)
+				assert.Equal(t, test.errorCode, err.Code)
+				mockIndexer.AssertExpectations(t)
+				return
+			}
+
+			assert.Nil(t, err)
+
+			used := []*xpubAddress{}
+			for _, chain := range []uint32{externalChain, internalChain} {
+				for _, index := range test.used[chain] {
+					used = append(used, &xpubAddress{
+						Chain:   chain,
+						Index:   uint32(index),
+						Address: addresses[chain][index],
+						Balance: &types.Amount{
+							Value:    balances[chain][index],
+							Currency: ravencoin.TestnetCurrency,
+						},
+					})
+				}
+			}
+
+			assert.Equal(t, &types.AccountBalanceResponse{
+				BlockIdentifier: block,
+				Balances: []*types.Amount{
+					{
+						Value:    test.balance,
+						Currency: ravencoin.TestnetCurrency,
+					},
+				},
+				Metadata: forceMarshalMap(t, &xpubBalanceMetadata{
+					Addresses: used,
+				}),
+			}, bal)
+			mockIndexer.AssertExpectations(t)
+		})
+	}
+}